@@ -0,0 +1,194 @@
+package public
+
+import (
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Public job board: read-only listing of approved, open jobs for visitors who haven't signed up yet
+
+// publicCacheMaxAge is how long clients and proxies may cache public responses
+const publicCacheMaxAge = 60 * time.Second
+
+// Job is the restricted set of job fields exposed without authentication
+type Job struct {
+	ID                  string  `json:"job_id"`
+	Title               string  `json:"title"`
+	CompanyID           *int    `json:"company_id"`
+	CompanyName         *string `json:"company_name"`
+	CompanyVerified     *bool   `json:"company_verified"`
+	JobCategory         string  `json:"job_category"`
+	JobType             string  `json:"job_type"`
+	JobLevel            string  `json:"job_level"`
+	MinSalary           float64 `json:"min_salary"`
+	MaxSalary           float64 `json:"max_salary"`
+	MinExperience       int     `json:"min_experience"`
+	MaxExperience       int     `json:"max_experience"`
+	Location            string  `json:"location"`
+	JobDescription      string  `json:"job_description"`
+	JobResponsibility   string  `json:"job_responsibility"`
+	Qualification       string  `json:"qualification"`
+	Benefits            string  `json:"benefits"`
+	SkillsRequired      string  `json:"skills_required"`
+	ApplicationDeadline string  `json:"application_deadline"`
+	PostedAt            string  `json:"posted_at"`
+}
+
+// jobColumns selects the public fields plus updated_at as a Unix time, which is used for Last-Modified
+const jobColumns = "j.job_id, j.title, j.company_id, co.name, co.verified, j.job_category, j.job_type, j.job_level, " +
+	"j.min_salary, j.max_salary, j.min_experience, j.max_experience, j.location, j.job_description, " +
+	"j.job_responsibility, j.qualification, j.benefits, j.skills_required, j.application_deadline, j.created_at, " +
+	"UNIX_TIMESTAMP(j.updated_at) FROM jobs j LEFT JOIN companies co ON co.company_id = j.company_id"
+
+// RateLimitMiddleware limits anonymous clients to PUBLIC_RATE_LIMIT requests per minute (default 60)
+func RateLimitMiddleware() gin.HandlerFunc {
+	limit := 60
+	if value := os.Getenv("PUBLIC_RATE_LIMIT"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			limit = parsed
+		} else {
+			log.Printf("Warning: invalid PUBLIC_RATE_LIMIT %q, using %d", value, limit)
+		}
+	}
+	return services.RateLimitMiddleware(limit, time.Minute)
+}
+
+// JobViews retrieves publicly visible jobs or a specific one by ID, with HTTP caching headers
+func JobViews(c *gin.Context) {
+	jobID := c.Param("job-id")
+	log.Printf("Received public request to view jobs. Job ID: %s", jobID)
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	// Public reads follow the same visibility policy as fresh grads
+	query := "SELECT " + jobColumns + " WHERE " + services.VisibleJobCondition("j", time.Now())
+	var args []interface{}
+
+	if jobID != "" {
+		query += " AND j.job_id = ?"
+		args = append(args, jobID)
+	} else {
+		// Append filters conditionally
+		if title := c.Query("q"); title != "" {
+			query += " AND j.title LIKE ?"
+			args = append(args, "%"+title+"%")
+		}
+		if jobType := c.Query("job_type"); jobType != "" {
+			query += " AND j.job_type = ?"
+			args = append(args, jobType)
+		}
+		if jobCategory := c.Query("job_category"); jobCategory != "" {
+			query += " AND j.job_category = ?"
+			args = append(args, jobCategory)
+		}
+		if jobLevel := c.Query("job_level"); jobLevel != "" {
+			query += " AND j.job_level = ?"
+			args = append(args, jobLevel)
+		}
+		if location := c.Query("location"); location != "" {
+			query += " AND j.location = ?"
+			args = append(args, location)
+		}
+		if minSalary := c.Query("min_salary"); minSalary != "" {
+			query += " AND j.min_salary >= ?"
+			args = append(args, minSalary)
+		}
+		if maxSalary := c.Query("max_salary"); maxSalary != "" {
+			query += " AND j.max_salary <= ?"
+			args = append(args, maxSalary)
+		}
+
+		// Pagination, capped so anonymous clients can't pull the whole table at once
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+		if err != nil || limit <= 0 || limit > 100 {
+			limit = 20
+		}
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+		query += " ORDER BY j.created_at DESC, j.job_id DESC LIMIT ? OFFSET ?"
+		args = append(args, limit, offset)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Printf("Query execution error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	defer rows.Close()
+
+	jobs := []Job{}
+	var lastModified time.Time
+	for rows.Next() {
+		var job Job
+		var updatedAt int64
+		if err := rows.Scan(
+			&job.ID, &job.Title, &job.CompanyID, &job.CompanyName, &job.CompanyVerified, &job.JobCategory,
+			&job.JobType, &job.JobLevel, &job.MinSalary, &job.MaxSalary, &job.MinExperience, &job.MaxExperience,
+			&job.Location, &job.JobDescription, &job.JobResponsibility, &job.Qualification, &job.Benefits,
+			&job.SkillsRequired, &job.ApplicationDeadline, &job.PostedAt, &updatedAt,
+		); err != nil {
+			log.Printf("Row scan error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
+			return
+		}
+		if t := time.Unix(updatedAt, 0); t.After(lastModified) {
+			lastModified = t
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Rows error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error processing rows"})
+		return
+	}
+
+	if jobID != "" {
+		if len(jobs) == 0 {
+			log.Printf("Public job not found: %s", jobID)
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Job not found"})
+			return
+		}
+		services.WriteCachedJSON(c, lastModified, publicCacheMaxAge, gin.H{"status": "success", "data": jobs[0]})
+		return
+	}
+
+	// A list changes when a job leaves it without any listed job being updated, so it is only validated by
+	// its ETag, which hashes the listed jobs, and not by the latest updated_at
+	services.WriteCachedJSON(c, time.Time{}, publicCacheMaxAge, gin.H{"status": "success", "data": jobs})
+}
+
+// SkillViews lists the skills catalogue with aliases so clients can offer skill pickers, optionally filtered by q
+func SkillViews(c *gin.Context) {
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	skills, err := services.ListSkills(db, c.Query("q"))
+	if err != nil {
+		log.Printf("Error listing skills: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": skills})
+}
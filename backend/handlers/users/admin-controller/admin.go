@@ -2,6 +2,7 @@ package admin
 
 import (
 	"database/sql"
+	"fmt"
 	services "fresh-grad-jobs/services"
 	"log"
 	"net/http"
@@ -39,6 +40,16 @@ import (
 
 // TODO: Analytics dashboard ❌
 // แดชบอร์ดวิเคราะห์ข้อมูล เช่น การดูสถิติจำนวนประกาศงาน, การใช้งานของผู้ใช้
+// มีสถิติการยื่นข้อเสนองานและการจ้างงานแล้วที่ GET /admin/analytics/hires
+
+// TODO: Audit log ✅
+// บันทึกประวัติการเปลี่ยนแปลงของผู้ดูแลระบบและนายจ้าง เพื่อตรวจสอบย้อนหลังว่าใครทำอะไร
+
+// TODO: Skills catalogue ✅
+// จัดการรายการทักษะมาตรฐาน พร้อมชื่อเรียกอื่นและชื่อภาษาไทย เพื่อใช้จับคู่งานกับผู้สมัคร
+
+// TODO: Email notifications ✅
+// ส่งอีเมลแจ้งเตือนเมื่ออนุมัติผู้ใช้ อนุมัติประกาศงาน และมีใบสมัครใหม่ เป็นภาษาไทยหรืออังกฤษตามที่ผู้ใช้ตั้งค่าไว้
 
 // AuthMiddleware checks for admin role
 func AuthMiddleware() gin.HandlerFunc {
@@ -73,6 +84,10 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		log.Printf("Admin access granted for user with role '%s'", role)
+
+		// Store admin_id in context for audit logging
+		c.Set("admin_id", jwtClaims.ID)
+
 		c.Next()
 	}
 }
@@ -125,9 +140,29 @@ func UserApprove(c *gin.Context) {
 		return
 	}
 
-	// Perform approval logic - update the approved status
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	// Perform approval logic - update the approved status, record it in the audit log and email the user
 	updateQuery := "UPDATE users SET approved = ? WHERE user_id = ?"
-	_, err = db.Exec(updateQuery, true, userID)
+	entry := services.NewAuditEntry(c, c.MustGet("admin_id"), "admin", "user.approve", "user", userID)
+	err = services.ExecWithAuditTx(tx, entry, services.UserSnapshotQuery, updateQuery, true, userID)
+	var recipientID int
+	var email string
+	if err == nil {
+		err = tx.QueryRow("SELECT user_id, email FROM users WHERE user_id = ?", userID).Scan(&recipientID, &email)
+	}
+	if err == nil {
+		err = services.EnqueueEmail(tx, recipientID, services.EmailTemplateUserApproved, map[string]interface{}{"Email": email})
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
 	if err != nil {
 		log.Printf("Error updating user approval status: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -192,9 +227,10 @@ func UserSuspend(c *gin.Context) {
 		return
 	}
 
-	// Update suspension status
+	// Update suspension status and record it in the audit log
 	updateQuery := "UPDATE users SET suspended = ? WHERE user_id = ?"
-	_, err = db.Exec(updateQuery, true, userID)
+	entry := services.NewAuditEntry(c, c.MustGet("admin_id"), "admin", "user.suspend", "user", userID)
+	err = services.ExecWithAudit(db, entry, services.UserSnapshotQuery, updateQuery, true, userID)
 	if err != nil {
 		log.Printf("Error updating suspension status for user %s: %v", userID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -250,9 +286,10 @@ func UserDelete(c *gin.Context) {
 		return
 	}
 
-	// Perform deletion logic
+	// Perform deletion logic and record it in the audit log
 	deleteQuery := "DELETE FROM users WHERE user_id = ?"
-	_, err = db.Exec(deleteQuery, userID)
+	entry := services.NewAuditEntry(c, c.MustGet("admin_id"), "admin", "user.delete", "user", userID)
+	err = services.ExecWithAudit(db, entry, services.UserSnapshotQuery, deleteQuery, userID)
 	if err != nil {
 		log.Printf("Error deleting user: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -458,8 +495,34 @@ func JobApprove(c *gin.Context) {
 		return
 	}
 
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	// Approve the job, record it in the audit log and let the employer know in the app and by email
 	updateQuery := "UPDATE jobs SET approved = ? WHERE job_id = ?"
-	_, err = db.Exec(updateQuery, true, jobID)
+	entry := services.NewAuditEntry(c, c.MustGet("admin_id"), "admin", "job.approve", "job", jobID)
+	err = services.ExecWithAuditTx(tx, entry, services.JobSnapshotQuery, updateQuery, true, jobID)
+	var employerID int
+	var title string
+	if err == nil {
+		err = tx.QueryRow("SELECT employer_id, title FROM jobs WHERE job_id = ?", jobID).Scan(&employerID, &title)
+	}
+	if err == nil {
+		message := fmt.Sprintf("Your job \"%s\" has been approved", title)
+		err = services.CreateNotification(tx, employerID, "job.approved", message, map[string]interface{}{"job_id": jobID})
+	}
+	if err == nil {
+		data := map[string]interface{}{"JobID": jobID, "JobTitle": title}
+		err = services.EnqueueEmail(tx, employerID, services.EmailTemplateJobApproved, data)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
 	if err != nil {
 		log.Printf("Error updating job approval status: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -515,8 +578,10 @@ func JobDelete(c *gin.Context) {
 		return
 	}
 
+	// Delete the job and record it in the audit log
 	deleteQuery := "DELETE FROM jobs WHERE job_id = ?"
-	_, err = db.Exec(deleteQuery, jobID)
+	entry := services.NewAuditEntry(c, c.MustGet("admin_id"), "admin", "job.delete", "job", jobID)
+	err = services.ExecWithAudit(db, entry, services.JobSnapshotQuery, deleteQuery, jobID)
 	if err != nil {
 		log.Printf("Error deleting job: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -572,8 +637,16 @@ func JobViews(c *gin.Context) {
 		JobStatus           string  `json:"job_status"`
 		SkillsRequired      string  `json:"skills_required"`
 		JobLevel            string  `json:"job_level"`
+		CompanyID           *int    `json:"company_id"`
+		Positions           int     `json:"positions"`
+		PositionsFilled     int     `json:"positions_filled"`
 	}
 
+	// Explicit column list so schema additions don't break the scan below
+	jobColumns := "job_id, title, employer_id, job_category, job_type, min_salary, max_salary, min_experience, " +
+		"max_experience, job_responsibility, qualification, benefits, job_description, approved, created_at, " +
+		"location, posted_by, application_deadline, job_status, skills_required, job_level, company_id, positions, positions_filled"
+
 	// Filters
 	jobType := c.Query("job_type")             // Filter by job type
 	jobCategory := c.Query("job_category")     // Filter by job category
@@ -590,7 +663,7 @@ func JobViews(c *gin.Context) {
 	var args []interface{}
 
 	if jobID == "" {
-		query = "SELECT * FROM jobs WHERE 1=1" // Base query
+		query = "SELECT " + jobColumns + " FROM jobs WHERE 1=1" // Base query
 
 		// Add filters to the query
 		if jobType != "" {
@@ -651,7 +724,7 @@ func JobViews(c *gin.Context) {
 
 		for rows.Next() {
 			var job Job
-			if err := rows.Scan(&job.ID, &job.Title, &job.EmployerID, &job.JobCategory, &job.JobType, &job.MinSalary, &job.MaxSalary, &job.MinExperience, &job.MaxExperience, &job.JobResponsibility, &job.Qualification, &job.Benefits, &job.JobDescription, &job.Approved, &job.CreatedAt, &job.Location, &job.PostedBy, &job.ApplicationDeadline, &job.JobStatus, &job.SkillsRequired, &job.JobLevel); err != nil {
+			if err := rows.Scan(&job.ID, &job.Title, &job.EmployerID, &job.JobCategory, &job.JobType, &job.MinSalary, &job.MaxSalary, &job.MinExperience, &job.MaxExperience, &job.JobResponsibility, &job.Qualification, &job.Benefits, &job.JobDescription, &job.Approved, &job.CreatedAt, &job.Location, &job.PostedBy, &job.ApplicationDeadline, &job.JobStatus, &job.SkillsRequired, &job.JobLevel, &job.CompanyID, &job.Positions, &job.PositionsFilled); err != nil {
 				log.Printf("Row scan error: %v", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
 				return
@@ -667,11 +740,11 @@ func JobViews(c *gin.Context) {
 
 	} else {
 		// If a specific job ID is provided, retrieve the job by ID
-		query := "SELECT * FROM jobs WHERE job_id = ?"
+		query := "SELECT " + jobColumns + " FROM jobs WHERE job_id = ?"
 		row := db.QueryRow(query, jobID)
 
 		var job Job
-		if err := row.Scan(&job.ID, &job.Title, &job.EmployerID, &job.JobCategory, &job.JobType, &job.MinSalary, &job.MaxSalary, &job.MinExperience, &job.MaxExperience, &job.JobResponsibility, &job.Qualification, &job.Benefits, &job.JobDescription, &job.Approved, &job.CreatedAt, &job.Location, &job.PostedBy, &job.ApplicationDeadline, &job.JobStatus, &job.SkillsRequired, &job.JobLevel); err != nil {
+		if err := row.Scan(&job.ID, &job.Title, &job.EmployerID, &job.JobCategory, &job.JobType, &job.MinSalary, &job.MaxSalary, &job.MinExperience, &job.MaxExperience, &job.JobResponsibility, &job.Qualification, &job.Benefits, &job.JobDescription, &job.Approved, &job.CreatedAt, &job.Location, &job.PostedBy, &job.ApplicationDeadline, &job.JobStatus, &job.SkillsRequired, &job.JobLevel, &job.CompanyID, &job.Positions, &job.PositionsFilled); err != nil {
 			if err == sql.ErrNoRows {
 				log.Printf("Job not found: %s", jobID)
 				c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Job not found"})
@@ -0,0 +1,53 @@
+package admin
+
+import (
+	services "fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HireAnalyticsView summarizes offers and hires, optionally between from and to (YYYY-MM-DD)
+func HireAnalyticsView(c *gin.Context) {
+	from := c.Query("from")
+	to := c.Query("to")
+
+	fieldErrors := services.FieldErrors{}
+	for param, value := range map[string]string{"from": from, "to": to} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			fieldErrors[param] = "must be a date in YYYY-MM-DD format"
+		}
+	}
+	if len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": fieldErrors})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Database connection error",
+		})
+		return
+	}
+	defer db.Close()
+
+	analytics, err := services.LoadHireAnalytics(db, from, to)
+	if err != nil {
+		log.Printf("Error loading hire analytics: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Query execution error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": analytics})
+}
@@ -0,0 +1,142 @@
+package admin
+
+import (
+	"encoding/json"
+	services "fresh-grad-jobs/services"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditViews retrieves audit log entries with optional filters and returns them as JSON
+func AuditViews(c *gin.Context) {
+	// Filters
+	actorID := c.Query("actor_id")             // Filter by the user who made the change
+	actorRole := c.Query("actor_role")         // Filter by actor role (admin, employer)
+	action := c.Query("action")                // Filter by action, e.g. job.delete
+	targetType := c.Query("target_type")       // Filter by target type, e.g. job or user
+	targetID := c.Query("target_id")           // Filter by target ID
+	requestID := c.Query("request_id")         // Filter by request ID
+	createdAfter := c.Query("created_after")   // Filter by entries created after a certain date
+	createdBefore := c.Query("created_before") // Filter by entries created before a certain date
+
+	limit := c.DefaultQuery("limit", "50")  // Pagination limit (default to 50)
+	offset := c.DefaultQuery("offset", "0") // Pagination offset (default to 0)
+
+	log.Printf("Retrieving audit logs. Action: %s, Target: %s/%s", action, targetType, targetID)
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Database connection error",
+		})
+		return
+	}
+	defer db.Close()
+
+	query := "SELECT audit_id, actor_id, actor_role, action, target_type, target_id, before_data, after_data, " +
+		"request_id, ip_address, created_at FROM audit_logs WHERE 1=1"
+	var args []interface{}
+
+	if actorID != "" {
+		query += " AND actor_id = ?"
+		args = append(args, actorID)
+	}
+	if actorRole != "" {
+		query += " AND actor_role = ?"
+		args = append(args, actorRole)
+	}
+	if action != "" {
+		query += " AND action = ?"
+		args = append(args, action)
+	}
+	if targetType != "" {
+		query += " AND target_type = ?"
+		args = append(args, targetType)
+	}
+	if targetID != "" {
+		query += " AND target_id = ?"
+		args = append(args, targetID)
+	}
+	if requestID != "" {
+		query += " AND request_id = ?"
+		args = append(args, requestID)
+	}
+	if createdAfter != "" {
+		query += " AND created_at >= ?"
+		args = append(args, createdAfter)
+	}
+	if createdBefore != "" {
+		query += " AND created_at <= ?"
+		args = append(args, createdBefore)
+	}
+
+	// Newest entries first, with pagination
+	query += " ORDER BY created_at DESC, audit_id DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Printf("Database query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Database query error",
+		})
+		return
+	}
+	defer rows.Close()
+
+	type AuditLog struct {
+		ID         int64           `json:"audit_id"`
+		ActorID    int             `json:"actor_id"`
+		ActorRole  string          `json:"actor_role"`
+		Action     string          `json:"action"`
+		TargetType string          `json:"target_type"`
+		TargetID   string          `json:"target_id"`
+		Before     json.RawMessage `json:"before"`
+		After      json.RawMessage `json:"after"`
+		RequestID  string          `json:"request_id"`
+		IP         string          `json:"ip_address"`
+		CreatedAt  string          `json:"created_at"`
+	}
+
+	var logs []AuditLog
+	for rows.Next() {
+		var entry AuditLog
+		var before, after []byte
+		if err := rows.Scan(&entry.ID, &entry.ActorID, &entry.ActorRole, &entry.Action, &entry.TargetType, &entry.TargetID,
+			&before, &after, &entry.RequestID, &entry.IP, &entry.CreatedAt); err != nil {
+			log.Printf("Error processing audit log data: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": "Error processing audit log data",
+			})
+			return
+		}
+		if before != nil {
+			entry.Before = json.RawMessage(before)
+		}
+		if after != nil {
+			entry.After = json.RawMessage(after)
+		}
+		logs = append(logs, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Rows error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Error processing audit log data",
+		})
+		return
+	}
+
+	log.Printf("Retrieved %d audit log entries", len(logs))
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   logs,
+	})
+}
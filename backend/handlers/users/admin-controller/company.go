@@ -0,0 +1,199 @@
+package admin
+
+import (
+	"database/sql"
+	services "fresh-grad-jobs/services"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CompanyVerify grants the verification badge to a company by ID
+func CompanyVerify(c *gin.Context) {
+	setCompanyVerified(c, true)
+}
+
+// CompanyUnverify revokes the verification badge of a company by ID
+func CompanyUnverify(c *gin.Context) {
+	setCompanyVerified(c, false)
+}
+
+// setCompanyVerified updates a company's verification badge and records it in the audit log
+func setCompanyVerified(c *gin.Context, verified bool) {
+	companyID := c.Param("company-id")
+	log.Printf("Attempting to set verified=%v for company with ID: %s", verified, companyID)
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Database connection error",
+		})
+		return
+	}
+	defer db.Close()
+
+	var isVerified bool
+	err = db.QueryRow("SELECT verified FROM companies WHERE company_id = ?", companyID).Scan(&isVerified)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("Company not found: %s", companyID)
+			c.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "Company not found",
+			})
+			return
+		}
+		log.Printf("Database query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Database query error",
+		})
+		return
+	}
+
+	if isVerified == verified {
+		log.Printf("Company %s already has verified=%v", companyID, verified)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Company verification status is unchanged",
+		})
+		return
+	}
+
+	adminID := c.MustGet("admin_id")
+	action := "company.verify"
+	updateQuery := "UPDATE companies SET verified = TRUE, verified_at = CURRENT_TIMESTAMP, verified_by = ? WHERE company_id = ?"
+	args := []interface{}{adminID, companyID}
+	if !verified {
+		action = "company.unverify"
+		updateQuery = "UPDATE companies SET verified = FALSE, verified_at = NULL, verified_by = NULL WHERE company_id = ?"
+		args = []interface{}{companyID}
+	}
+
+	entry := services.NewAuditEntry(c, adminID, "admin", action, "company", companyID)
+	if err := services.ExecWithAudit(db, entry, services.CompanySnapshotQuery, updateQuery, args...); err != nil {
+		log.Printf("Error updating company verification status: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Error updating company verification status",
+		})
+		return
+	}
+
+	log.Printf("Company %s verified=%v successfully", companyID, verified)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Company verification status updated successfully",
+	})
+}
+
+// CompanyViews retrieves all companies or a specific company by ID and returns them as JSON
+func CompanyViews(c *gin.Context) {
+	companyID := c.Param("company-id")
+
+	// Filters
+	nameFilter := c.Query("name")         // Optional partial name filter
+	verifiedFilter := c.Query("verified") // Optional verification status filter
+	industry := c.Query("industry")       // Optional industry filter
+
+	limit := c.DefaultQuery("limit", "10")  // Pagination limit (default to 10)
+	offset := c.DefaultQuery("offset", "0") // Pagination offset (default to 0)
+
+	log.Printf("Retrieving companies. CompanyID: %s", companyID)
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Database connection error",
+		})
+		return
+	}
+	defer db.Close()
+
+	query := "SELECT company_id, owner_id, name, logo_url, industry, company_size, website, description, address, " +
+		"verified, verified_at, verified_by, created_at FROM companies WHERE 1=1"
+	var args []interface{}
+
+	if companyID != "" {
+		query += " AND company_id = ?"
+		args = append(args, companyID)
+	}
+	if nameFilter != "" {
+		query += " AND name LIKE ?"
+		args = append(args, "%"+nameFilter+"%")
+	}
+	if verifiedFilter != "" {
+		query += " AND verified = ?"
+		args = append(args, verifiedFilter == "true")
+	}
+	if industry != "" {
+		query += " AND industry = ?"
+		args = append(args, industry)
+	}
+
+	query += " LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Printf("Database query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Database query error",
+		})
+		return
+	}
+	defer rows.Close()
+
+	type Company struct {
+		ID          int     `json:"company_id"`
+		OwnerID     int     `json:"owner_id"`
+		Name        string  `json:"name"`
+		LogoURL     string  `json:"logo_url"`
+		Industry    string  `json:"industry"`
+		CompanySize string  `json:"company_size"`
+		Website     string  `json:"website"`
+		Description string  `json:"description"`
+		Address     string  `json:"address"`
+		Verified    bool    `json:"verified"`
+		VerifiedAt  *string `json:"verified_at"`
+		VerifiedBy  *int    `json:"verified_by"`
+		CreatedAt   string  `json:"created_at"`
+	}
+
+	var companies []Company
+	for rows.Next() {
+		var company Company
+		if err := rows.Scan(&company.ID, &company.OwnerID, &company.Name, &company.LogoURL, &company.Industry,
+			&company.CompanySize, &company.Website, &company.Description, &company.Address, &company.Verified,
+			&company.VerifiedAt, &company.VerifiedBy, &company.CreatedAt); err != nil {
+			log.Printf("Error processing company data: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": "Error processing company data",
+			})
+			return
+		}
+		companies = append(companies, company)
+	}
+
+	if len(companies) == 0 && companyID != "" {
+		log.Printf("Company not found: %s", companyID)
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Company not found",
+		})
+		return
+	}
+
+	log.Printf("Retrieved %d companies", len(companies))
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   companies,
+	})
+}
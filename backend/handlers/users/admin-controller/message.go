@@ -0,0 +1,136 @@
+package admin
+
+import (
+	"database/sql"
+	services "fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MessageViews returns the full conversation of an application for moderation, including hidden messages.
+// Read receipts are not touched.
+func MessageViews(c *gin.Context) {
+	applicationID := c.Param("application-id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !requireApplication(c, db, applicationID) {
+		return
+	}
+
+	messages, err := services.LoadMessages(db, applicationID, true)
+	if err != nil {
+		log.Printf("Error loading messages of application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": messages})
+}
+
+// MessageAttachmentView downloads any attachment of an application's conversation for moderation
+func MessageAttachmentView(c *gin.Context) {
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	services.WriteMessageAttachment(c, db, c.Param("application-id"), true)
+}
+
+// requireApplication checks the application exists, writing a 404 if not
+func requireApplication(c *gin.Context, db *sql.DB, applicationID string) bool {
+	var exists bool
+	if err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM applications WHERE application_id = ?)", applicationID).Scan(&exists); err != nil {
+		log.Printf("Error checking application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return false
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Application not found"})
+		return false
+	}
+	return true
+}
+
+// MessageHide hides a message from both parties of the conversation, with a reason
+func MessageHide(c *gin.Context) {
+	var request struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Reason is required"})
+		return
+	}
+	setMessageHidden(c, true, reason)
+}
+
+// MessageUnhide shows a hidden message again
+func MessageUnhide(c *gin.Context) {
+	setMessageHidden(c, false, "")
+}
+
+// setMessageHidden hides or restores a message and records it in the audit log
+func setMessageHidden(c *gin.Context, hidden bool, reason string) {
+	messageID := c.Param("message-id")
+	adminID := c.MustGet("admin_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	var isHidden bool
+	if err := db.QueryRow("SELECT hidden_at IS NOT NULL FROM application_messages WHERE message_id = ?", messageID).Scan(&isHidden); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Message not found"})
+			return
+		}
+		log.Printf("Error loading message %s: %v", messageID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if isHidden == hidden {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Message visibility is unchanged"})
+		return
+	}
+
+	action := "message.hide"
+	updateQuery := "UPDATE application_messages SET hidden_at = CURRENT_TIMESTAMP, hidden_by = ?, hidden_reason = ? WHERE message_id = ?"
+	args := []interface{}{adminID, reason, messageID}
+	if !hidden {
+		action = "message.unhide"
+		updateQuery = "UPDATE application_messages SET hidden_at = NULL, hidden_by = NULL, hidden_reason = NULL WHERE message_id = ?"
+		args = []interface{}{messageID}
+	}
+
+	entry := services.NewAuditEntry(c, adminID, "admin", action, "message", messageID)
+	if err := services.ExecWithAudit(db, entry, services.MessageSnapshotQuery, updateQuery, args...); err != nil {
+		log.Printf("Error updating message visibility: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error updating message visibility"})
+		return
+	}
+
+	log.Printf("Message %s hidden=%v by admin %v", messageID, hidden, adminID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Message visibility updated successfully"})
+}
@@ -0,0 +1,354 @@
+package admin
+
+import (
+	"database/sql"
+	services "fresh-grad-jobs/services"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// skillRequest is the body of skill create and update requests
+type skillRequest struct {
+	Name    string   `json:"name"`
+	NameTH  string   `json:"name_th"`
+	Aliases []string `json:"aliases"`
+}
+
+// SkillViews lists the skills catalogue with aliases, optionally filtered by q
+func SkillViews(c *gin.Context) {
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	skills, err := services.ListSkills(db, c.Query("q"))
+	if err != nil {
+		log.Printf("Error listing skills: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": skills})
+}
+
+// SkillCreate adds a skill and its aliases to the catalogue
+func SkillCreate(c *gin.Context) {
+	var request skillRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	request.Name = services.NormalizeSkillName(request.Name)
+	request.NameTH = services.NormalizeSkillName(request.NameTH)
+	if request.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Skill name is required"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	if !checkSkillNamesFree(c, tx, 0, append([]string{request.Name}, request.Aliases...)) {
+		return
+	}
+
+	result, err := tx.Exec("INSERT INTO skills (name, name_th) VALUES (?, ?)", request.Name, request.NameTH)
+	if err != nil {
+		log.Printf("Error creating skill: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create skill"})
+		return
+	}
+	skillID, err := result.LastInsertId()
+	if err == nil {
+		err = insertSkillAliases(tx, skillID, request.Aliases)
+	}
+
+	adminID := c.MustGet("admin_id")
+	entry := services.NewAuditEntry(c, adminID, "admin", "skill.create", "skill", skillID)
+	if err == nil {
+		if entry.After, err = services.SnapshotRow(tx, services.SkillSnapshotQuery, skillID); err == nil {
+			err = services.RecordAudit(tx, entry)
+		}
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		log.Printf("Error creating skill: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create skill"})
+		return
+	}
+
+	log.Printf("Skill %d (%s) created", skillID, request.Name)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Skill created successfully", "skill_id": skillID})
+}
+
+// SkillUpdate renames a skill; aliases, when sent, replace the existing ones
+func SkillUpdate(c *gin.Context) {
+	skillID := c.Param("skill-id")
+
+	var request struct {
+		Name    *string   `json:"name"`
+		NameTH  *string   `json:"name_th"`
+		Aliases *[]string `json:"aliases"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	var current skillRequest
+	if err := db.QueryRow("SELECT name, name_th FROM skills WHERE skill_id = ?", skillID).Scan(&current.Name, &current.NameTH); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Skill not found"})
+			return
+		}
+		log.Printf("Error loading skill %s: %v", skillID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+
+	if request.Name != nil {
+		if current.Name = services.NormalizeSkillName(*request.Name); current.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Skill name must not be empty"})
+			return
+		}
+	}
+	if request.NameTH != nil {
+		current.NameTH = services.NormalizeSkillName(*request.NameTH)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	adminID := c.MustGet("admin_id")
+	entry := services.NewAuditEntry(c, adminID, "admin", "skill.update", "skill", skillID)
+	if entry.Before, err = services.SnapshotRow(tx, services.SkillSnapshotQuery, skillID); err != nil {
+		log.Printf("Error reading skill %s: %v", skillID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update skill"})
+		return
+	}
+
+	// Aliases being replaced must not block the new set
+	if request.Aliases != nil {
+		if _, err := tx.Exec("DELETE FROM skill_aliases WHERE skill_id = ?", skillID); err != nil {
+			log.Printf("Error clearing aliases of skill %s: %v", skillID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update skill"})
+			return
+		}
+	}
+	names := []string{current.Name}
+	if request.Aliases != nil {
+		names = append(names, *request.Aliases...)
+	}
+	if !checkSkillNamesFree(c, tx, skillID, names) {
+		return
+	}
+
+	_, err = tx.Exec("UPDATE skills SET name = ?, name_th = ? WHERE skill_id = ?", current.Name, current.NameTH, skillID)
+	if err == nil && request.Aliases != nil {
+		err = insertSkillAliases(tx, skillID, *request.Aliases)
+	}
+	if err == nil {
+		if entry.After, err = services.SnapshotRow(tx, services.SkillSnapshotQuery, skillID); err == nil {
+			err = services.RecordAudit(tx, entry)
+		}
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		log.Printf("Error updating skill %s: %v", skillID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update skill"})
+		return
+	}
+
+	log.Printf("Skill %s updated", skillID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Skill updated successfully"})
+}
+
+// SkillDelete removes a skill from the catalogue along with its job and profile links
+func SkillDelete(c *gin.Context) {
+	skillID := c.Param("skill-id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	var exists bool
+	if err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM skills WHERE skill_id = ?)", skillID).Scan(&exists); err != nil {
+		log.Printf("Error checking skill %s: %v", skillID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Skill not found"})
+		return
+	}
+
+	entry := services.NewAuditEntry(c, c.MustGet("admin_id"), "admin", "skill.delete", "skill", skillID)
+	if err := services.ExecWithAudit(db, entry, services.SkillSnapshotQuery, "DELETE FROM skills WHERE skill_id = ?", skillID); err != nil {
+		log.Printf("Error deleting skill %s: %v", skillID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to delete skill"})
+		return
+	}
+
+	log.Printf("Skill %s deleted", skillID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Skill deleted successfully"})
+}
+
+// SkillAliasCreate adds an alias to a skill
+func SkillAliasCreate(c *gin.Context) {
+	skillID := c.Param("skill-id")
+
+	var request struct {
+		Alias string `json:"alias" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	alias := services.NormalizeSkillName(request.Alias)
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	var exists bool
+	if err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM skills WHERE skill_id = ?)", skillID).Scan(&exists); err != nil {
+		log.Printf("Error checking skill %s: %v", skillID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Skill not found"})
+		return
+	}
+	if !checkSkillNamesFree(c, db, 0, []string{alias}) {
+		return
+	}
+
+	entry := services.NewAuditEntry(c, c.MustGet("admin_id"), "admin", "skill.alias_add", "skill", skillID)
+	insertQuery := "INSERT INTO skill_aliases (alias, skill_id) VALUES (?, ?)"
+	if err := services.ExecWithAudit(db, entry, services.SkillSnapshotQuery, insertQuery, alias, skillID); err != nil {
+		log.Printf("Error adding alias to skill %s: %v", skillID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to add alias"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Alias added successfully"})
+}
+
+// SkillAliasDelete removes an alias from a skill
+func SkillAliasDelete(c *gin.Context) {
+	skillID := c.Param("skill-id")
+	alias := c.Param("alias")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	var exists bool
+	checkQuery := "SELECT EXISTS (SELECT 1 FROM skill_aliases WHERE skill_id = ? AND alias = ?)"
+	if err := db.QueryRow(checkQuery, skillID, alias).Scan(&exists); err != nil {
+		log.Printf("Error checking alias of skill %s: %v", skillID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Alias not found"})
+		return
+	}
+
+	entry := services.NewAuditEntry(c, c.MustGet("admin_id"), "admin", "skill.alias_remove", "skill", skillID)
+	deleteQuery := "DELETE FROM skill_aliases WHERE skill_id = ? AND alias = ?"
+	if err := services.ExecWithAudit(db, entry, services.SkillSnapshotQuery, deleteQuery, skillID, alias); err != nil {
+		log.Printf("Error removing alias from skill %s: %v", skillID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to remove alias"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Alias removed successfully"})
+}
+
+// checkSkillNamesFree rejects empty or duplicate names and names already used by another skill or alias
+func checkSkillNamesFree(c *gin.Context, q services.Querier, skillID interface{}, names []string) bool {
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = services.NormalizeSkillName(name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Skill names and aliases must not be empty"})
+			return false
+		}
+		if seen[name] {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Duplicate skill name or alias: " + name})
+			return false
+		}
+		seen[name] = true
+
+		taken, err := services.SkillNameTaken(q, name, skillID)
+		if err != nil {
+			log.Printf("Error checking skill name %q: %v", name, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+			return false
+		}
+		if taken {
+			c.JSON(http.StatusConflict, gin.H{"status": "error", "message": "Skill name or alias already in use: " + name})
+			return false
+		}
+	}
+	return true
+}
+
+// insertSkillAliases links aliases to a skill
+func insertSkillAliases(exec services.Execer, skillID interface{}, aliases []string) error {
+	for _, alias := range aliases {
+		if _, err := exec.Exec("INSERT INTO skill_aliases (alias, skill_id) VALUES (?, ?)", services.NormalizeSkillName(alias), skillID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
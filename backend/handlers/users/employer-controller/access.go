@@ -0,0 +1,145 @@
+package employer
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Team roles within a company
+const (
+	teamRoleOwner     = "owner"
+	teamRoleRecruiter = "recruiter"
+	teamRoleViewer    = "viewer"
+)
+
+// Roles allowed to create and change job postings
+var jobEditorRoles = []string{teamRoleOwner, teamRoleRecruiter}
+
+// companyMembership returns the company and team role of the employer, or sql.ErrNoRows if they have no company
+func companyMembership(db *sql.DB, employerID interface{}) (int, string, error) {
+	var companyID int
+	var role string
+	query := "SELECT company_id, role FROM company_members WHERE user_id = ?"
+	if err := db.QueryRow(query, employerID).Scan(&companyID, &role); err != nil {
+		return 0, "", err
+	}
+	return companyID, role, nil
+}
+
+// requireCompanyRole loads the employer's membership and checks it has one of roles, writing the error response if not
+func requireCompanyRole(c *gin.Context, db *sql.DB, employerID interface{}, roles ...string) (int, string, bool) {
+	companyID, role, err := companyMembership(db, employerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("Employer %v is not a member of any company", employerID)
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Company not found"})
+			return 0, "", false
+		}
+		log.Printf("Error checking company membership for employer %v: %v", employerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return 0, "", false
+	}
+
+	if len(roles) > 0 && !hasRole(role, roles) {
+		log.Printf("Employer %v with team role '%s' attempted an action requiring %v", employerID, role, roles)
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": "Insufficient team permissions"})
+		return 0, "", false
+	}
+
+	return companyID, role, true
+}
+
+// jobAccessCondition returns a condition limiting jobs to those the employer's team can access with one of roles
+// (any role when none are given). Jobs without a company remain accessible to the employer who posted them.
+func jobAccessCondition(alias string, employerID interface{}, roles ...string) (string, []interface{}) {
+	if alias != "" {
+		alias += "."
+	}
+
+	memberQuery := "SELECT company_id FROM company_members WHERE user_id = ?"
+	args := []interface{}{employerID}
+	if len(roles) > 0 {
+		memberQuery += " AND role IN (?" + strings.Repeat(", ?", len(roles)-1) + ")"
+		for _, role := range roles {
+			args = append(args, role)
+		}
+	}
+	args = append(args, employerID)
+
+	condition := "(" + alias + "company_id IN (" + memberQuery + ") OR (" + alias + "company_id IS NULL AND " + alias + "employer_id = ?))"
+	return condition, args
+}
+
+// hasRole reports whether role is one of roles
+func hasRole(role string, roles []string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// requireApplicationAccess checks the application belongs to the job and the employer's team can access the job,
+// with one of roles when given, writing a 404 or 403 response if not
+func requireApplicationAccess(c *gin.Context, db *sql.DB, employerID interface{}, jobID, applicationID string, roles ...string) bool {
+	viewCondition, viewArgs := jobAccessCondition("j", employerID)
+	editCondition, editArgs := jobAccessCondition("j", employerID, roles...)
+	base := "SELECT 1 FROM applications a INNER JOIN jobs j ON j.job_id = a.job_id WHERE a.application_id = ? AND a.job_id = ? AND "
+	query := "SELECT EXISTS (" + base + viewCondition + "), EXISTS (" + base + editCondition + ")"
+
+	args := append([]interface{}{applicationID, jobID}, viewArgs...)
+	args = append(append(args, applicationID, jobID), editArgs...)
+
+	var canView, canEdit bool
+	if err := db.QueryRow(query, args...).Scan(&canView, &canEdit); err != nil {
+		log.Printf("Error checking access to application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return false
+	}
+	if !canView {
+		log.Printf("Application %s not found for job %s and employer %v", applicationID, jobID, employerID)
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Application not found"})
+		return false
+	}
+	if !canEdit {
+		log.Printf("Employer %v lacks team role %v for application %s", employerID, roles, applicationID)
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": "Insufficient team permissions"})
+		return false
+	}
+	return true
+}
+
+// requireJobAccess checks the employer's team can access the job, with one of roles when given, writing a
+// 404 or 403 response if not
+func requireJobAccess(c *gin.Context, db *sql.DB, employerID interface{}, jobID string, roles ...string) bool {
+	viewCondition, viewArgs := jobAccessCondition("", employerID)
+	editCondition, editArgs := jobAccessCondition("", employerID, roles...)
+	query := "SELECT EXISTS (SELECT 1 FROM jobs WHERE job_id = ? AND " + viewCondition + "), " +
+		"EXISTS (SELECT 1 FROM jobs WHERE job_id = ? AND " + editCondition + ")"
+
+	args := append([]interface{}{jobID}, viewArgs...)
+	args = append(append(args, jobID), editArgs...)
+
+	var canView, canEdit bool
+	if err := db.QueryRow(query, args...).Scan(&canView, &canEdit); err != nil {
+		log.Printf("Error checking access to job %s: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return false
+	}
+	if !canView {
+		log.Printf("Job not found or not accessible (Job ID: %s, Employer ID: %v)", jobID, employerID)
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Job not found"})
+		return false
+	}
+	if !canEdit {
+		log.Printf("Employer %v lacks team role %v for job %s", employerID, roles, jobID)
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": "Insufficient team permissions"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package employer
+
+import (
+	"fmt"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Application is an application as seen by the employer, with the applicant's searchable profile fields
+type Application struct {
+	ApplicationID      int      `json:"application_id"`
+	JobID              int      `json:"job_id"`
+	JobTitle           string   `json:"job_title"`
+	FreshGradProfileID int      `json:"fresh_grad_profile_id"`
+	ResumeVersionID    *int     `json:"resume_version_id"`
+	ResumeName         *string  `json:"resume_name"`
+	ResumeVersion      *int     `json:"resume_version"`
+	FreshGradResume    *string  `json:"resume_file_link"`
+	Favorited          bool     `json:"favorited"`
+	FavoriteCount      int      `json:"favorite_count"`
+	Status             string   `json:"status"`
+	AppliedAt          string   `json:"applied_at"`
+	WithdrawnAt        *string  `json:"withdrawn_at"`
+	WithdrawReason     *string  `json:"withdraw_reason"`
+	University         *string  `json:"university"`
+	GraduationYear     *int     `json:"graduation_year"`
+	GPA                *float64 `json:"gpa"`
+	Location           *string  `json:"location"`
+	AverageRating      *float64 `json:"average_rating"`
+	Tags               []string `json:"tags"`
+
+	tagList    *string
+	resumeFile bool
+}
+
+// applicationColumns are the Application columns over applicationTables; its one placeholder is the viewing
+// recruiter, for whom favorited is reported. Profile fields are the ones submitted with the application.
+var applicationColumns = "a.application_id, a.job_id, j.title, a.freshgradprofile_id, " +
+	"a.resume_version_id, r.name, rv.version, rv.file_link, rv.storage_key IS NOT NULL, " +
+	services.FavoritedExpr + ", " +
+	"(SELECT COUNT(*) FROM application_favorites fc WHERE fc.application_id = a.application_id), " +
+	"a.status, a.applied_at, a.withdrawn_at, a.withdraw_reason, " +
+	services.SubmittedUniversityExpr + ", " + services.SubmittedGraduationYearExpr + ", " +
+	services.SubmittedGPAExpr + ", " + services.SubmittedLocationExpr + ", " +
+	services.AverageRatingExpr + ", " + services.ApplicationTagsExpr
+
+// applicationTables joins applications a with their jobs j, applicants' profiles f and submitted resume
+// versions rv of resumes r
+const applicationTables = "applications a " +
+	"INNER JOIN jobs j ON a.job_id = j.job_id " +
+	"INNER JOIN freshgradprofiles f ON a.freshgradprofile_id = f.freshgradprofile_id " +
+	"LEFT JOIN resume_versions rv ON rv.resume_version_id = a.resume_version_id " +
+	"LEFT JOIN resumes r ON r.resume_id = rv.resume_id"
+
+// applicationQuery selects Application rows
+var applicationQuery = "SELECT " + applicationColumns + " FROM " + applicationTables
+
+// scanTargets returns pointers to the application's fields in applicationQuery order
+func (application *Application) scanTargets() []interface{} {
+	return []interface{}{
+		&application.ApplicationID, &application.JobID, &application.JobTitle, &application.FreshGradProfileID,
+		&application.ResumeVersionID, &application.ResumeName, &application.ResumeVersion, &application.FreshGradResume,
+		&application.resumeFile, &application.Favorited, &application.FavoriteCount, &application.Status, &application.AppliedAt,
+		&application.WithdrawnAt, &application.WithdrawReason, &application.University, &application.GraduationYear, &application.GPA, &application.Location,
+		&application.AverageRating, &application.tagList,
+	}
+}
+
+// afterScan fills the fields derived from scanned columns
+func (application *Application) afterScan() {
+	application.Tags = services.SplitTags(application.tagList)
+	// Uploaded resumes are downloaded through the API
+	if application.resumeFile {
+		link := fmt.Sprintf("/employer/jobs/%d/applications/%d/resume", application.JobID, application.ApplicationID)
+		application.FreshGradResume = &link
+	}
+}
+
+// ApplicantSearch searches applicants across every job the employer's team can see, with the same filters
+// as ApplicationViews plus an optional job_id
+func ApplicantSearch(c *gin.Context) {
+	employerID := c.MustGet("employer_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	listApplications(c, db, employerID, c.Query("job_id"))
+}
+
+// listApplications writes the applications matching the request's search parameters, limited to jobID when set
+func listApplications(c *gin.Context, db services.Querier, employerID interface{}, jobID string) {
+	search, fieldErrors, err := services.ParseApplicantSearch(db, c.Request.URL.Query(), employerID)
+	if err != nil {
+		log.Printf("Error parsing applicant search: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	if len(fieldErrors) > 0 {
+		respondValidationErrors(c, fieldErrors)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "limit must be between 1 and 200"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "offset must not be negative"})
+		return
+	}
+
+	// Applications are visible to every member of the job's company
+	accessCondition, accessArgs := jobAccessCondition("j", employerID)
+	query := applicationQuery + " WHERE " + accessCondition
+	args := append([]interface{}{employerID}, accessArgs...)
+	if jobID != "" {
+		query += " AND a.job_id = ?"
+		args = append(args, jobID)
+	}
+	query += search.Where() + " ORDER BY " + search.OrderBy + " LIMIT ? OFFSET ?"
+	args = append(append(args, search.Args...), limit, offset)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Printf("Query execution error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	defer rows.Close()
+
+	applications := []Application{}
+	for rows.Next() {
+		var application Application
+		if err := rows.Scan(application.scanTargets()...); err != nil {
+			log.Printf("Row scan error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
+			return
+		}
+		application.afterScan()
+		applications = append(applications, application)
+	}
+
+	// Check for any errors during row iteration
+	if err := rows.Err(); err != nil {
+		log.Printf("Rows iteration error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error processing applications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": applications})
+}
@@ -0,0 +1,342 @@
+package employer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApplicantProfile is the applicant's profile in the application detail, as submitted when Submitted is set and
+// as it is now for applications from before profiles were kept; contact fields are only filled once the
+// application status allows the employer to contact the applicant or the applicant accepted a contact request
+type ApplicantProfile struct {
+	Submitted         bool             `json:"submitted"`
+	FullName          *string          `json:"full_name"`
+	ExperienceYears   *int             `json:"experience_years"`
+	PreferredLocation *string          `json:"preferred_location"`
+	Skills            []services.Skill `json:"skills"`
+	ContactVisible    bool             `json:"contact_visible"`
+	Email             *string          `json:"email"`
+	Phone             *string          `json:"phone"`
+}
+
+// ScreeningAnswer is the applicant's answer to one screening question
+type ScreeningAnswer struct {
+	QuestionID *int   `json:"question_id"`
+	Question   string `json:"question"`
+	Answer     string `json:"answer"`
+	Knockout   bool   `json:"knockout"`
+}
+
+// StatusHistoryEntry is one status change of an application
+type StatusHistoryEntry struct {
+	FromStatus *string `json:"from_status"`
+	ToStatus   string  `json:"to_status"`
+	ChangedBy  *int    `json:"changed_by"`
+	Note       *string `json:"note"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+// ApplicationNote is a private note by a member of the employer's team
+type ApplicationNote struct {
+	NoteID    int    `json:"note_id"`
+	AuthorID  int    `json:"author_id"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// ApplicationRating is one reviewer's 1–5 rating of an application
+type ApplicationRating struct {
+	ReviewerID int    `json:"reviewer_id"`
+	Rating     int    `json:"rating"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// ApplicationDetail is a single application with everything a reviewer needs
+type ApplicationDetail struct {
+	Application
+	CoverLetter      *string              `json:"cover_letter"`
+	Profile          ApplicantProfile     `json:"profile"`
+	ScreeningAnswers []ScreeningAnswer    `json:"screening_answers"`
+	StatusHistory    []StatusHistoryEntry `json:"status_history"`
+	Notes            []ApplicationNote    `json:"notes"`
+	Ratings          []ApplicationRating  `json:"ratings"`
+}
+
+// loadApplicationDetail reads an application the employer can access, or returns sql.ErrNoRows
+func loadApplicationDetail(db *sql.DB, employerID interface{}, jobID, applicationID string) (ApplicationDetail, error) {
+	var detail ApplicationDetail
+	var email, phone *string
+
+	// Applications are visible to every member of the job's company
+	accessCondition, accessArgs := jobAccessCondition("j", employerID)
+	acceptedCondition, acceptedArgs := contactAcceptedCondition(employerID)
+	var snapshot []byte
+	var contactAccepted bool
+	query := "SELECT " + applicationColumns + ", a.cover_letter, f.full_name, f.experience_years, f.preferred_location, u.email, f.phone, " +
+		"a.profile_snapshot, " + acceptedCondition + " FROM " + applicationTables + " " +
+		"INNER JOIN users u ON u.user_id = f.user_id " +
+		"WHERE a.application_id = ? AND a.job_id = ? AND " + accessCondition
+	targets := append(detail.scanTargets(), &detail.CoverLetter, &detail.Profile.FullName,
+		&detail.Profile.ExperienceYears, &detail.Profile.PreferredLocation, &email, &phone, &snapshot, &contactAccepted)
+	args := append(append([]interface{}{employerID}, acceptedArgs...), applicationID, jobID)
+	if err := db.QueryRow(query, append(args, accessArgs...)...).Scan(targets...); err != nil {
+		return detail, err
+	}
+	detail.afterScan()
+
+	// Applications submitted with a profile snapshot show the profile as it was then
+	if snapshot != nil {
+		var submitted services.ProfileSnapshot
+		if err := json.Unmarshal(snapshot, &submitted); err != nil {
+			return detail, fmt.Errorf("error reading profile snapshot: %v", err)
+		}
+		detail.Profile.Submitted = true
+		detail.Profile.FullName, detail.Profile.ExperienceYears = submitted.FullName, submitted.ExperienceYears
+		detail.Profile.PreferredLocation, detail.Profile.Skills, phone = submitted.PreferredLocation, submitted.Skills, submitted.Phone
+		detail.University, detail.GraduationYear = submitted.University, submitted.GraduationYear
+		detail.GPA, detail.Location = submitted.GPA, submitted.Location
+	}
+
+	// Contact details are shared by the application's progress or by accepting a contact request from the team
+	if services.ContactVisible(services.ApplicationStatus(detail.Status)) || contactAccepted {
+		detail.Profile.ContactVisible = true
+		detail.Profile.Email, detail.Profile.Phone = email, phone
+	}
+
+	var err error
+	if !detail.Profile.Submitted {
+		if detail.Profile.Skills, err = loadProfileSkills(db, detail.FreshGradProfileID); err != nil {
+			return detail, err
+		}
+	}
+	if detail.ScreeningAnswers, err = loadScreeningAnswers(db, applicationID); err != nil {
+		return detail, err
+	}
+	if detail.StatusHistory, err = loadStatusHistory(db, applicationID); err != nil {
+		return detail, err
+	}
+	if detail.Notes, err = loadApplicationNotes(db, applicationID); err != nil {
+		return detail, err
+	}
+	if detail.Ratings, err = loadApplicationRatings(db, applicationID); err != nil {
+		return detail, err
+	}
+	return detail, nil
+}
+
+// ApplicationResumeView downloads the resume version submitted with an application
+func ApplicationResumeView(c *gin.Context) {
+	db, ok := openApplicationReview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	services.WriteResumeVersion(c, db, "rv.resume_version_id = (SELECT resume_version_id FROM applications WHERE application_id = ?)",
+		c.Param("application-id"))
+}
+
+// loadProfileSkills reads the catalogue skills of a profile
+func loadProfileSkills(db *sql.DB, profileID int) ([]services.Skill, error) {
+	query := "SELECT s.skill_id, s.name, s.name_th FROM freshgradprofile_skills ps " +
+		"INNER JOIN skills s ON s.skill_id = ps.skill_id WHERE ps.freshgradprofile_id = ? ORDER BY s.name"
+	rows, err := db.Query(query, profileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	skills := []services.Skill{}
+	for rows.Next() {
+		var skill services.Skill
+		if err := rows.Scan(&skill.ID, &skill.Name, &skill.NameTH); err != nil {
+			return nil, err
+		}
+		skills = append(skills, skill)
+	}
+	return skills, rows.Err()
+}
+
+// loadScreeningAnswers reads the screening answers of an application in question order
+func loadScreeningAnswers(db *sql.DB, applicationID string) ([]ScreeningAnswer, error) {
+	rows, err := db.Query("SELECT question_id, question, answer, knockout FROM application_answers WHERE application_id = ? ORDER BY answer_id", applicationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := []ScreeningAnswer{}
+	for rows.Next() {
+		var answer ScreeningAnswer
+		if err := rows.Scan(&answer.QuestionID, &answer.Question, &answer.Answer, &answer.Knockout); err != nil {
+			return nil, err
+		}
+		answers = append(answers, answer)
+	}
+	return answers, rows.Err()
+}
+
+// loadStatusHistory reads the status changes of an application, oldest first
+func loadStatusHistory(db *sql.DB, applicationID string) ([]StatusHistoryEntry, error) {
+	query := "SELECT from_status, to_status, changed_by, note, created_at FROM application_status_history " +
+		"WHERE application_id = ? ORDER BY created_at, history_id"
+	rows, err := db.Query(query, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []StatusHistoryEntry{}
+	for rows.Next() {
+		var entry StatusHistoryEntry
+		if err := rows.Scan(&entry.FromStatus, &entry.ToStatus, &entry.ChangedBy, &entry.Note, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+	return history, rows.Err()
+}
+
+// loadApplicationNotes reads the team's notes on an application, oldest first
+func loadApplicationNotes(db *sql.DB, applicationID string) ([]ApplicationNote, error) {
+	query := "SELECT note_id, author_id, body, created_at, updated_at FROM application_notes " +
+		"WHERE application_id = ? ORDER BY created_at, note_id"
+	rows, err := db.Query(query, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := []ApplicationNote{}
+	for rows.Next() {
+		var note ApplicationNote
+		if err := rows.Scan(&note.NoteID, &note.AuthorID, &note.Body, &note.CreatedAt, &note.UpdatedAt); err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+// loadApplicationRatings reads every reviewer's rating of an application
+func loadApplicationRatings(db *sql.DB, applicationID string) ([]ApplicationRating, error) {
+	query := "SELECT reviewer_id, rating, updated_at FROM application_ratings WHERE application_id = ? ORDER BY updated_at"
+	rows, err := db.Query(query, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := []ApplicationRating{}
+	for rows.Next() {
+		var rating ApplicationRating
+		if err := rows.Scan(&rating.ReviewerID, &rating.Rating, &rating.UpdatedAt); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rating)
+	}
+	return ratings, rows.Err()
+}
+
+// ApplicationStatusUpdate moves an application to a new status, recording the history and notifying the applicant
+func ApplicationStatusUpdate(c *gin.Context) {
+	jobID := c.Param("job-id")
+	applicationID := c.Param("application-id")
+	employerID := c.MustGet("employer_id")
+
+	var request struct {
+		Status string `json:"status" binding:"required"`
+		Note   string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	status, ok := services.ParseApplicationStatus(request.Status)
+	if !ok {
+		respondValidationErrors(c, services.FieldErrors{"status": "is not a valid application status"})
+		return
+	}
+	if err := services.CheckEmployerStatusTarget(status); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+	if !requireApplicationAccess(c, db, employerID, jobID, applicationID, jobEditorRoles...) {
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	entry := services.NewAuditEntry(c, employerID, "employer", "application.status", "application", applicationID)
+	if entry.Before, err = services.SnapshotRow(tx, services.ApplicationSnapshotQuery, applicationID); err != nil {
+		log.Printf("Error reading application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update application status"})
+		return
+	}
+
+	change, err := services.ChangeApplicationStatus(tx, applicationID, status, employerID, strings.TrimSpace(request.Note))
+	if err != nil {
+		if !respondStatusChangeError(c, err) {
+			log.Printf("Error changing status of application %s: %v", applicationID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update application status"})
+		}
+		return
+	}
+
+	if entry.After, err = services.SnapshotRow(tx, services.ApplicationSnapshotQuery, applicationID); err == nil {
+		err = services.RecordAudit(tx, entry)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		log.Printf("Error saving status of application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update application status"})
+		return
+	}
+
+	log.Printf("Application %s moved from %s to %s by employer %v", applicationID, change.From, change.To, employerID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Application status updated successfully"})
+}
+
+// respondStatusChangeError writes the response for a rejected status change and reports whether err was one
+func respondStatusChangeError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrApplicationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Application not found"})
+	case errors.Is(err, services.ErrApplicationStatusSame),
+		errors.Is(err, services.ErrApplicationStatusLocked),
+		errors.Is(err, services.ErrApplicationWithdrawOnly):
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
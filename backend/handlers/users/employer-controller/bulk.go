@@ -0,0 +1,244 @@
+package employer
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBulkApplications caps the applications changed by one bulk request
+const maxBulkApplications = 500
+
+// Bulk actions on applications
+const (
+	bulkActionStatus     = "status"
+	bulkActionTag        = "tag"
+	bulkActionFavorite   = "favorite"
+	bulkActionUnfavorite = "unfavorite"
+	bulkActionReject     = "reject"
+)
+
+// bulkItemResult is the outcome for one application of a bulk request
+type bulkItemResult struct {
+	ApplicationID int    `json:"application_id"`
+	Success       bool   `json:"success"`
+	Error         string `json:"error,omitempty"`
+}
+
+// errNotInJob is reported for applications that do not belong to the job in the path
+var errNotInJob = errors.New("application not found for this job")
+
+// ApplicationBulkAction applies one action to many applications of a job in a single transaction. Each
+// application runs inside its own savepoint, so failures are reported per item without undoing the others.
+func ApplicationBulkAction(c *gin.Context) {
+	jobID := c.Param("job-id")
+	employerID := c.MustGet("employer_id")
+
+	var request struct {
+		ApplicationIDs []int  `json:"application_ids" binding:"required"`
+		Action         string `json:"action" binding:"required"`
+		Status         string `json:"status"`
+		Tag            string `json:"tag"`
+		Message        string `json:"message"`
+		Note           string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	fieldErrors := services.FieldErrors{}
+	ids := uniqueIDs(request.ApplicationIDs)
+	if len(ids) == 0 || len(ids) > maxBulkApplications {
+		fieldErrors["application_ids"] = fmt.Sprintf("must list 1 to %d applications", maxBulkApplications)
+	}
+	var status services.ApplicationStatus
+	tag := services.NormalizeTag(request.Tag)
+	message := strings.TrimSpace(request.Message)
+	switch request.Action {
+	case bulkActionStatus:
+		var ok bool
+		if status, ok = services.ParseApplicationStatus(request.Status); !ok {
+			fieldErrors["status"] = "is not a valid application status"
+		}
+	case bulkActionTag:
+		if tag == "" || len(tag) > 50 {
+			fieldErrors["tag"] = "must be 1 to 50 characters"
+		}
+	case bulkActionReject:
+		status = services.ApplicationStatusRejected
+		if message == "" {
+			fieldErrors["message"] = "is required when rejecting"
+		}
+	case bulkActionFavorite, bulkActionUnfavorite:
+	default:
+		fieldErrors["action"] = "must be one of: status, tag, favorite, unfavorite, reject"
+	}
+	if len(fieldErrors) > 0 {
+		respondValidationErrors(c, fieldErrors)
+		return
+	}
+	if request.Action == bulkActionStatus {
+		if err := services.CheckEmployerStatusTarget(status); err != nil {
+			c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	accessCondition, accessArgs := jobAccessCondition("", employerID, jobEditorRoles...)
+	var jobExists bool
+	checkQuery := "SELECT EXISTS (SELECT 1 FROM jobs WHERE job_id = ? AND " + accessCondition + ")"
+	if err := db.QueryRow(checkQuery, append([]interface{}{jobID}, accessArgs...)...).Scan(&jobExists); err != nil {
+		log.Printf("Error checking job %s: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if !jobExists {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Job not found"})
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	results := make([]bulkItemResult, 0, len(ids))
+	succeeded := 0
+	for _, applicationID := range ids {
+		result := bulkItemResult{ApplicationID: applicationID}
+		if _, err := tx.Exec("SAVEPOINT bulk_item"); err != nil {
+			log.Printf("Error creating savepoint: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Bulk action failed"})
+			return
+		}
+
+		err := applyBulkAction(c, tx, employerID, jobID, applicationID, request.Action, status, tag, message, strings.TrimSpace(request.Note))
+		if err != nil {
+			if _, rollbackErr := tx.Exec("ROLLBACK TO SAVEPOINT bulk_item"); rollbackErr != nil {
+				log.Printf("Error rolling back savepoint: %v", rollbackErr)
+				c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Bulk action failed"})
+				return
+			}
+			result.Error = bulkErrorMessage(err)
+			if result.Error == "" {
+				log.Printf("Bulk %s failed for application %d: %v", request.Action, applicationID, err)
+				result.Error = "internal error"
+			}
+		} else {
+			result.Success = true
+			succeeded++
+		}
+		results = append(results, result)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error committing transaction"})
+		return
+	}
+
+	responseStatus := "success"
+	if succeeded < len(results) {
+		responseStatus = "partial"
+		if succeeded == 0 {
+			responseStatus = "error"
+		}
+	}
+	log.Printf("Bulk %s on job %s by employer %v: %d of %d succeeded", request.Action, jobID, employerID, succeeded, len(results))
+	c.JSON(http.StatusOK, gin.H{
+		"status":    responseStatus,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+		"results":   results,
+	})
+}
+
+// applyBulkAction applies the action to one application inside tx, recording it in the audit log
+func applyBulkAction(c *gin.Context, tx *sql.Tx, employerID interface{}, jobID string, applicationID int,
+	action string, status services.ApplicationStatus, tag, message, note string) error {
+	var applicationJobID string
+	err := tx.QueryRow("SELECT job_id FROM applications WHERE application_id = ?", applicationID).Scan(&applicationJobID)
+	if err == sql.ErrNoRows || (err == nil && applicationJobID != jobID) {
+		return errNotInJob
+	}
+	if err != nil {
+		return err
+	}
+
+	entry := services.NewAuditEntry(c, employerID, "employer", "application."+action, "application", applicationID)
+	if entry.Before, err = services.SnapshotRow(tx, services.ApplicationSnapshotQuery, applicationID); err != nil {
+		return err
+	}
+
+	switch action {
+	case bulkActionStatus:
+		_, err = services.ChangeApplicationStatus(tx, applicationID, status, employerID, note)
+	case bulkActionReject:
+		var change services.ApplicationStatusChange
+		if change, err = services.ChangeApplicationStatus(tx, applicationID, status, employerID, note); err == nil {
+			data := map[string]interface{}{"application_id": applicationID, "job_id": change.JobID}
+			err = services.CreateNotification(tx, change.FreshGradUserID, "application.message", message, data)
+		}
+	case bulkActionTag:
+		err = services.AddApplicationTag(tx, applicationID, tag, employerID)
+	case bulkActionFavorite, bulkActionUnfavorite:
+		err = services.SetApplicationFavorite(tx, applicationID, employerID, action == bulkActionFavorite)
+	}
+	if err != nil {
+		return err
+	}
+
+	if entry.After, err = services.SnapshotRow(tx, services.ApplicationSnapshotQuery, applicationID); err != nil {
+		return err
+	}
+	return services.RecordAudit(tx, entry)
+}
+
+// bulkErrorMessage returns the client-facing message for an expected per-item failure, or "" for internal errors
+func bulkErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, errNotInJob):
+		return err.Error()
+	case errors.Is(err, services.ErrApplicationNotFound),
+		errors.Is(err, services.ErrApplicationStatusSame),
+		errors.Is(err, services.ErrApplicationStatusLocked),
+		errors.Is(err, services.ErrApplicationWithdrawOnly):
+		return err.Error()
+	}
+	return ""
+}
+
+// uniqueIDs drops duplicate IDs, keeping the first occurrence
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	var unique []int
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,410 @@
+package employer
+
+import (
+	"database/sql"
+	"fmt"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SuggestedCandidates scores active fresh grads against one of the employer's jobs and returns the best
+// matches with an explanation of each score
+func SuggestedCandidates(c *gin.Context) {
+	jobID := c.Param("job-id")
+	employerID := c.MustGet("employer_id")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "limit must be between 1 and 100"})
+		return
+	}
+	minScore, err := strconv.ParseFloat(c.DefaultQuery("min_score", "0"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "min_score must be a number"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	var job services.JobRequirements
+	accessCondition, accessArgs := jobAccessCondition("j", employerID)
+	jobQuery := "SELECT " + services.JobRequirementsColumns + " FROM jobs j WHERE j.job_id = ? AND " + accessCondition
+	if err := db.QueryRow(jobQuery, append([]interface{}{jobID}, accessArgs...)...).Scan(job.ScanTargets()...); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Job not found"})
+			return
+		}
+		log.Printf("Error loading job %s: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	jobSkills, err := services.SkillIDsByOwner(db, "job_skills", "job_id", []int{job.JobID})
+	if err != nil {
+		log.Printf("Error loading skills of job %s: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	job.SkillIDs = jobSkills[job.JobID]
+
+	type Candidate struct {
+		FreshGradProfileID int                 `json:"fresh_grad_profile_id"`
+		ExperienceYears    *int                `json:"experience_years"`
+		PreferredLocation  *string             `json:"preferred_location"`
+		PreferredJobLevel  *string             `json:"preferred_job_level"`
+		Applied            bool                `json:"applied"`
+		Match              services.MatchScore `json:"match"`
+	}
+
+	// Only approved, active fresh grads whose profile the employer may find are suggested. When the job lists
+	// catalogue skills, only profiles sharing one of them are scored, those sharing the most first; either way at
+	// most MaxSuggestedCandidatePool profiles are loaded.
+	visibleCondition, visibleArgs := candidateVisibleCondition(employerID)
+	args := []interface{}{job.JobID}
+	overlapJoin, order := "", "f.freshgradprofile_id DESC"
+	if len(job.SkillIDs) > 0 {
+		overlapJoin = "INNER JOIN (SELECT freshgradprofile_id, COUNT(*) AS shared_skills FROM freshgradprofile_skills " +
+			"WHERE skill_id IN (?" + strings.Repeat(", ?", len(job.SkillIDs)-1) + ") GROUP BY freshgradprofile_id) ps " +
+			"ON ps.freshgradprofile_id = f.freshgradprofile_id "
+		order = "ps.shared_skills DESC, " + order
+		for _, id := range job.SkillIDs {
+			args = append(args, id)
+		}
+	}
+	query := "SELECT " + services.CandidateProfileColumns + ", " +
+		"EXISTS (SELECT 1 FROM applications a WHERE a.job_id = ? AND a.freshgradprofile_id = f.freshgradprofile_id) " +
+		"FROM freshgradprofiles f INNER JOIN users u ON u.user_id = f.user_id " + overlapJoin +
+		"WHERE " + activeCandidateCondition + " AND " + visibleCondition + " ORDER BY " + order + " LIMIT ?"
+	args = append(append(args, visibleArgs...), services.MaxSuggestedCandidatePool)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Printf("Query execution error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	defer rows.Close()
+
+	var profiles []services.CandidateProfile
+	var candidates []Candidate
+	var profileIDs []int
+	for rows.Next() {
+		var profile services.CandidateProfile
+		var candidate Candidate
+		if err := rows.Scan(append(profile.ScanTargets(), &candidate.Applied)...); err != nil {
+			log.Printf("Row scan error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
+			return
+		}
+		candidate.FreshGradProfileID = profile.ProfileID
+		candidate.ExperienceYears, candidate.PreferredLocation = profile.ExperienceYears, profile.PreferredLocation
+		candidate.PreferredJobLevel = profile.PreferredJobLevel
+		profiles = append(profiles, profile)
+		candidates = append(candidates, candidate)
+		profileIDs = append(profileIDs, profile.ProfileID)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Rows iteration error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	profileSkills, err := services.SkillIDsByOwner(db, "freshgradprofile_skills", "freshgradprofile_id", profileIDs)
+	if err != nil {
+		log.Printf("Error loading profile skills: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	scores := make([]services.MatchScore, len(profiles))
+	for i := range profiles {
+		profiles[i].SkillIDs = profileSkills[profiles[i].ProfileID]
+		scores[i] = services.ScoreMatch(profiles[i], job)
+		candidates[i].Match = scores[i]
+	}
+
+	suggested := []Candidate{}
+	for _, i := range services.SortByScore(scores) {
+		if len(suggested) == limit || scores[i].Total < minScore {
+			break
+		}
+		suggested = append(suggested, candidates[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": suggested})
+}
+
+// activeCandidateCondition limits users u to approved, active fresh grads
+const activeCandidateCondition = "u.role = 'freshGrad' AND u.approved = TRUE AND u.suspended = FALSE"
+
+// companyBlockedCondition matches profiles f that blocked the company of the employer bound to its placeholder
+const companyBlockedCondition = "EXISTS (SELECT 1 FROM profile_blocked_companies b " +
+	"INNER JOIN company_members m ON m.company_id = b.company_id " +
+	"WHERE b.freshgradprofile_id = f.freshgradprofile_id AND m.user_id = ?)"
+
+// candidateVisibleCondition limits profiles f to those the employer may find outside applications: searchable
+// profiles, and profiles visible to applied employers that applied to a job of the employer's team, unless the
+// fresh grad blocked the employer's company
+func candidateVisibleCondition(employerID interface{}) (string, []interface{}) {
+	accessCondition, accessArgs := jobAccessCondition("pj", employerID)
+	condition := "NOT " + companyBlockedCondition + " AND (f.visibility = ? OR (f.visibility = ? AND " +
+		"EXISTS (SELECT 1 FROM applications pa INNER JOIN jobs pj ON pj.job_id = pa.job_id " +
+		"WHERE pa.freshgradprofile_id = f.freshgradprofile_id AND " + accessCondition + ")))"
+	args := []interface{}{employerID, string(services.ProfileVisibilitySearchable), string(services.ProfileVisibilityApplied)}
+	return condition, append(args, accessArgs...)
+}
+
+// contactAcceptedCondition matches profiles f that accepted a contact request from the employer's team and have
+// not blocked its company since
+func contactAcceptedCondition(employerID interface{}) (string, []interface{}) {
+	accessCondition, accessArgs := jobAccessCondition("cr", employerID)
+	condition := "(NOT " + companyBlockedCondition + " AND EXISTS (SELECT 1 FROM contact_requests cr " +
+		"WHERE cr.freshgradprofile_id = f.freshgradprofile_id AND cr.status = ? AND " + accessCondition + "))"
+	args := []interface{}{employerID, string(services.ContactRequestStatusAccepted)}
+	return condition, append(args, accessArgs...)
+}
+
+// contactSharedCondition matches profiles f whose contact details the employer's team may see: an application to
+// one of the team's jobs has reached a contact status, or the fresh grad accepted a contact request
+func contactSharedCondition(employerID interface{}) (string, []interface{}) {
+	accessCondition, accessArgs := jobAccessCondition("sj", employerID)
+	statusCondition, statusArgs := services.ContactStatusCondition("sa.status")
+	acceptedCondition, acceptedArgs := contactAcceptedCondition(employerID)
+	condition := "(EXISTS (SELECT 1 FROM applications sa INNER JOIN jobs sj ON sj.job_id = sa.job_id " +
+		"WHERE sa.freshgradprofile_id = f.freshgradprofile_id AND " + statusCondition + " AND " + accessCondition + ") OR " +
+		acceptedCondition + ")"
+	args := append(statusArgs, accessArgs...)
+	return condition, append(args, acceptedArgs...)
+}
+
+// CandidateDetail is a fresh grad's current profile as an employer finds it outside applications; contact fields
+// are only filled once the fresh grad shares them with the employer's team
+type CandidateDetail struct {
+	FreshGradProfileID   int              `json:"fresh_grad_profile_id"`
+	FullName             *string          `json:"full_name"`
+	University           *string          `json:"university"`
+	GraduationYear       *int             `json:"graduation_year"`
+	GPA                  *float64         `json:"gpa"`
+	Location             *string          `json:"location"`
+	ExperienceYears      *int             `json:"experience_years"`
+	PreferredLocation    *string          `json:"preferred_location"`
+	PreferredJobLevel    *string          `json:"preferred_job_level"`
+	Skills               []services.Skill `json:"skills"`
+	Applied              bool             `json:"applied"`
+	ContactVisible       bool             `json:"contact_visible"`
+	Email                *string          `json:"email"`
+	Phone                *string          `json:"phone"`
+	ContactRequestStatus *string          `json:"contact_request_status"`
+}
+
+// CandidateView returns a fresh grad's profile when their visibility settings let the employer find it
+func CandidateView(c *gin.Context) {
+	profileID := c.Param("profile-id")
+	employerID := c.MustGet("employer_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	appliedCondition, appliedArgs := jobAccessCondition("aj", employerID)
+	sharedCondition, sharedArgs := contactSharedCondition(employerID)
+	requestCondition, requestArgs := jobAccessCondition("cr", employerID)
+	visibleCondition, visibleArgs := candidateVisibleCondition(employerID)
+	query := "SELECT f.freshgradprofile_id, f.full_name, f.university, f.graduation_year, f.gpa, f.location, " +
+		"f.experience_years, f.preferred_location, f.preferred_job_level, u.email, f.phone, " +
+		"EXISTS (SELECT 1 FROM applications aa INNER JOIN jobs aj ON aj.job_id = aa.job_id " +
+		"WHERE aa.freshgradprofile_id = f.freshgradprofile_id AND " + appliedCondition + "), " + sharedCondition + ", " +
+		"(SELECT cr.status FROM contact_requests cr WHERE cr.freshgradprofile_id = f.freshgradprofile_id AND " + requestCondition +
+		" ORDER BY cr.created_at DESC, cr.contact_request_id DESC LIMIT 1) " +
+		"FROM freshgradprofiles f INNER JOIN users u ON u.user_id = f.user_id " +
+		"WHERE f.freshgradprofile_id = ? AND " + activeCandidateCondition + " AND " + visibleCondition
+	args := append(appliedArgs, sharedArgs...)
+	args = append(append(args, requestArgs...), profileID)
+	args = append(args, visibleArgs...)
+
+	var candidate CandidateDetail
+	var email, phone *string
+	if err := db.QueryRow(query, args...).Scan(
+		&candidate.FreshGradProfileID, &candidate.FullName, &candidate.University, &candidate.GraduationYear,
+		&candidate.GPA, &candidate.Location, &candidate.ExperienceYears, &candidate.PreferredLocation,
+		&candidate.PreferredJobLevel, &email, &phone, &candidate.Applied, &candidate.ContactVisible,
+		&candidate.ContactRequestStatus,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Candidate not found"})
+			return
+		}
+		log.Printf("Error loading candidate %s: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if candidate.ContactVisible {
+		candidate.Email, candidate.Phone = email, phone
+	}
+
+	if candidate.Skills, err = loadProfileSkills(db, candidate.FreshGradProfileID); err != nil {
+		log.Printf("Error loading skills of candidate %s: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": candidate})
+}
+
+// ContactRequestCreate asks a fresh grad the employer can find to share their contact details with the
+// employer's team, optionally about one of the team's jobs
+func ContactRequestCreate(c *gin.Context) {
+	profileID := c.Param("profile-id")
+	employerID := c.MustGet("employer_id")
+
+	var request struct {
+		JobID   *int   `json:"job_id"`
+		Message string `json:"message"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	request.Message = strings.TrimSpace(request.Message)
+	if len([]rune(request.Message)) > services.MaxContactMessageLength {
+		respondValidationErrors(c, services.FieldErrors{"message": fmt.Sprintf("must be at most %d characters", services.MaxContactMessageLength)})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	// Employers without a company ask for themselves; team members need a role that can recruit
+	input := services.ContactRequestInput{JobID: request.JobID, Message: request.Message}
+	companyID, role, err := companyMembership(db, employerID)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		log.Printf("Error checking company membership for employer %v: %v", employerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	case !hasRole(role, jobEditorRoles):
+		log.Printf("Employer %v with team role '%s' attempted to request contact details", employerID, role)
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": "Insufficient team permissions"})
+		return
+	default:
+		input.CompanyID = &companyID
+	}
+	if request.JobID != nil && !requireJobAccess(c, db, employerID, strconv.Itoa(*request.JobID)) {
+		return
+	}
+
+	visibleCondition, visibleArgs := candidateVisibleCondition(employerID)
+	sharedCondition, sharedArgs := contactSharedCondition(employerID)
+	query := "SELECT f.freshgradprofile_id, " + sharedCondition + " FROM freshgradprofiles f " +
+		"INNER JOIN users u ON u.user_id = f.user_id " +
+		"WHERE f.freshgradprofile_id = ? AND " + activeCandidateCondition + " AND " + visibleCondition
+	args := append(append(sharedArgs, profileID), visibleArgs...)
+	var shared bool
+	if err := db.QueryRow(query, args...).Scan(&input.ProfileID, &shared); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Candidate not found"})
+			return
+		}
+		log.Printf("Error loading candidate %s: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if shared {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": services.ErrContactShared.Error()})
+		return
+	}
+	input.EmployerID, _ = employerID.(int)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	requestID, err := services.CreateContactRequest(tx, input)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if !services.RespondPrivacyError(c, err) {
+			log.Printf("Error requesting contact with candidate %s: %v", profileID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to send contact request"})
+		}
+		return
+	}
+
+	log.Printf("Employer %v requested contact with candidate %s", employerID, profileID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Contact request sent successfully", "contact_request_id": requestID})
+}
+
+// ContactRequestViews lists the contact requests sent by the employer's team, newest first, optionally filtered
+// by status
+func ContactRequestViews(c *gin.Context) {
+	employerID := c.MustGet("employer_id")
+
+	condition, args := jobAccessCondition("cr", employerID)
+	conditions := []string{condition}
+	if value := strings.TrimSpace(c.Query("status")); value != "" {
+		status, ok := services.ParseContactRequestStatus(value)
+		if !ok {
+			respondValidationErrors(c, services.FieldErrors{"status": "is not a valid contact request status"})
+			return
+		}
+		conditions = append(conditions, "cr.status = ?")
+		args = append(args, string(status))
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	requests, err := services.LoadContactRequests(db, strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		log.Printf("Error loading contact requests of employer %v: %v", employerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": requests})
+}
@@ -0,0 +1,297 @@
+package employer
+
+import (
+	"database/sql"
+	services "fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Company is the employer's view of their company profile
+type Company struct {
+	ID          int     `json:"company_id"`
+	OwnerID     int     `json:"owner_id"`
+	Name        string  `json:"name"`
+	LogoURL     string  `json:"logo_url"`
+	Industry    string  `json:"industry"`
+	CompanySize string  `json:"company_size"`
+	Website     string  `json:"website"`
+	Description string  `json:"description"`
+	Address     string  `json:"address"`
+	Verified    bool    `json:"verified"`
+	VerifiedAt  *string `json:"verified_at"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+	TeamRole    string  `json:"team_role"`
+}
+
+// CompanyCreate creates the company profile owned by the employer
+func CompanyCreate(c *gin.Context) {
+	var companyRequest struct {
+		Name        string `json:"name" binding:"required,max=255"`
+		LogoURL     string `json:"logo_url" binding:"omitempty,url"`
+		Industry    string `json:"industry"`
+		CompanySize string `json:"company_size" binding:"omitempty,oneof=1-10 11-50 51-200 201-500 501-1000 1000+"`
+		Website     string `json:"website" binding:"omitempty,url"`
+		Description string `json:"description"`
+		Address     string `json:"address"`
+	}
+
+	log.Println("Received company creation request")
+
+	if err := c.ShouldBindJSON(&companyRequest); err != nil {
+		log.Printf("Error binding company creation request: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	// Retrieve employer_id from the context
+	employerID, exists := c.Get("employer_id")
+	if !exists {
+		log.Println("Employer ID not found in context")
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Employer ID not found"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	// An employer belongs to at most one company
+	var companyExists bool
+	if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM company_members WHERE user_id = ?)", employerID).Scan(&companyExists); err != nil {
+		log.Printf("Error checking existing company for employer %v: %v", employerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if companyExists {
+		log.Printf("Employer %v already has a company", employerID)
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": "Company already exists"})
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO companies (
+		owner_id, name, logo_url, industry, company_size, website, description, address
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := tx.Exec(query,
+		employerID,
+		companyRequest.Name,
+		companyRequest.LogoURL,
+		companyRequest.Industry,
+		companyRequest.CompanySize,
+		companyRequest.Website,
+		companyRequest.Description,
+		companyRequest.Address,
+	)
+	if err != nil {
+		log.Printf("Failed to create company for employer %v: %v", employerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create company"})
+		return
+	}
+
+	companyID, err := result.LastInsertId()
+	if err != nil {
+		log.Printf("Error retrieving created company ID for employer %v: %v", employerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create company"})
+		return
+	}
+
+	// The creator becomes the company's first owner
+	if _, err := tx.Exec("INSERT INTO company_members (company_id, user_id, role) VALUES (?, ?, ?)", companyID, employerID, teamRoleOwner); err != nil {
+		log.Printf("Error adding owner to company %d: %v", companyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create company"})
+		return
+	}
+
+	// Link the employer's existing jobs to the new company
+	if _, err := tx.Exec("UPDATE jobs SET company_id = ? WHERE employer_id = ? AND company_id IS NULL", companyID, employerID); err != nil {
+		log.Printf("Error linking jobs to company %d: %v", companyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create company"})
+		return
+	}
+
+	// Record the created company in the audit log
+	entry := services.NewAuditEntry(c, employerID, "employer", "company.create", "company", companyID)
+	if entry.After, err = services.SnapshotRow(tx, services.CompanySnapshotQuery, companyID); err == nil {
+		err = services.RecordAudit(tx, entry)
+	}
+	if err != nil {
+		log.Printf("Error recording audit log for company %d: %v", companyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create company"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error committing transaction"})
+		return
+	}
+
+	log.Printf("Company %d created successfully for employer %v", companyID, employerID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Company created successfully", "company_id": companyID})
+}
+
+// CompanyUpdate updates the employer's company profile
+func CompanyUpdate(c *gin.Context) {
+	var companyRequest struct {
+		Name        *string `json:"name" binding:"omitempty,max=255"`
+		LogoURL     *string `json:"logo_url" binding:"omitempty,url"`
+		Industry    *string `json:"industry"`
+		CompanySize *string `json:"company_size" binding:"omitempty,oneof=1-10 11-50 51-200 201-500 501-1000 1000+"`
+		Website     *string `json:"website" binding:"omitempty,url"`
+		Description *string `json:"description"`
+		Address     *string `json:"address"`
+	}
+
+	log.Println("Received company update request")
+
+	if err := c.ShouldBindJSON(&companyRequest); err != nil {
+		log.Printf("Error binding company update request: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	employerID, exists := c.Get("employer_id")
+	if !exists {
+		log.Println("Employer ID not found in context")
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Employer ID not found in context"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	// Only owners may edit the company profile
+	companyID, _, ok := requireCompanyRole(c, db, employerID, teamRoleOwner)
+	if !ok {
+		return
+	}
+
+	// Prepare fields to update
+	updateFields := []string{}
+	updateValues := []interface{}{}
+
+	if companyRequest.Name != nil {
+		updateFields = append(updateFields, "name = ?")
+		updateValues = append(updateValues, *companyRequest.Name)
+	}
+	if companyRequest.LogoURL != nil {
+		updateFields = append(updateFields, "logo_url = ?")
+		updateValues = append(updateValues, *companyRequest.LogoURL)
+	}
+	if companyRequest.Industry != nil {
+		updateFields = append(updateFields, "industry = ?")
+		updateValues = append(updateValues, *companyRequest.Industry)
+	}
+	if companyRequest.CompanySize != nil {
+		updateFields = append(updateFields, "company_size = ?")
+		updateValues = append(updateValues, *companyRequest.CompanySize)
+	}
+	if companyRequest.Website != nil {
+		updateFields = append(updateFields, "website = ?")
+		updateValues = append(updateValues, *companyRequest.Website)
+	}
+	if companyRequest.Description != nil {
+		updateFields = append(updateFields, "description = ?")
+		updateValues = append(updateValues, *companyRequest.Description)
+	}
+	if companyRequest.Address != nil {
+		updateFields = append(updateFields, "address = ?")
+		updateValues = append(updateValues, *companyRequest.Address)
+	}
+
+	if len(updateFields) == 0 {
+		log.Printf("No fields to update for company %d", companyID)
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "No fields to update"})
+		return
+	}
+
+	// Changing the company's identity requires admin verification again
+	if companyRequest.Name != nil || companyRequest.Website != nil {
+		updateFields = append(updateFields, "verified = FALSE", "verified_at = NULL", "verified_by = NULL")
+	}
+
+	updateQuery := "UPDATE companies SET " + strings.Join(updateFields, ", ") + " WHERE company_id = ?"
+	updateValues = append(updateValues, companyID)
+
+	entry := services.NewAuditEntry(c, employerID, "employer", "company.update", "company", companyID)
+	if err := services.ExecWithAudit(db, entry, services.CompanySnapshotQuery, updateQuery, updateValues...); err != nil {
+		log.Printf("Failed to update company %d: %v", companyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update company"})
+		return
+	}
+
+	log.Printf("Company %d updated successfully", companyID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Company updated successfully"})
+}
+
+// CompanyView returns the employer's company profile
+func CompanyView(c *gin.Context) {
+	employerID, exists := c.Get("employer_id")
+	if !exists {
+		log.Println("Employer ID not found in context")
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Employer ID not found in context"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	query := "SELECT co.company_id, co.owner_id, co.name, co.logo_url, co.industry, co.company_size, co.website, " +
+		"co.description, co.address, co.verified, co.verified_at, co.created_at, co.updated_at, m.role " +
+		"FROM companies co INNER JOIN company_members m ON m.company_id = co.company_id WHERE m.user_id = ?"
+
+	var company Company
+	if err := db.QueryRow(query, employerID).Scan(
+		&company.ID, &company.OwnerID, &company.Name, &company.LogoURL, &company.Industry, &company.CompanySize,
+		&company.Website, &company.Description, &company.Address, &company.Verified, &company.VerifiedAt,
+		&company.CreatedAt, &company.UpdatedAt, &company.TeamRole,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("Company not found for employer %v", employerID)
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Company not found"})
+			return
+		}
+		log.Printf("Row scan error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": company})
+}
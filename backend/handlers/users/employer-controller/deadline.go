@@ -0,0 +1,103 @@
+package employer
+
+import (
+	"database/sql"
+	services "fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobDeadlineUpdate extends a job's application deadline and optionally reopens a closed job
+func JobDeadlineUpdate(c *gin.Context) {
+	jobID := c.Param("job-id")
+
+	var deadlineRequest struct {
+		ApplicationDeadline string `json:"application_deadline" binding:"required"`
+		Reopen              bool   `json:"reopen"`
+	}
+
+	log.Printf("Received deadline update request for job ID: %s", jobID)
+
+	if err := c.ShouldBindJSON(&deadlineRequest); err != nil {
+		log.Printf("Error binding deadline request for job ID %s: %v", jobID, err)
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	deadline, err := services.ParseDeadline(deadlineRequest.ApplicationDeadline)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid application deadline", "details": err.Error()})
+		return
+	}
+	if deadline.Before(services.DeadlineToday(time.Now())) {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Application deadline must not be in the past"})
+		return
+	}
+
+	employerID, exists := c.Get("employer_id")
+	if !exists {
+		log.Printf("Employer ID not found in context for deadline update (Job ID: %s)", jobID)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Employer ID not found in context"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	accessCondition, accessArgs := jobAccessCondition("", employerID, jobEditorRoles...)
+	var jobStatus services.JobStatus
+	checkQuery := "SELECT job_status FROM jobs WHERE job_id = ? AND " + accessCondition
+	if err := db.QueryRow(checkQuery, append([]interface{}{jobID}, accessArgs...)...).Scan(&jobStatus); err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("Job not found or not owned by employer (Job ID: %s, Employer ID: %v)", jobID, employerID)
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Job not found"})
+			return
+		}
+		log.Printf("Error checking job (Job ID: %s): %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+
+	if jobStatus == services.JobStatusFilled {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": "Every position of this job is filled; raise positions to reopen it"})
+		return
+	}
+	if jobStatus != services.JobStatusOpen && !deadlineRequest.Reopen {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Job is not open; set reopen to true to reopen it"})
+		return
+	}
+
+	// A new deadline gets a fresh expiry notice
+	action := "job.extend"
+	updateQuery := "UPDATE jobs SET application_deadline = ?, expiry_notified_at = NULL"
+	updateValues := []interface{}{deadline.Format("2006-01-02")}
+	if deadlineRequest.Reopen && jobStatus != services.JobStatusOpen {
+		action = "job.reopen"
+		updateQuery += ", job_status = ?"
+		updateValues = append(updateValues, services.JobStatusOpen)
+	}
+	updateQuery += " WHERE job_id = ?"
+	updateValues = append(updateValues, jobID)
+
+	entry := services.NewAuditEntry(c, employerID, "employer", action, "job", jobID)
+	if err := services.ExecWithAudit(db, entry, services.JobSnapshotQuery, updateQuery, updateValues...); err != nil {
+		log.Printf("Failed to update deadline (Job ID: %s): %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update application deadline"})
+		return
+	}
+
+	log.Printf("Job %s deadline set to %s (%s)", jobID, deadline.Format("2006-01-02"), action)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Application deadline updated successfully"})
+}
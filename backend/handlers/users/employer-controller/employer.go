@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
@@ -34,6 +35,24 @@ import (
 // TODO: Save applicant profiles ✅
 // บันทึกโปรไฟล์ผู้สมัครที่สนใจไว้เพื่อพิจารณาภายหลัง
 
+// TODO: Company profile ✅
+// จัดการข้อมูลบริษัท เช่น ชื่อ โลโก้ อุตสาหกรรม และที่อยู่ พร้อมเชื่อมโยงกับประกาศงาน
+
+// TODO: Company team ✅
+// เชิญเจ้าหน้าที่ HR หลายคนเข้าร่วมบริษัท โดยกำหนดบทบาท owner, recruiter หรือ viewer
+
+// TODO: Interview scheduling ✅
+// นัดสัมภาษณ์ผู้สมัคร เสนอช่วงเวลา ตรวจสอบเวลาชนกัน และส่งออกเป็นไฟล์ปฏิทิน .ics
+
+// TODO: Messaging ✅
+// ส่งข้อความและไฟล์แนบกับผู้สมัครในแต่ละใบสมัคร พร้อมสถานะการอ่านและจำนวนข้อความที่ยังไม่อ่าน
+
+// TODO: Job offers ✅
+// ยื่นข้อเสนองานให้ผู้สมัคร กำหนดเงินเดือนในช่วงที่ประกาศ วันเริ่มงาน และวันหมดอายุของข้อเสนอ
+
+// TODO: Candidate discovery ✅
+// ดูโปรไฟล์ผู้สมัครที่อนุญาตให้ค้นหา และขอดูช่องทางติดต่อ ซึ่งจะแสดงเมื่อผู้สมัครยอมรับหรือใบสมัครถึงขั้นที่กำหนด
+
 // AuthMiddleware checks for employer role in the JWT and retrieves employer_id
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -84,33 +103,19 @@ func AuthMiddleware() gin.HandlerFunc {
 
 // JobCreate handles job creation requests
 func JobCreate(c *gin.Context) {
-	var jobRequest struct {
-		Title               string  `json:"title" binding:"required"`
-		Job_Category        string  `json:"job_category" binding:"required"`
-		Job_Type            string  `json:"job_type" binding:"required"` // Enum: Full-time, Contract
-		Min_Salary          float64 `json:"min_salary" binding:"required"`
-		Max_Salary          float64 `json:"max_salary" binding:"required"`
-		Min_Experience      int     `json:"min_experience" binding:"required"`
-		Max_Experience      int     `json:"max_experience" binding:"required"`
-		Job_Responsibility  string  `json:"job_responsibility" binding:"required"`
-		Qualification       string  `json:"qualification" binding:"required"`
-		Benefits            string  `json:"benefits" binding:"required"`
-		Job_Description     string  `json:"job_description" binding:"required"`
-		Location            string  `json:"location" binding:"required"`
-		PostedBy            string  `json:"posted_by" binding:"required"`
-		ApplicationDeadline string  `json:"application_deadline" binding:"required"`
-		JobStatus           string  `json:"job_status" binding:"required"`
-		SkillsRequired      string  `json:"skills_required" binding:"required"`
-		JobLevel            string  `json:"job_level" binding:"required"`
-	}
-
 	// Log the request body for debugging
 	log.Println("Received job creation request")
 
-	// Bind the request body to the jobRequest struct
-	if err := c.ShouldBindJSON(&jobRequest); err != nil {
-		log.Printf("Error binding job creation request: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+	// Bind and validate the request against the job model
+	var jobRequest services.JobInput
+	if !bindJobInput(c, &jobRequest) {
+		return
+	}
+
+	job, fieldErrors := services.BuildJob(nil, jobRequest, time.Now())
+	if len(fieldErrors) > 0 {
+		log.Printf("Job creation request failed validation: %v", fieldErrors)
+		respondValidationErrors(c, fieldErrors)
 		return
 	}
 
@@ -160,34 +165,48 @@ func JobCreate(c *gin.Context) {
 		return
 	}
 
+	// Jobs belong to the employer's company; viewers cannot post jobs
+	var companyID interface{}
+	memberCompanyID, teamRole, err := companyMembership(db, employerID)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Error checking company membership for employer %v: %v", employerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if err == nil {
+		if !hasRole(teamRole, jobEditorRoles) {
+			log.Printf("Employer %v with team role '%s' attempted to create a job", employerID, teamRole)
+			c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": "Insufficient team permissions"})
+			return
+		}
+		companyID = memberCompanyID
+	}
+
+	// Catalogue skills replace the free-text skills_required
+	skillIDs, ok := resolveJobSkills(c, db, &jobRequest)
+	if !ok {
+		return
+	}
+	if jobRequest.Skills != nil {
+		job.SkillsRequired = *jobRequest.SkillsRequired
+	}
+
 	// Prepare the query for job creation
-	query := `INSERT INTO jobs (
-		title, employer_id, job_category, job_type, min_salary, max_salary, min_experience, max_experience,
-		job_responsibility, qualification, benefits, job_description, location, posted_by,
-		application_deadline, job_status, skills_required, job_level
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	query := "INSERT INTO jobs (employer_id, company_id, " + services.JobColumns + ") " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+	// Begin a transaction so the job and its audit entry are written together
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
 
 	// Execute the query
-	_, err = db.Exec(query,
-		jobRequest.Title,
-		employerID, // Use employer_id from context
-		jobRequest.Job_Category,
-		jobRequest.Job_Type,
-		jobRequest.Min_Salary,
-		jobRequest.Max_Salary,
-		jobRequest.Min_Experience,
-		jobRequest.Max_Experience,
-		jobRequest.Job_Responsibility,
-		jobRequest.Qualification,
-		jobRequest.Benefits,
-		jobRequest.Job_Description,
-		jobRequest.Location,
-		jobRequest.PostedBy,
-		jobRequest.ApplicationDeadline,
-		jobRequest.JobStatus,
-		jobRequest.SkillsRequired,
-		jobRequest.JobLevel,
-	)
+	// employer_id comes from the context and company_id links the job to the employer's company, if any
+	result, err := tx.Exec(query, append([]interface{}{employerID, companyID}, job.Values()...)...)
 
 	// Handle potential errors during job creation
 	if err != nil {
@@ -196,42 +215,54 @@ func JobCreate(c *gin.Context) {
 		return
 	}
 
+	jobID, err := result.LastInsertId()
+	if err != nil {
+		log.Printf("Error retrieving created job ID for employer %v: %v", employerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create job"})
+		return
+	}
+
+	if skillIDs != nil {
+		if err := services.SetJobSkills(tx, jobID, skillIDs); err != nil {
+			log.Printf("Error linking skills to job %d: %v", jobID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create job"})
+			return
+		}
+	}
+
+	// Record the created job in the audit log
+	entry := services.NewAuditEntry(c, employerID, "employer", "job.create", "job", jobID)
+	if entry.After, err = services.SnapshotRow(tx, services.JobSnapshotQuery, jobID); err == nil {
+		err = services.RecordAudit(tx, entry)
+	}
+	if err != nil {
+		log.Printf("Error recording audit log for job %d: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create job"})
+		return
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error committing transaction"})
+		return
+	}
+
 	// Job created successfully
 	log.Printf("Job created successfully for employer %v", employerID)
-	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Job created successfully"})
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Job created successfully", "job_id": jobID})
 }
 
 // JobUpdate handles job update requests
 func JobUpdate(c *gin.Context) {
 	jobID := c.Param("job-id") // Get job ID from the URL parameters
 
-	var jobRequest struct {
-		Title               *string  `json:"title"`
-		Job_Category        *string  `json:"job_category"`
-		Job_Type            *string  `json:"job_type"` // Enum: Full-time, Contract
-		Min_Salary          *float64 `json:"min_salary"`
-		Max_Salary          *float64 `json:"max_salary"`
-		Min_Experience      *int     `json:"min_experience"`
-		Max_Experience      *int     `json:"max_experience"`
-		Job_Responsibility  *string  `json:"job_responsibility"`
-		Qualification       *string  `json:"qualification"`
-		Benefits            *string  `json:"benefits"`
-		Job_Description     *string  `json:"job_description"`
-		Location            *string  `json:"location"`
-		PostedBy            *string  `json:"posted_by"`
-		ApplicationDeadline *string  `json:"application_deadline"`
-		JobStatus           *string  `json:"job_status"`
-		SkillsRequired      *string  `json:"skills_required"`
-		JobLevel            *string  `json:"job_level"`
-	}
-
 	// Log the incoming update request
 	log.Printf("Received job update request for job ID: %s", jobID)
 
-	// Bind the JSON request to the jobRequest struct
-	if err := c.ShouldBindJSON(&jobRequest); err != nil {
-		log.Printf("Error binding update request for job ID %s: %v", jobID, err)
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+	// Bind the JSON request to the job model; absent fields stay unchanged
+	var jobRequest services.JobInput
+	if !bindJobInput(c, &jobRequest) {
 		return
 	}
 
@@ -281,92 +312,41 @@ func JobUpdate(c *gin.Context) {
 		return
 	}
 
-	// Check if the job exists and belongs to the employer
-	var jobExists bool
-	checkQuery := "SELECT EXISTS(SELECT 1 FROM jobs WHERE job_id = ? AND employer_id = ?)"
-	if err := db.QueryRow(checkQuery, jobID, employerID).Scan(&jobExists); err != nil {
-		log.Printf("Error checking job existence (Job ID: %s, Employer ID: %v): %v", jobID, employerID, err)
+	// Load the job if it exists and the employer's team may edit it
+	var current services.Job
+	accessCondition, accessArgs := jobAccessCondition("", employerID, jobEditorRoles...)
+	loadQuery := "SELECT " + services.JobColumns + ", positions_filled FROM jobs WHERE job_id = ? AND " + accessCondition
+	scanTargets := append(current.ScanTargets(), &current.PositionsFilled)
+	if err := db.QueryRow(loadQuery, append([]interface{}{jobID}, accessArgs...)...).Scan(scanTargets...); err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("Job not found or not owned by employer (Job ID: %s, Employer ID: %v)", jobID, employerID)
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Job not found"})
+			return
+		}
+		log.Printf("Error loading job (Job ID: %s, Employer ID: %v): %v", jobID, employerID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
 		return
 	}
 
-	if !jobExists {
-		log.Printf("Job not found or not owned by employer (Job ID: %s, Employer ID: %v)", jobID, employerID)
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Job not found"})
+	// Catalogue skills replace the free-text skills_required
+	skillIDs, ok := resolveJobSkills(c, db, &jobRequest)
+	if !ok {
 		return
 	}
 
-	// Prepare fields to update
-	updateFields := []string{}
-	updateValues := []interface{}{}
-
-	if jobRequest.Title != nil {
-		updateFields = append(updateFields, "title = ?")
-		updateValues = append(updateValues, *jobRequest.Title)
-	}
-	if jobRequest.Job_Category != nil {
-		updateFields = append(updateFields, "job_category = ?")
-		updateValues = append(updateValues, *jobRequest.Job_Category)
-	}
-	if jobRequest.Job_Type != nil {
-		updateFields = append(updateFields, "job_type = ?")
-		updateValues = append(updateValues, *jobRequest.Job_Type)
-	}
-	if jobRequest.Min_Salary != nil {
-		updateFields = append(updateFields, "min_salary = ?")
-		updateValues = append(updateValues, *jobRequest.Min_Salary)
-	}
-	if jobRequest.Max_Salary != nil {
-		updateFields = append(updateFields, "max_salary = ?")
-		updateValues = append(updateValues, *jobRequest.Max_Salary)
-	}
-	if jobRequest.Min_Experience != nil {
-		updateFields = append(updateFields, "min_experience = ?")
-		updateValues = append(updateValues, *jobRequest.Min_Experience)
-	}
-	if jobRequest.Max_Experience != nil {
-		updateFields = append(updateFields, "max_experience = ?")
-		updateValues = append(updateValues, *jobRequest.Max_Experience)
-	}
-	if jobRequest.Job_Responsibility != nil {
-		updateFields = append(updateFields, "job_responsibility = ?")
-		updateValues = append(updateValues, *jobRequest.Job_Responsibility)
-	}
-	if jobRequest.Qualification != nil {
-		updateFields = append(updateFields, "qualification = ?")
-		updateValues = append(updateValues, *jobRequest.Qualification)
-	}
-	if jobRequest.Benefits != nil {
-		updateFields = append(updateFields, "benefits = ?")
-		updateValues = append(updateValues, *jobRequest.Benefits)
-	}
-	if jobRequest.Job_Description != nil {
-		updateFields = append(updateFields, "job_description = ?")
-		updateValues = append(updateValues, *jobRequest.Job_Description)
-	}
-	if jobRequest.Location != nil {
-		updateFields = append(updateFields, "location = ?")
-		updateValues = append(updateValues, *jobRequest.Location)
-	}
-	if jobRequest.PostedBy != nil {
-		updateFields = append(updateFields, "posted_by = ?")
-		updateValues = append(updateValues, *jobRequest.PostedBy)
+	// Validate the job as it will be after the update, so cross-field rules see both old and new values
+	job, fieldErrors := services.BuildJob(&current, jobRequest, time.Now())
+	if len(fieldErrors) > 0 {
+		log.Printf("Job update request for job ID %s failed validation: %v", jobID, fieldErrors)
+		respondValidationErrors(c, fieldErrors)
+		return
 	}
+
+	// Prepare fields to update
+	updateFields, updateValues := jobRequest.ChangedColumns(job)
 	if jobRequest.ApplicationDeadline != nil {
-		updateFields = append(updateFields, "application_deadline = ?")
-		updateValues = append(updateValues, *jobRequest.ApplicationDeadline)
-	}
-	if jobRequest.JobStatus != nil {
-		updateFields = append(updateFields, "job_status = ?")
-		updateValues = append(updateValues, *jobRequest.JobStatus)
-	}
-	if jobRequest.SkillsRequired != nil {
-		updateFields = append(updateFields, "skills_required = ?")
-		updateValues = append(updateValues, *jobRequest.SkillsRequired)
-	}
-	if jobRequest.JobLevel != nil {
-		updateFields = append(updateFields, "job_level = ?")
-		updateValues = append(updateValues, *jobRequest.JobLevel)
+		// A new deadline gets a fresh expiry notice
+		updateFields = append(updateFields, "expiry_notified_at = NULL")
 	}
 
 	// Ensure there are fields to update
@@ -377,11 +357,28 @@ func JobUpdate(c *gin.Context) {
 	}
 
 	// Build the update query
-	updateQuery := "UPDATE jobs SET " + strings.Join(updateFields, ", ") + " WHERE job_id = ? AND employer_id = ?"
-	updateValues = append(updateValues, jobID, employerID) // Add jobID and employerID to the query
+	updateQuery := "UPDATE jobs SET " + strings.Join(updateFields, ", ") + " WHERE job_id = ? AND " + accessCondition
+	updateValues = append(updateValues, jobID) // Add jobID and the team access check to the query
+	updateValues = append(updateValues, accessArgs...)
+
+	// Execute the update query, relink the job's skills and record it in the audit log in one transaction
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
 
-	// Execute the update query
-	if _, err := db.Exec(updateQuery, updateValues...); err != nil {
+	entry := services.NewAuditEntry(c, employerID, "employer", "job.update", "job", jobID)
+	err = services.ExecWithAuditTx(tx, entry, services.JobSnapshotQuery, updateQuery, updateValues...)
+	if err == nil && skillIDs != nil {
+		err = services.SetJobSkills(tx, jobID, skillIDs)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
 		log.Printf("Failed to update job (Job ID: %s, Employer ID: %v): %v", jobID, employerID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update job", "details": err.Error()})
 		return
@@ -448,10 +445,11 @@ func JobDelete(c *gin.Context) {
 		return
 	}
 
-	// Check if the job exists and belongs to the employer
+	// Check if the job exists and the employer's team may delete it
 	var jobExists bool
-	checkQuery := "SELECT EXISTS(SELECT 1 FROM jobs WHERE job_id = ? AND employer_id = ?)"
-	err = db.QueryRow(checkQuery, jobID, employerID).Scan(&jobExists)
+	accessCondition, accessArgs := jobAccessCondition("", employerID, jobEditorRoles...)
+	checkQuery := "SELECT EXISTS(SELECT 1 FROM jobs WHERE job_id = ? AND " + accessCondition + ")"
+	err = db.QueryRow(checkQuery, append([]interface{}{jobID}, accessArgs...)...).Scan(&jobExists)
 	if err != nil {
 		log.Printf("Error querying job existence: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -481,9 +479,22 @@ func JobDelete(c *gin.Context) {
 		return
 	}
 
+	// Snapshot the job before it is deleted for the audit log
+	entry := services.NewAuditEntry(c, employerID, "employer", "job.delete", "job", jobID)
+	entry.Before, err = services.SnapshotRow(tx, services.JobSnapshotQuery, jobID)
+	if err != nil {
+		log.Printf("Error reading job snapshot (Job ID: %s): %v", jobID, err)
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Error deleting job",
+		})
+		return
+	}
+
 	// Perform the job deletion within the transaction
-	deleteQuery := "DELETE FROM jobs WHERE job_id = ? AND employer_id = ?"
-	_, err = tx.Exec(deleteQuery, jobID, employerID)
+	deleteQuery := "DELETE FROM jobs WHERE job_id = ? AND " + accessCondition
+	_, err = tx.Exec(deleteQuery, append([]interface{}{jobID}, accessArgs...)...)
 	if err != nil {
 		log.Printf("Error deleting job (Job ID: %s): %v", jobID, err)
 		tx.Rollback() // Roll back the transaction if something goes wrong
@@ -494,6 +505,17 @@ func JobDelete(c *gin.Context) {
 		return
 	}
 
+	// Record the deletion in the same transaction
+	if err := services.RecordAudit(tx, entry); err != nil {
+		log.Printf("Error recording audit log (Job ID: %s): %v", jobID, err)
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Error deleting job",
+		})
+		return
+	}
+
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
 		log.Printf("Error committing transaction: %v", err)
@@ -594,6 +616,9 @@ func JobViews(c *gin.Context) {
 		JobStatus           string  `json:"job_status"`
 		SkillsRequired      string  `json:"skills_required"`
 		JobLevel            string  `json:"job_level"`
+		CompanyID           *int    `json:"company_id"`
+		Positions           int     `json:"positions"`
+		PositionsFilled     int     `json:"positions_filled"`
 	}
 
 	// Filters
@@ -608,15 +633,16 @@ func JobViews(c *gin.Context) {
 	createdAfter := c.Query("created_after")   // Filter by jobs created after a certain date
 	createdBefore := c.Query("created_before") // Filter by jobs created before a certain date
 
-	// Prepare the query
+	// Prepare the query, limited to jobs of the employer's company
 	var query string
 	var args []interface{}
+	accessCondition, accessArgs := jobAccessCondition("", employerID)
 
 	if jobID == "" {
 		query = "SELECT job_id, title, employer_id, job_category, job_type, min_salary, max_salary, min_experience, " +
 			"max_experience, job_responsibility, qualification, benefits, job_description, approved, created_at, " +
-			"location, posted_by, application_deadline, job_status, skills_required, job_level FROM jobs WHERE employer_id = ?"
-		args = append(args, employerID)
+			"location, posted_by, application_deadline, job_status, skills_required, job_level, company_id, positions, positions_filled FROM jobs WHERE " + accessCondition
+		args = append(args, accessArgs...)
 
 		// Add filters to the query
 		if jobType != "" {
@@ -668,8 +694,9 @@ func JobViews(c *gin.Context) {
 		// If a specific job ID is provided, retrieve the job by ID
 		query = "SELECT job_id, title, employer_id, job_category, job_type, min_salary, max_salary, min_experience, " +
 			"max_experience, job_responsibility, qualification, benefits, job_description, approved, created_at, " +
-			"location, posted_by, application_deadline, job_status, skills_required, job_level FROM jobs WHERE job_id = ? AND employer_id = ?"
-		args = append(args, jobID, employerID)
+			"location, posted_by, application_deadline, job_status, skills_required, job_level, company_id, positions, positions_filled FROM jobs WHERE job_id = ? AND " + accessCondition
+		args = append(args, jobID)
+		args = append(args, accessArgs...)
 	}
 
 	// Execute the query
@@ -692,7 +719,8 @@ func JobViews(c *gin.Context) {
 			&job.ID, &job.Title, &job.EmployerID, &job.JobCategory, &job.JobType, &job.MinSalary,
 			&job.MaxSalary, &job.MinExperience, &job.MaxExperience, &job.JobResponsibility, &job.Qualification,
 			&job.Benefits, &job.JobDescription, &job.Approved, &job.CreatedAt, &job.Location, &job.PostedBy,
-			&job.ApplicationDeadline, &job.JobStatus, &job.SkillsRequired, &job.JobLevel,
+			&job.ApplicationDeadline, &job.JobStatus, &job.SkillsRequired, &job.JobLevel, &job.CompanyID,
+			&job.Positions, &job.PositionsFilled,
 		); err != nil {
 			log.Printf("Row scan error: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -745,15 +773,6 @@ func ApplicationViews(c *gin.Context) {
 		}
 	}()
 
-	// Struct to hold application data
-	type Application struct {
-		ApplicationID      int    `json:"application_id"`
-		JobID              int    `json:"job_id"`
-		FreshGradProfileID int    `json:"fresh_grad_profile_id"`
-		FreshGradResume    string `json:"resume_file_link"`
-		Favorited          bool   `json:"favorited"`
-	}
-
 	// Retrieve employer_id from the context
 	employerID, exists := c.Get("employer_id")
 	if !exists {
@@ -790,99 +809,66 @@ func ApplicationViews(c *gin.Context) {
 		return
 	}
 
-	// If no application ID is provided, fetch all applications for a job
+	// If no application ID is provided, search the applications for the job
 	if applicationID == "" {
-		log.Printf("Fetching all applications for jobID: %s", jobID)
+		log.Printf("Fetching applications for jobID: %s", jobID)
+		listApplications(c, db, employerID, jobID)
+		return
+	}
 
-		query := `
-									SELECT a.application_id, a.job_id, a.freshgradprofile_id, a.favorited, f.resume_file_link
-									FROM applications a 
-									INNER JOIN jobs j ON a.job_id = j.job_id 
-									INNER JOIN freshgradprofiles f ON a.freshgradprofile_id = f.freshgradprofile_id
-									WHERE j.employer_id = ? AND a.job_id = ?
-					`
+	// Fetch specific application based on applicationID
+	log.Printf("Fetching application with applicationID: %s", applicationID)
 
-		rows, err := db.Query(query, employerID, jobID)
-		if err != nil {
-			log.Printf("Query execution error: %v", err)
+	// The detail carries the profile, answers, history and the team's notes
+	application, err := loadApplicationDetail(db, employerID, jobID, applicationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("Application not found for applicationID: %s", applicationID)
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Application not found"})
+		} else {
+			log.Printf("Error loading application %s: %v", applicationID, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
-			return
-		}
-		defer rows.Close()
-
-		var applications []Application
-
-		for rows.Next() {
-			var application Application
-			if err := rows.Scan(&application.ApplicationID, &application.JobID, &application.FreshGradProfileID, &application.Favorited, &application.FreshGradResume); err != nil {
-				log.Printf("Row scan error: %v", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
-				return
-			}
-			applications = append(applications, application)
-		}
-
-		// Check for any errors during row iteration
-		if err := rows.Err(); err != nil {
-			log.Printf("Rows iteration error: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error processing applications"})
-			return
 		}
+		return
+	}
 
-		c.JSON(http.StatusOK, gin.H{
-			"status": "success",
-			"data":   applications,
-		})
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   application,
+	})
+}
 
-	} else {
-		// Fetch specific application based on applicationID
-		log.Printf("Fetching application with applicationID: %s", applicationID)
-
-		query := `
-									SELECT a.application_id, a.job_id, a.freshgradprofile_id, a.favorited, f.resume_file_link
-									FROM applications a 
-									INNER JOIN jobs j ON a.job_id = j.job_id 
-									INNER JOIN freshgradprofiles f ON a.freshgradprofile_id = f.freshgradprofile_id
-									WHERE a.application_id = ? AND j.employer_id = ? AND a.job_id = ?
-					`
-
-		row := db.QueryRow(query, applicationID, employerID, jobID)
-
-		var application Application
-		if err := row.Scan(&application.ApplicationID, &application.JobID, &application.FreshGradProfileID, &application.Favorited, &application.FreshGradResume); err != nil {
-			if err == sql.ErrNoRows {
-				log.Printf("Application not found for applicationID: %s", applicationID)
-				c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Application not found"})
-			} else {
-				log.Printf("Row scan error: %v", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
-			}
-			return
-		}
+// FavoritedController marks (PUT) or unmarks (DELETE) an application as one of the requesting recruiter's
+// favorites. Both are idempotent, and only members of the job's team can reach the application.
+func FavoritedController(c *gin.Context) {
+	favorite := c.Request.Method != http.MethodDelete
+	setFavorite(c, func(db *sql.DB) (bool, error) { return favorite, nil })
+}
 
-		c.JSON(http.StatusOK, gin.H{
-			"status": "success",
-			"data":   application,
-		})
-	}
+// FavoritedToggle is the deprecated PUT .../isFavorited, kept for older clients. It toggles the requesting
+// recruiter's favorite; clients should move to PUT and DELETE .../favorite.
+func FavoritedToggle(c *gin.Context) {
+	c.Header("Deprecation", "true")
+	c.Header("Link", "</employer/jobs/"+c.Param("job-id")+"/applications/"+c.Param("application-id")+"/favorite>; rel=\"successor-version\"")
+	setFavorite(c, func(db *sql.DB) (bool, error) {
+		var favorited bool
+		query := "SELECT " + services.FavoritedExpr + " FROM applications a WHERE a.application_id = ?"
+		err := db.QueryRow(query, c.MustGet("employer_id"), c.Param("application-id")).Scan(&favorited)
+		return !favorited, err
+	})
 }
 
-func FavoritedController(c *gin.Context) {
+// setFavorite sets the requesting recruiter's favorite on the application in the request path to the value
+// decide returns
+func setFavorite(c *gin.Context, decide func(db *sql.DB) (bool, error)) {
 	// Retrieve parameters from URL path
 	applicationID := c.Param("application-id")
 	jobID := c.Param("job-id")
+	employerID := c.MustGet("employer_id")
 
-	// Log the request details
-	log.Printf("FavoritedController invoked - applicationID: %s, jobID: %s", applicationID, jobID)
-
-	// Connect to the centralized database
-	db, err := services.ConnectDB()
-	if err != nil {
-		log.Printf("Error connecting to the database: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Unable to connect to the database. Please try again later.",
-		})
+	// Checks the employer's status and that the application belongs to a job their team can access
+	db, ok := openApplicationReview(c)
+	if !ok {
 		return
 	}
 	defer func() {
@@ -891,10 +877,8 @@ func FavoritedController(c *gin.Context) {
 		}
 	}()
 
-	// Check if the application is currently favorited
-	var isFavorited bool
-	favoritedQuery := "SELECT favorited FROM applications WHERE application_id = ?"
-	if err := db.QueryRow(favoritedQuery, applicationID).Scan(&isFavorited); err != nil {
+	favorite, err := decide(db)
+	if err != nil {
 		log.Printf("Error fetching favorited status for application %s: %v", applicationID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
@@ -903,10 +887,11 @@ func FavoritedController(c *gin.Context) {
 		return
 	}
 
-	// Toggle the favorited status
-	updateQuery := "UPDATE applications SET favorited = ? WHERE application_id = ?"
-	_, err = db.Exec(updateQuery, !isFavorited, applicationID)
-	if err != nil {
+	// Log the request details
+	log.Printf("Favorite update - applicationID: %s, jobID: %s, favorite: %v", applicationID, jobID, favorite)
+
+	// A single insert or delete per recruiter, so concurrent requests cannot lose an update
+	if err := services.SetApplicationFavorite(db, applicationID, employerID, favorite); err != nil {
 		log.Printf("Error updating favorited status for application %s: %v", applicationID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
@@ -916,10 +901,10 @@ func FavoritedController(c *gin.Context) {
 	}
 
 	// Log success and respond with success message
-	log.Printf("Successfully toggled favorited status for application %s to %v", applicationID, !isFavorited)
+	log.Printf("Application %s favorited=%v for recruiter %v", applicationID, favorite, employerID)
 	c.JSON(http.StatusOK, gin.H{
 		"status":    "success",
 		"message":   "Application favorited status updated successfully.",
-		"favorited": !isFavorited,
+		"favorited": favorite,
 	})
 }
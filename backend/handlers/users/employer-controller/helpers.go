@@ -0,0 +1,89 @@
+package employer
+
+import (
+	"database/sql"
+	services "fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkEmployerStatus verifies the employer is approved and not suspended, writing the error response if not
+func checkEmployerStatus(c *gin.Context, db *sql.DB, employerID interface{}) bool {
+	var isApproved, isSuspended bool
+	approvedQuery := "SELECT approved, suspended FROM users WHERE user_id=?"
+	if err := db.QueryRow(approvedQuery, employerID).Scan(&isApproved, &isSuspended); err != nil {
+		log.Printf("Error checking employer approval status: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error checking employer approval status"})
+		return false
+	}
+
+	if !isApproved {
+		log.Printf("Employer %v is not approved", employerID)
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": "Your account is not approved"})
+		return false
+	}
+
+	if isSuspended {
+		log.Printf("User with ID %v is suspended", employerID)
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": "Your account is suspended"})
+		return false
+	}
+
+	return true
+}
+
+// bindJobInput binds a job request body, reporting JSON type mismatches as field errors
+func bindJobInput(c *gin.Context, input *services.JobInput) bool {
+	if err := c.ShouldBindJSON(input); err != nil {
+		log.Printf("Error binding job request: %v", err)
+		if fieldErrors := services.BindingFieldErrors(err); fieldErrors != nil {
+			respondValidationErrors(c, fieldErrors)
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return false
+	}
+	return true
+}
+
+// respondValidationErrors writes a 400 response listing the field-level validation errors
+func respondValidationErrors(c *gin.Context, fieldErrors services.FieldErrors) {
+	c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": fieldErrors})
+}
+
+// resolveJobSkills maps the request's catalogue skills to skill IDs and mirrors their names into
+// skills_required, writing a validation error for unknown skills. Without skills, a free-text skills_required
+// is linked to the catalogue skills it names and its other entries stay text only. It returns nil IDs when
+// neither was sent.
+func resolveJobSkills(c *gin.Context, db *sql.DB, input *services.JobInput) ([]int, bool) {
+	if input.Skills == nil {
+		if input.SkillsRequired == nil {
+			return nil, true
+		}
+		skills, _, err := services.ResolveSkills(db, services.SplitSkillsText(*input.SkillsRequired))
+		if err != nil {
+			log.Printf("Error resolving job skills: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+			return nil, false
+		}
+		return services.SkillIDs(skills), true
+	}
+
+	skills, unknown, err := services.ResolveSkills(db, *input.Skills)
+	if err != nil {
+		log.Printf("Error resolving job skills: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return nil, false
+	}
+	if len(unknown) > 0 {
+		respondValidationErrors(c, services.FieldErrors{"skills": "unknown skills: " + strings.Join(unknown, ", ")})
+		return nil, false
+	}
+
+	names := services.SkillNames(skills)
+	input.SkillsRequired = &names
+	return services.SkillIDs(skills), true
+}
@@ -0,0 +1,322 @@
+package employer
+
+import (
+	"database/sql"
+	"fmt"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InterviewCreate proposes interview slots to the applicant of an application
+func InterviewCreate(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	employerID := c.MustGet("employer_id")
+
+	var input services.InterviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	slots, fieldErrors := input.Validate(time.Now())
+	if len(fieldErrors) > 0 {
+		respondValidationErrors(c, fieldErrors)
+		return
+	}
+
+	db, ok := openApplicationReview(c, jobEditorRoles...)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	interviewID, err := services.CreateInterview(tx, applicationID, employerID, input, slots)
+	if err == nil {
+		err = notifyCandidate(tx, applicationID, interviewID, "interview.proposed", "You have been invited to an interview for \"%s\"")
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if !services.RespondInterviewError(c, err) && !respondStatusChangeError(c, err) {
+			log.Printf("Error creating interview for application %s: %v", applicationID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create interview"})
+		}
+		return
+	}
+
+	log.Printf("Interview %d proposed for application %s by employer %v", interviewID, applicationID, employerID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Interview proposed successfully", "interview_id": interviewID})
+}
+
+// notifyCandidate notifies the applicant of an application about an interview; format takes the job title
+func notifyCandidate(tx *sql.Tx, applicationID interface{}, interviewID int64, kind, format string) error {
+	var candidateUserID int
+	var jobTitle string
+	query := "SELECT f.user_id, j.title FROM applications a " +
+		"INNER JOIN jobs j ON j.job_id = a.job_id " +
+		"INNER JOIN freshgradprofiles f ON f.freshgradprofile_id = a.freshgradprofile_id WHERE a.application_id = ?"
+	if err := tx.QueryRow(query, applicationID).Scan(&candidateUserID, &jobTitle); err != nil {
+		return err
+	}
+	data := map[string]interface{}{"interview_id": interviewID, "application_id": applicationID}
+	return services.CreateNotification(tx, candidateUserID, kind, fmt.Sprintf(format, jobTitle), data)
+}
+
+// ApplicationInterviewViews lists the interviews of an application, flagging candidate slots that clash with
+// the organizer's calendar
+func ApplicationInterviewViews(c *gin.Context) {
+	applicationID := c.Param("application-id")
+
+	db, ok := openApplicationReview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	interviews, err := services.LoadInterviews(db, "i.application_id = ?", applicationID)
+	if err == nil {
+		err = services.MarkSlotConflicts(db, interviews)
+	}
+	if err != nil {
+		log.Printf("Error loading interviews of application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": interviews})
+}
+
+// InterviewViews lists the interviews organized by the employer, filtered by status and by a from/to
+// window (RFC 3339) on the scheduled time
+func InterviewViews(c *gin.Context) {
+	employerID := c.MustGet("employer_id")
+
+	conditions := []string{"i.employer_id = ?"}
+	args := []interface{}{employerID}
+	fieldErrors := services.FieldErrors{}
+	if value := strings.TrimSpace(c.Query("status")); value != "" {
+		if status, ok := services.ParseInterviewStatus(value); ok {
+			conditions = append(conditions, "i.status = ?")
+			args = append(args, string(status))
+		} else {
+			fieldErrors["status"] = "is not a valid interview status"
+		}
+	}
+	for param, operator := range map[string]string{"from": ">=", "to": "<"} {
+		value := strings.TrimSpace(c.Query(param))
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			fieldErrors[param] = "must be an RFC 3339 time"
+			continue
+		}
+		conditions = append(conditions, "i.scheduled_at "+operator+" ?")
+		args = append(args, t.UTC().Format(services.InterviewTimeLayout))
+	}
+	if len(fieldErrors) > 0 {
+		respondValidationErrors(c, fieldErrors)
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	interviews, err := services.LoadInterviews(db, strings.Join(conditions, " AND "), args...)
+	if err == nil {
+		err = services.MarkSlotConflicts(db, interviews)
+	}
+	if err != nil {
+		log.Printf("Error loading interviews of employer %v: %v", employerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": interviews})
+}
+
+// openInterview connects to the database and checks the employer's team can access the interview in the
+// request path with one of roles, writing the error response if not. The caller closes the returned database.
+func openInterview(c *gin.Context, roles ...string) (*sql.DB, bool) {
+	interviewID := c.Param("interview-id")
+	employerID := c.MustGet("employer_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return nil, false
+	}
+	if !checkEmployerStatus(c, db, employerID) {
+		db.Close()
+		return nil, false
+	}
+
+	var jobID, applicationID string
+	query := "SELECT a.job_id, a.application_id FROM interviews i " +
+		"INNER JOIN applications a ON a.application_id = i.application_id WHERE i.interview_id = ?"
+	if err := db.QueryRow(query, interviewID).Scan(&jobID, &applicationID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Interview not found"})
+		} else {
+			log.Printf("Error loading interview %s: %v", interviewID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		}
+		db.Close()
+		return nil, false
+	}
+	if !requireApplicationAccess(c, db, employerID, jobID, applicationID, roles...) {
+		db.Close()
+		return nil, false
+	}
+	return db, true
+}
+
+// InterviewAccept schedules the interview at one of the candidate's proposed slots
+func InterviewAccept(c *gin.Context) {
+	interviewID := c.Param("interview-id")
+	employerID := c.MustGet("employer_id")
+
+	var request struct {
+		SlotID int `json:"slot_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	db, ok := openInterview(c, jobEditorRoles...)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	changeInterview(c, db, interviewID, "Interview scheduled successfully", func(tx *sql.Tx) (services.InterviewChange, error) {
+		return services.AcceptInterviewSlot(tx, interviewID, request.SlotID, services.InterviewPartyEmployer, employerID, time.Now())
+	}, "interview.scheduled", "Your interview for \"%s\" is confirmed")
+}
+
+// InterviewPropose proposes new slots, replying to the candidate's alternatives or rescheduling
+func InterviewPropose(c *gin.Context) {
+	interviewID := c.Param("interview-id")
+
+	var request struct {
+		Slots []string `json:"slots" binding:"required"`
+		Note  string   `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	slots, fieldErrors := services.ParseInterviewSlots(request.Slots, time.Now())
+	if len(fieldErrors) > 0 {
+		respondValidationErrors(c, fieldErrors)
+		return
+	}
+
+	db, ok := openInterview(c, jobEditorRoles...)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	changeInterview(c, db, interviewID, "Interview slots proposed successfully", func(tx *sql.Tx) (services.InterviewChange, error) {
+		return services.ProposeInterviewSlots(tx, interviewID, services.InterviewPartyEmployer, slots, strings.TrimSpace(request.Note))
+	}, "interview.proposed", "New interview times were proposed for \"%s\"")
+}
+
+// InterviewCancel cancels an interview with an optional reason
+func InterviewCancel(c *gin.Context) {
+	interviewID := c.Param("interview-id")
+
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	db, ok := openInterview(c, jobEditorRoles...)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	changeInterview(c, db, interviewID, "Interview cancelled successfully", func(tx *sql.Tx) (services.InterviewChange, error) {
+		return services.CloseInterview(tx, interviewID, services.InterviewPartyEmployer, strings.TrimSpace(request.Reason))
+	}, "interview.cancelled", "Your interview for \"%s\" was cancelled")
+}
+
+// changeInterview runs an interview change in a transaction and notifies the candidate; format takes the job title
+func changeInterview(c *gin.Context, db *sql.DB, interviewID, successMessage string,
+	change func(tx *sql.Tx) (services.InterviewChange, error), kind, format string) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := change(tx)
+	if err == nil {
+		data := map[string]interface{}{"interview_id": result.InterviewID, "application_id": result.ApplicationID, "status": result.Status}
+		err = services.CreateNotification(tx, result.CandidateUserID, kind, fmt.Sprintf(format, result.JobTitle), data)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if !services.RespondInterviewError(c, err) && !respondStatusChangeError(c, err) {
+			log.Printf("Error updating interview %s: %v", interviewID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update interview"})
+		}
+		return
+	}
+
+	log.Printf("Interview %s is now %s", interviewID, result.Status)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": successMessage, "interview_status": result.Status})
+}
+
+// InterviewCalendar downloads a scheduled interview as an iCalendar (.ics) file
+func InterviewCalendar(c *gin.Context) {
+	interviewID := c.Param("interview-id")
+
+	db, ok := openInterview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	interviews, err := services.LoadInterviews(db, "i.interview_id = ?", interviewID)
+	if err != nil || len(interviews) == 0 {
+		log.Printf("Error loading interview %s: %v", interviewID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	services.WriteInterviewICS(c, interviews[0])
+}
@@ -0,0 +1,112 @@
+package employer
+
+import (
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MessageViews returns the conversation of an application and marks it read for the requesting employer
+func MessageViews(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	employerID := c.MustGet("employer_id")
+
+	db, ok := openApplicationReview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	if err := services.MarkMessagesRead(db, applicationID, employerID); err != nil {
+		log.Printf("Error marking messages of application %s read: %v", applicationID, err)
+	}
+	messages, err := services.LoadMessages(db, applicationID, false)
+	if err != nil {
+		log.Printf("Error loading messages of application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": messages})
+}
+
+// MessageCreate sends a message, with optional attachments, to the applicant of an application
+func MessageCreate(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	employerID := c.MustGet("employer_id")
+
+	input, fieldErrors, err := services.ParseMessageInput(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if len(fieldErrors) > 0 {
+		respondValidationErrors(c, fieldErrors)
+		return
+	}
+
+	db, ok := openApplicationReview(c, jobEditorRoles...)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	storage, err := services.NewStorage()
+	if err != nil {
+		log.Printf("File storage error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "File storage error"})
+		return
+	}
+	messageID, err := services.SendMessage(db, storage, applicationID, employerID, services.MessageSenderEmployer, input)
+	if err != nil {
+		log.Printf("Error sending message on application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to send message"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Message sent successfully", "message_id": messageID})
+}
+
+// MessageAttachmentView downloads an attachment of an application's conversation
+func MessageAttachmentView(c *gin.Context) {
+	db, ok := openApplicationReview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	services.WriteMessageAttachment(c, db, c.Param("application-id"), false)
+}
+
+// UnreadMessageViews counts the employer's unread messages per application across the team's jobs
+func UnreadMessageViews(c *gin.Context) {
+	employerID := c.MustGet("employer_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	accessCondition, accessArgs := jobAccessCondition("j", employerID)
+	counts, err := services.UnreadMessageCounts(db, employerID, accessCondition, accessArgs...)
+	if err != nil {
+		log.Printf("Error counting unread messages of employer %v: %v", employerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count.Unread
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "total": total, "data": counts})
+}
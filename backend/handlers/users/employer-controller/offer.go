@@ -0,0 +1,195 @@
+package employer
+
+import (
+	"database/sql"
+	"fmt"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OfferCreate issues an offer to the applicant of an application
+func OfferCreate(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	employerID := c.MustGet("employer_id")
+
+	var input services.OfferInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	expiresAt, fieldErrors := input.Validate(time.Now())
+	if len(fieldErrors) > 0 {
+		respondValidationErrors(c, fieldErrors)
+		return
+	}
+
+	db, ok := openApplicationReview(c, jobEditorRoles...)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	change, err := services.CreateOffer(tx, applicationID, employerID, input, expiresAt)
+	if err == nil {
+		err = notifyOfferCandidate(tx, change, "offer.new", "You received an offer for \"%s\"")
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if !services.RespondOfferError(c, err) && !respondStatusChangeError(c, err) {
+			log.Printf("Error creating offer for application %s: %v", applicationID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create offer"})
+		}
+		return
+	}
+
+	log.Printf("Offer %d issued for application %s by employer %v", change.OfferID, applicationID, employerID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Offer issued successfully", "offer_id": change.OfferID})
+}
+
+// notifyOfferCandidate notifies the applicant about an offer; format takes the job title
+func notifyOfferCandidate(tx *sql.Tx, change services.OfferChange, kind, format string) error {
+	data := map[string]interface{}{"offer_id": change.OfferID, "application_id": change.ApplicationID, "status": change.Status}
+	return services.CreateNotification(tx, change.CandidateUserID, kind, fmt.Sprintf(format, change.JobTitle), data)
+}
+
+// ApplicationOfferViews lists the offers made for an application, newest first
+func ApplicationOfferViews(c *gin.Context) {
+	applicationID := c.Param("application-id")
+
+	db, ok := openApplicationReview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	offers, err := services.LoadOffers(db, "o.application_id = ?", applicationID)
+	if err != nil {
+		log.Printf("Error loading offers of application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": offers})
+}
+
+// OfferViews lists the offers for jobs the employer's team can access, optionally filtered by status
+func OfferViews(c *gin.Context) {
+	employerID := c.MustGet("employer_id")
+
+	condition, args := jobAccessCondition("j", employerID)
+	conditions := []string{condition}
+	if value := strings.TrimSpace(c.Query("status")); value != "" {
+		status, ok := services.ParseOfferStatus(value)
+		if !ok {
+			respondValidationErrors(c, services.FieldErrors{"status": "is not a valid offer status"})
+			return
+		}
+		statusCondition, statusArgs := services.OfferStatusCondition(status)
+		conditions = append(conditions, statusCondition)
+		args = append(args, statusArgs...)
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	offers, err := services.LoadOffers(db, strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		log.Printf("Error loading offers of employer %v: %v", employerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": offers})
+}
+
+// OfferRescind withdraws a pending offer with an optional reason
+func OfferRescind(c *gin.Context) {
+	offerID := c.Param("offer-id")
+	employerID := c.MustGet("employer_id")
+
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	var jobID, applicationID string
+	query := "SELECT a.job_id, a.application_id FROM offers o " +
+		"INNER JOIN applications a ON a.application_id = o.application_id WHERE o.offer_id = ?"
+	if err := db.QueryRow(query, offerID).Scan(&jobID, &applicationID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Offer not found"})
+			return
+		}
+		log.Printf("Error loading offer %s: %v", offerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if !requireApplicationAccess(c, db, employerID, jobID, applicationID, jobEditorRoles...) {
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	change, err := services.RescindOffer(tx, offerID, strings.TrimSpace(request.Reason))
+	if err == nil {
+		err = notifyOfferCandidate(tx, change, "offer.rescinded", "Your offer for \"%s\" was withdrawn")
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if !services.RespondOfferError(c, err) {
+			log.Printf("Error rescinding offer %s: %v", offerID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update offer"})
+		}
+		return
+	}
+
+	log.Printf("Offer %s rescinded by employer %v", offerID, employerID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Offer rescinded successfully", "offer_status": change.Status})
+}
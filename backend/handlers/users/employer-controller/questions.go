@@ -0,0 +1,111 @@
+package employer
+
+import (
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScreeningQuestionViews lists the screening questions of one of the employer's jobs
+func ScreeningQuestionViews(c *gin.Context) {
+	jobID := c.Param("job-id")
+	employerID := c.MustGet("employer_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) || !requireJobAccess(c, db, employerID, jobID) {
+		return
+	}
+
+	questions, err := services.LoadScreeningQuestions(db, jobID)
+	if err != nil {
+		log.Printf("Error loading questions of job %s: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": questions})
+}
+
+// ScreeningQuestionsUpdate replaces the screening questions of a job. Answers already given keep the
+// question text they were answered against.
+func ScreeningQuestionsUpdate(c *gin.Context) {
+	jobID := c.Param("job-id")
+	employerID := c.MustGet("employer_id")
+
+	var request struct {
+		Questions []struct {
+			services.ScreeningQuestion
+			Required *bool `json:"required"`
+		} `json:"questions"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	// Questions are required unless marked otherwise
+	input := make([]services.ScreeningQuestion, len(request.Questions))
+	for i, question := range request.Questions {
+		input[i] = question.ScreeningQuestion
+		input[i].Required = question.Required == nil || *question.Required
+	}
+	questions, fieldErrors := services.NormalizeQuestions(input)
+	if len(fieldErrors) > 0 {
+		respondValidationErrors(c, fieldErrors)
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) || !requireJobAccess(c, db, employerID, jobID, jobEditorRoles...) {
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	entry := services.NewAuditEntry(c, employerID, "employer", "job.questions_update", "job", jobID)
+	before, err := services.LoadScreeningQuestions(tx, jobID)
+	if err == nil {
+		entry.Before = map[string]interface{}{"questions": before}
+		err = services.ReplaceScreeningQuestions(tx, jobID, questions)
+	}
+	if err == nil {
+		var after []services.ScreeningQuestion
+		if after, err = services.LoadScreeningQuestions(tx, jobID); err == nil {
+			entry.After = map[string]interface{}{"questions": after}
+			err = services.RecordAudit(tx, entry)
+		}
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		log.Printf("Error replacing questions of job %s: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update screening questions"})
+		return
+	}
+
+	log.Printf("Job %s now has %d screening questions", jobID, len(questions))
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Screening questions updated successfully"})
+}
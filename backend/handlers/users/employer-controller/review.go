@@ -0,0 +1,263 @@
+package employer
+
+import (
+	"database/sql"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Notes, tags and ratings are private to the employer's team; no freshGrad route reads these tables.
+
+// openApplicationReview connects to the database and checks the employer may review the application in the
+// request path with one of roles, writing the error response if not. The caller closes the returned database.
+func openApplicationReview(c *gin.Context, roles ...string) (*sql.DB, bool) {
+	employerID := c.MustGet("employer_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return nil, false
+	}
+
+	if !checkEmployerStatus(c, db, employerID) ||
+		!requireApplicationAccess(c, db, employerID, c.Param("job-id"), c.Param("application-id"), roles...) {
+		db.Close()
+		return nil, false
+	}
+	return db, true
+}
+
+// ApplicationNoteCreate adds a private team note to an application
+func ApplicationNoteCreate(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	employerID := c.MustGet("employer_id")
+
+	var request struct {
+		Body string `json:"body" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	body := strings.TrimSpace(request.Body)
+	if body == "" {
+		respondValidationErrors(c, services.FieldErrors{"body": "must not be empty"})
+		return
+	}
+
+	db, ok := openApplicationReview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	result, err := db.Exec("INSERT INTO application_notes (application_id, author_id, body) VALUES (?, ?, ?)", applicationID, employerID, body)
+	if err != nil {
+		log.Printf("Error adding note to application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to add note"})
+		return
+	}
+	noteID, _ := result.LastInsertId()
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Note added successfully", "note_id": noteID})
+}
+
+// ApplicationNoteUpdate rewrites a note; only its author may change it
+func ApplicationNoteUpdate(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	noteID := c.Param("note-id")
+	employerID := c.MustGet("employer_id")
+
+	var request struct {
+		Body string `json:"body" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	body := strings.TrimSpace(request.Body)
+	if body == "" {
+		respondValidationErrors(c, services.FieldErrors{"body": "must not be empty"})
+		return
+	}
+
+	db, ok := openApplicationReview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	if !requireNoteAuthor(c, db, applicationID, noteID, employerID) {
+		return
+	}
+
+	if _, err := db.Exec("UPDATE application_notes SET body = ? WHERE note_id = ?", body, noteID); err != nil {
+		log.Printf("Error updating note %s: %v", noteID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update note"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Note updated successfully"})
+}
+
+// ApplicationNoteDelete removes a note; only its author may remove it
+func ApplicationNoteDelete(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	noteID := c.Param("note-id")
+	employerID := c.MustGet("employer_id")
+
+	db, ok := openApplicationReview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	if !requireNoteAuthor(c, db, applicationID, noteID, employerID) {
+		return
+	}
+
+	if _, err := db.Exec("DELETE FROM application_notes WHERE note_id = ?", noteID); err != nil {
+		log.Printf("Error deleting note %s: %v", noteID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to delete note"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Note deleted successfully"})
+}
+
+// requireNoteAuthor checks the note belongs to the application and was written by the employer
+func requireNoteAuthor(c *gin.Context, db *sql.DB, applicationID, noteID string, employerID interface{}) bool {
+	var authorID int
+	query := "SELECT author_id FROM application_notes WHERE note_id = ? AND application_id = ?"
+	if err := db.QueryRow(query, noteID, applicationID).Scan(&authorID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Note not found"})
+			return false
+		}
+		log.Printf("Error loading note %s: %v", noteID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return false
+	}
+	if id, _ := employerID.(int); id != authorID {
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": "Only the author can change a note"})
+		return false
+	}
+	return true
+}
+
+// ApplicationTagAdd puts a custom tag on an application
+func ApplicationTagAdd(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	employerID := c.MustGet("employer_id")
+
+	var request struct {
+		Tag string `json:"tag" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	tag := services.NormalizeTag(request.Tag)
+	if tag == "" || len(tag) > 50 {
+		respondValidationErrors(c, services.FieldErrors{"tag": "must be 1 to 50 characters"})
+		return
+	}
+
+	db, ok := openApplicationReview(c, jobEditorRoles...)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	if err := services.AddApplicationTag(db, applicationID, tag, employerID); err != nil {
+		log.Printf("Error tagging application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to add tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Tag added successfully"})
+}
+
+// ApplicationTagRemove takes a tag off an application
+func ApplicationTagRemove(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	tag := services.NormalizeTag(c.Param("tag"))
+
+	db, ok := openApplicationReview(c, jobEditorRoles...)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	result, err := db.Exec("DELETE FROM application_tags WHERE application_id = ? AND tag = ?", applicationID, tag)
+	if err != nil {
+		log.Printf("Error untagging application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to remove tag"})
+		return
+	}
+	if removed, _ := result.RowsAffected(); removed == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Tag not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Tag removed successfully"})
+}
+
+// ApplicationRatingSet sets the requesting reviewer's 1–5 rating of an application
+func ApplicationRatingSet(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	employerID := c.MustGet("employer_id")
+
+	var request struct {
+		Rating int `json:"rating" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if request.Rating < 1 || request.Rating > 5 {
+		respondValidationErrors(c, services.FieldErrors{"rating": "must be between 1 and 5"})
+		return
+	}
+
+	db, ok := openApplicationReview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	query := "INSERT INTO application_ratings (application_id, reviewer_id, rating) VALUES (?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE rating = VALUES(rating)"
+	if _, err := db.Exec(query, applicationID, employerID, request.Rating); err != nil {
+		log.Printf("Error rating application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to save rating"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Rating saved successfully"})
+}
+
+// ApplicationRatingDelete removes the requesting reviewer's rating of an application
+func ApplicationRatingDelete(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	employerID := c.MustGet("employer_id")
+
+	db, ok := openApplicationReview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("DELETE FROM application_ratings WHERE application_id = ? AND reviewer_id = ?", applicationID, employerID); err != nil {
+		log.Printf("Error removing rating of application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to remove rating"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Rating removed successfully"})
+}
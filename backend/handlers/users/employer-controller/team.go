@@ -0,0 +1,532 @@
+package employer
+
+import (
+	"database/sql"
+	"fmt"
+	services "fresh-grad-jobs/services"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TeamMemberViews lists the members of the employer's company
+func TeamMemberViews(c *gin.Context) {
+	employerID, exists := c.Get("employer_id")
+	if !exists {
+		log.Println("Employer ID not found in context")
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Employer ID not found in context"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	companyID, _, ok := requireCompanyRole(c, db, employerID)
+	if !ok {
+		return
+	}
+
+	type Member struct {
+		UserID   int    `json:"user_id"`
+		Email    string `json:"email"`
+		Role     string `json:"role"`
+		JoinedAt string `json:"joined_at"`
+	}
+
+	query := "SELECT m.user_id, u.email, m.role, m.created_at FROM company_members m " +
+		"INNER JOIN users u ON u.user_id = m.user_id WHERE m.company_id = ? ORDER BY m.created_at"
+	rows, err := db.Query(query, companyID)
+	if err != nil {
+		log.Printf("Query execution error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	defer rows.Close()
+
+	var members []Member
+	for rows.Next() {
+		var member Member
+		if err := rows.Scan(&member.UserID, &member.Email, &member.Role, &member.JoinedAt); err != nil {
+			log.Printf("Row scan error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
+			return
+		}
+		members = append(members, member)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": members})
+}
+
+// TeamMemberUpdate changes the team role of a company member
+func TeamMemberUpdate(c *gin.Context) {
+	memberID := c.Param("user-id")
+
+	var roleRequest struct {
+		Role string `json:"role" binding:"required,oneof=owner recruiter viewer"`
+	}
+	if err := c.ShouldBindJSON(&roleRequest); err != nil {
+		log.Printf("Error binding team role request: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	employerID, exists := c.Get("employer_id")
+	if !exists {
+		log.Println("Employer ID not found in context")
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Employer ID not found in context"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	companyID, _, ok := requireCompanyRole(c, db, employerID, teamRoleOwner)
+	if !ok {
+		return
+	}
+
+	currentRole, ok := memberRole(c, db, companyID, memberID)
+	if !ok {
+		return
+	}
+
+	// A company must always keep at least one owner
+	if currentRole == teamRoleOwner && roleRequest.Role != teamRoleOwner && !ensureAnotherOwner(c, db, companyID) {
+		return
+	}
+
+	updateQuery := "UPDATE company_members SET role = ? WHERE company_id = ? AND user_id = ?"
+	entry := services.NewAuditEntry(c, employerID, "employer", "company.member_update", "company_member", memberID)
+	if err := services.ExecWithAudit(db, entry, services.MemberSnapshotQuery, updateQuery, roleRequest.Role, companyID, memberID); err != nil {
+		log.Printf("Failed to update team role of user %s in company %d: %v", memberID, companyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update team role"})
+		return
+	}
+
+	log.Printf("Team role of user %s in company %d changed to %s", memberID, companyID, roleRequest.Role)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Team role updated successfully"})
+}
+
+// TeamMemberRemove removes a member from the company; owners can remove anyone and members can remove themselves
+func TeamMemberRemove(c *gin.Context) {
+	memberID := c.Param("user-id")
+
+	employerID, exists := c.Get("employer_id")
+	if !exists {
+		log.Println("Employer ID not found in context")
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Employer ID not found in context"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	companyID, role, ok := requireCompanyRole(c, db, employerID)
+	if !ok {
+		return
+	}
+
+	isSelf := memberID == fmt.Sprint(employerID)
+	if role != teamRoleOwner && !isSelf {
+		log.Printf("Employer %v with team role '%s' attempted to remove user %s", employerID, role, memberID)
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": "Insufficient team permissions"})
+		return
+	}
+
+	currentRole, ok := memberRole(c, db, companyID, memberID)
+	if !ok {
+		return
+	}
+
+	if currentRole == teamRoleOwner && !ensureAnotherOwner(c, db, companyID) {
+		return
+	}
+
+	deleteQuery := "DELETE FROM company_members WHERE company_id = ? AND user_id = ?"
+	entry := services.NewAuditEntry(c, employerID, "employer", "company.member_remove", "company_member", memberID)
+	if err := services.ExecWithAudit(db, entry, services.MemberSnapshotQuery, deleteQuery, companyID, memberID); err != nil {
+		log.Printf("Failed to remove user %s from company %d: %v", memberID, companyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to remove team member"})
+		return
+	}
+
+	log.Printf("User %s removed from company %d", memberID, companyID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Team member removed successfully"})
+}
+
+// InvitationCreate invites a user by email to join the employer's company with a team role
+func InvitationCreate(c *gin.Context) {
+	var inviteRequest struct {
+		Email string `json:"email" binding:"required,email"`
+		Role  string `json:"role" binding:"required,oneof=owner recruiter viewer"`
+	}
+	if err := c.ShouldBindJSON(&inviteRequest); err != nil {
+		log.Printf("Error binding invitation request: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	employerID, exists := c.Get("employer_id")
+	if !exists {
+		log.Println("Employer ID not found in context")
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Employer ID not found in context"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	companyID, _, ok := requireCompanyRole(c, db, employerID, teamRoleOwner)
+	if !ok {
+		return
+	}
+
+	// Reject invitations for people already in a company or with a pending invitation
+	var alreadyMember, alreadyInvited bool
+	memberQuery := "SELECT EXISTS(SELECT 1 FROM company_members m INNER JOIN users u ON u.user_id = m.user_id WHERE u.email = ?)"
+	inviteQuery := "SELECT EXISTS(SELECT 1 FROM company_invitations WHERE company_id = ? AND email = ? " +
+		"AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > NOW())"
+	if err := db.QueryRow(memberQuery, inviteRequest.Email).Scan(&alreadyMember); err == nil {
+		err = db.QueryRow(inviteQuery, companyID, inviteRequest.Email).Scan(&alreadyInvited)
+	}
+	if err != nil {
+		log.Printf("Error checking existing membership for %s: %v", inviteRequest.Email, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if alreadyMember {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": "User already belongs to a company"})
+		return
+	}
+	if alreadyInvited {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": "A pending invitation already exists for this email"})
+		return
+	}
+
+	token, err := services.GenerateRandomToken(32)
+	if err != nil {
+		log.Printf("Error generating invitation token: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create invitation"})
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	insertQuery := "INSERT INTO company_invitations (company_id, email, role, token, invited_by, expires_at) " +
+		"VALUES (?, ?, ?, ?, ?, DATE_ADD(NOW(), INTERVAL 7 DAY))"
+	result, err := tx.Exec(insertQuery, companyID, inviteRequest.Email, inviteRequest.Role, token, employerID)
+	if err != nil {
+		log.Printf("Failed to create invitation for %s: %v", inviteRequest.Email, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create invitation"})
+		return
+	}
+
+	invitationID, err := result.LastInsertId()
+	if err == nil {
+		entry := services.NewAuditEntry(c, employerID, "employer", "company.invite", "company_invitation", invitationID)
+		if entry.After, err = services.SnapshotRow(tx, services.InviteSnapshotQuery, invitationID); err == nil {
+			err = services.RecordAudit(tx, entry)
+		}
+	}
+	if err != nil {
+		log.Printf("Error recording audit log for invitation: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to create invitation"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error committing transaction"})
+		return
+	}
+
+	log.Printf("Invitation %d created for %s in company %d", invitationID, inviteRequest.Email, companyID)
+	c.JSON(http.StatusOK, gin.H{
+		"status":        "success",
+		"message":       "Invitation created successfully",
+		"invitation_id": invitationID,
+		"token":         token,
+	})
+}
+
+// InvitationViews lists the pending invitations of the employer's company
+func InvitationViews(c *gin.Context) {
+	employerID, exists := c.Get("employer_id")
+	if !exists {
+		log.Println("Employer ID not found in context")
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Employer ID not found in context"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	companyID, _, ok := requireCompanyRole(c, db, employerID, teamRoleOwner)
+	if !ok {
+		return
+	}
+
+	type Invitation struct {
+		ID        int    `json:"invitation_id"`
+		Email     string `json:"email"`
+		Role      string `json:"role"`
+		InvitedBy int    `json:"invited_by"`
+		ExpiresAt string `json:"expires_at"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	query := "SELECT invitation_id, email, role, invited_by, expires_at, created_at FROM company_invitations " +
+		"WHERE company_id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > NOW() ORDER BY created_at DESC"
+	rows, err := db.Query(query, companyID)
+	if err != nil {
+		log.Printf("Query execution error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	defer rows.Close()
+
+	var invitations []Invitation
+	for rows.Next() {
+		var invitation Invitation
+		if err := rows.Scan(&invitation.ID, &invitation.Email, &invitation.Role, &invitation.InvitedBy,
+			&invitation.ExpiresAt, &invitation.CreatedAt); err != nil {
+			log.Printf("Row scan error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
+			return
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": invitations})
+}
+
+// InvitationRevoke cancels a pending invitation of the employer's company
+func InvitationRevoke(c *gin.Context) {
+	invitationID := c.Param("invitation-id")
+
+	employerID, exists := c.Get("employer_id")
+	if !exists {
+		log.Println("Employer ID not found in context")
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Employer ID not found in context"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	companyID, _, ok := requireCompanyRole(c, db, employerID, teamRoleOwner)
+	if !ok {
+		return
+	}
+
+	var pending bool
+	checkQuery := "SELECT EXISTS(SELECT 1 FROM company_invitations WHERE invitation_id = ? AND company_id = ? " +
+		"AND accepted_at IS NULL AND revoked_at IS NULL)"
+	if err := db.QueryRow(checkQuery, invitationID, companyID).Scan(&pending); err != nil {
+		log.Printf("Error checking invitation %s: %v", invitationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if !pending {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Invitation not found"})
+		return
+	}
+
+	updateQuery := "UPDATE company_invitations SET revoked_at = NOW() WHERE invitation_id = ?"
+	entry := services.NewAuditEntry(c, employerID, "employer", "company.invite_revoke", "company_invitation", invitationID)
+	if err := services.ExecWithAudit(db, entry, services.InviteSnapshotQuery, updateQuery, invitationID); err != nil {
+		log.Printf("Failed to revoke invitation %s: %v", invitationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to revoke invitation"})
+		return
+	}
+
+	log.Printf("Invitation %s revoked", invitationID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Invitation revoked successfully"})
+}
+
+// InvitationAccept adds the signed-in employer to the inviting company
+func InvitationAccept(c *gin.Context) {
+	token := c.Param("token")
+
+	employerID, exists := c.Get("employer_id")
+	if !exists {
+		log.Println("Employer ID not found in context")
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Employer ID not found in context"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkEmployerStatus(c, db, employerID) {
+		return
+	}
+
+	// The invitation must be pending and addressed to the signed-in employer's email
+	var invitationID, companyID int
+	var role string
+	inviteQuery := "SELECT i.invitation_id, i.company_id, i.role FROM company_invitations i " +
+		"INNER JOIN users u ON u.email = i.email WHERE i.token = ? AND u.user_id = ? " +
+		"AND i.accepted_at IS NULL AND i.revoked_at IS NULL AND i.expires_at > NOW()"
+	if err := db.QueryRow(inviteQuery, token, employerID).Scan(&invitationID, &companyID, &role); err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("No pending invitation for employer %v", employerID)
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Invitation not found or expired"})
+			return
+		}
+		log.Printf("Error retrieving invitation: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+
+	if _, _, err := companyMembership(db, employerID); err != sql.ErrNoRows {
+		if err == nil {
+			c.JSON(http.StatusConflict, gin.H{"status": "error", "message": "You already belong to a company"})
+			return
+		}
+		log.Printf("Error checking company membership for employer %v: %v", employerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	steps := []struct {
+		query string
+		args  []interface{}
+	}{
+		{"INSERT INTO company_members (company_id, user_id, role) VALUES (?, ?, ?)", []interface{}{companyID, employerID, role}},
+		{"UPDATE company_invitations SET accepted_at = NOW() WHERE invitation_id = ?", []interface{}{invitationID}},
+		// Jobs the employer posted before joining move to the company
+		{"UPDATE jobs SET company_id = ? WHERE employer_id = ? AND company_id IS NULL", []interface{}{companyID, employerID}},
+	}
+	for _, step := range steps {
+		if _, err := tx.Exec(step.query, step.args...); err != nil {
+			log.Printf("Error accepting invitation %d: %v", invitationID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to accept invitation"})
+			return
+		}
+	}
+
+	entry := services.NewAuditEntry(c, employerID, "employer", "company.member_add", "company_member", employerID)
+	if entry.After, err = services.SnapshotRow(tx, services.MemberSnapshotQuery, employerID); err == nil {
+		err = services.RecordAudit(tx, entry)
+	}
+	if err != nil {
+		log.Printf("Error recording audit log for invitation %d: %v", invitationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to accept invitation"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error committing transaction"})
+		return
+	}
+
+	log.Printf("Employer %v joined company %d as %s", employerID, companyID, role)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Invitation accepted successfully", "company_id": companyID, "role": role})
+}
+
+// memberRole returns the team role of a member of the company, writing the error response if not found
+func memberRole(c *gin.Context, db *sql.DB, companyID int, memberID string) (string, bool) {
+	var role string
+	err := db.QueryRow("SELECT role FROM company_members WHERE company_id = ? AND user_id = ?", companyID, memberID).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Team member not found"})
+			return "", false
+		}
+		log.Printf("Error retrieving team role of user %s: %v", memberID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return "", false
+	}
+	return role, true
+}
+
+// ensureAnotherOwner checks the company has more than one owner, writing the error response if not
+func ensureAnotherOwner(c *gin.Context, db *sql.DB, companyID int) bool {
+	var owners int
+	if err := db.QueryRow("SELECT COUNT(*) FROM company_members WHERE company_id = ? AND role = ?", companyID, teamRoleOwner).Scan(&owners); err != nil {
+		log.Printf("Error counting owners of company %d: %v", companyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return false
+	}
+	if owners <= 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "A company must keep at least one owner"})
+		return false
+	}
+	return true
+}
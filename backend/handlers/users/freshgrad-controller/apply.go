@@ -0,0 +1,299 @@
+package freshGrad
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// knockoutNote is recorded in the status history of applications rejected by a screening question
+const knockoutNote = "Automatically rejected by a screening question"
+
+// JobQuestionViews lists the screening questions to answer when applying to a visible job
+func JobQuestionViews(c *gin.Context) {
+	jobID := c.Param("job-id")
+	freshGradID := c.MustGet("freshGrad_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkFreshGradStatus(c, db, freshGradID) || !requireVisibleJob(c, db, jobID, freshGradID) {
+		return
+	}
+
+	questions, err := services.LoadScreeningQuestions(db, jobID)
+	if err != nil {
+		log.Printf("Error loading questions of job %s: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	// Knockout rules stay with the employer
+	for i := range questions {
+		questions[i].KnockoutOptions, questions[i].MinValue, questions[i].MaxValue = nil, nil, nil
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": questions})
+}
+
+// JobApply submits an application to a visible job with a cover letter, one of the fresh grad's resumes
+// (the default one unless resume_id is given) and answers to its screening questions. The resume version and
+// the profile are kept as submitted. Answers that hit a knockout rule reject the application straight away.
+func JobApply(c *gin.Context) {
+	jobID := c.Param("job-id")
+	freshGradID := c.MustGet("freshGrad_id")
+
+	var request struct {
+		CoverLetter string                          `json:"cover_letter"`
+		ResumeID    *int                            `json:"resume_id"`
+		Answers     []services.ScreeningAnswerInput `json:"answers"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkFreshGradStatus(c, db, freshGradID) || !requireVisibleJob(c, db, jobID, freshGradID) {
+		return
+	}
+	profileID, ok := freshGradProfileID(c, db, freshGradID)
+	if !ok {
+		return
+	}
+
+	questions, err := services.LoadScreeningQuestions(db, jobID)
+	if err != nil {
+		log.Printf("Error loading questions of job %s: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	answers, fieldErrors := services.EvaluateAnswers(questions, request.Answers)
+	if len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": fieldErrors})
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	// Locking the profile serializes concurrent applies by the same fresh grad
+	lockQuery := "SELECT freshgradprofile_id FROM freshgradprofiles WHERE freshgradprofile_id = ? FOR UPDATE"
+	if err := tx.QueryRow(lockQuery, profileID).Scan(&profileID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Profile not found"})
+			return
+		}
+		log.Printf("Error locking profile %d: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to submit application"})
+		return
+	}
+
+	var alreadyApplied bool
+	checkQuery := "SELECT EXISTS (SELECT 1 FROM applications WHERE job_id = ? AND freshgradprofile_id = ? AND status <> ?)"
+	if err := tx.QueryRow(checkQuery, jobID, profileID, string(services.ApplicationStatusWithdrawn)).Scan(&alreadyApplied); err != nil {
+		log.Printf("Error checking existing application: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to submit application"})
+		return
+	}
+	if alreadyApplied {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": "You have already applied to this job"})
+		return
+	}
+	availableAt, err := services.ReapplyAvailableAt(tx, services.ReapplyPolicy, profileID, jobID)
+	if err != nil {
+		log.Printf("Error checking reapply cooldown: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to submit application"})
+		return
+	}
+	if availableAt != "" {
+		c.JSON(http.StatusConflict, gin.H{
+			"status":        "error",
+			"message":       "You withdrew an application recently; you can apply again after the cooldown",
+			"reapply_after": availableAt,
+			"reapply_scope": services.ReapplyPolicy.Scope,
+		})
+		return
+	}
+
+	resumeVersionID, err := services.ApplicationResumeVersion(tx, profileID, request.ResumeID)
+	if errors.Is(err, services.ErrResumeNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": gin.H{"resume_id": "is not one of your resumes"}})
+		return
+	}
+	var profileSnapshot []byte
+	if err == nil {
+		profileSnapshot, err = services.SnapshotProfile(tx, profileID)
+	}
+	if err != nil {
+		log.Printf("Error snapshotting profile %d: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to submit application"})
+		return
+	}
+
+	var coverLetter interface{}
+	if text := strings.TrimSpace(request.CoverLetter); text != "" {
+		coverLetter = text
+	}
+	insertQuery := "INSERT INTO applications (job_id, freshgradprofile_id, status, cover_letter, resume_version_id, profile_snapshot) " +
+		"VALUES (?, ?, ?, ?, ?, ?)"
+	result, err := tx.Exec(insertQuery, jobID, profileID, string(services.ApplicationStatusSubmitted), coverLetter,
+		resumeVersionID, string(profileSnapshot))
+	if err != nil {
+		log.Printf("Error creating application: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to submit application"})
+		return
+	}
+	applicationID, err := result.LastInsertId()
+	if err == nil {
+		err = services.RecordStatusHistory(tx, applicationID, "", services.ApplicationStatusSubmitted, nil, "")
+	}
+
+	knockedOut := false
+	for _, answer := range answers {
+		if err != nil {
+			break
+		}
+		knockedOut = knockedOut || answer.Knockout
+		_, err = tx.Exec("INSERT INTO application_answers (application_id, question_id, question, answer, knockout) VALUES (?, ?, ?, ?, ?)",
+			applicationID, answer.Question.ID, answer.Question.Question, answer.Answer, answer.Knockout)
+	}
+
+	// Let the employer who posted the job know
+	var employerID int
+	var jobTitle string
+	if err == nil {
+		err = tx.QueryRow("SELECT employer_id, title FROM jobs WHERE job_id = ?", jobID).Scan(&employerID, &jobTitle)
+	}
+	if err == nil && !knockedOut {
+		message := fmt.Sprintf("New application for \"%s\"", jobTitle)
+		data := map[string]interface{}{"application_id": applicationID, "job_id": jobID}
+		err = services.CreateNotification(tx, employerID, "application.new", message, data)
+	}
+	if err == nil && !knockedOut {
+		data := map[string]interface{}{"ApplicationID": applicationID, "JobID": jobID, "JobTitle": jobTitle}
+		err = services.EnqueueEmail(tx, employerID, services.EmailTemplateApplicationNew, data)
+	}
+
+	status := services.ApplicationStatusSubmitted
+	if err == nil && knockedOut {
+		status = services.ApplicationStatusRejected
+		_, err = services.ChangeApplicationStatus(tx, applicationID, status, nil, knockoutNote)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		log.Printf("Error submitting application to job %s for profile %d: %v", jobID, profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to submit application"})
+		return
+	}
+
+	log.Printf("Profile %d applied to job %s (application %d, status %s)", profileID, jobID, applicationID, status)
+	c.JSON(http.StatusOK, gin.H{
+		"status":             "success",
+		"message":            "Application submitted successfully",
+		"application_id":     applicationID,
+		"application_status": status,
+	})
+}
+
+// ApplicationWithdraw withdraws one of the fresh grad's applications with an optional reason. The employer is
+// notified, and applying again is subject to the reapply cooldown.
+func ApplicationWithdraw(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	freshGradID := c.MustGet("freshGrad_id")
+
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	// The body is optional on DELETE
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+			return
+		}
+	}
+	if len(strings.TrimSpace(request.Reason)) > services.MaxWithdrawReasonLength {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed",
+			"errors": gin.H{"reason": fmt.Sprintf("must be at most %d characters", services.MaxWithdrawReasonLength)}})
+		return
+	}
+
+	db, _, ok := openOwnApplication(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	entry := services.NewAuditEntry(c, freshGradID, "freshGrad", "application.withdraw", "application", applicationID)
+	entry.Before, err = services.SnapshotRow(tx, services.ApplicationSnapshotQuery, applicationID)
+	var withdrawal services.ApplicationWithdrawal
+	if err == nil {
+		withdrawal, err = services.WithdrawApplication(tx, applicationID, freshGradID, request.Reason)
+	}
+	if err == nil {
+		message := fmt.Sprintf("An applicant withdrew from \"%s\"", withdrawal.JobTitle)
+		data := map[string]interface{}{"application_id": applicationID, "job_id": withdrawal.JobID}
+		err = services.CreateNotification(tx, withdrawal.EmployerID, "application.withdrawn", message, data)
+	}
+	if err == nil {
+		if entry.After, err = services.SnapshotRow(tx, services.ApplicationSnapshotQuery, applicationID); err == nil {
+			err = services.RecordAudit(tx, entry)
+		}
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrApplicationStatusSame):
+			c.JSON(http.StatusConflict, gin.H{"status": "error", "message": "Application is already withdrawn"})
+		case errors.Is(err, services.ErrApplicationStatusLocked):
+			c.JSON(http.StatusConflict, gin.H{"status": "error", "message": "Application can no longer be withdrawn"})
+		default:
+			log.Printf("Error withdrawing application %s: %v", applicationID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to withdraw application"})
+		}
+		return
+	}
+
+	log.Printf("Application %s withdrawn by freshGrad %v (was %s)", applicationID, freshGradID, withdrawal.From)
+	c.JSON(http.StatusOK, gin.H{
+		"status":                "success",
+		"message":               "Application withdrawn successfully",
+		"reapply_cooldown_days": services.ReapplyPolicy.CooldownDays,
+		"reapply_scope":         services.ReapplyPolicy.Scope,
+	})
+}
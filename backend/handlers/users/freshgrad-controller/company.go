@@ -0,0 +1,101 @@
+package freshGrad
+
+import (
+	"database/sql"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CompanyView returns a company's public page with its approved job postings
+func CompanyView(c *gin.Context) {
+	companyID := c.Param("company-id")
+	log.Printf("Received request to view company. Company ID: %s", companyID)
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	freshGradID, exists := c.Get("freshGrad_id")
+	if !exists {
+		log.Printf("freshGrad ID not found in context")
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "freshGrad ID not found"})
+		return
+	}
+
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		return
+	}
+
+	type CompanyJob struct {
+		ID                  string `json:"job_id"`
+		Title               string `json:"title"`
+		JobType             string `json:"job_type"`
+		Location            string `json:"location"`
+		ApplicationDeadline string `json:"application_deadline"`
+		JobStatus           string `json:"job_status"`
+	}
+
+	type Company struct {
+		ID          int          `json:"company_id"`
+		Name        string       `json:"name"`
+		LogoURL     string       `json:"logo_url"`
+		Industry    string       `json:"industry"`
+		CompanySize string       `json:"company_size"`
+		Website     string       `json:"website"`
+		Description string       `json:"description"`
+		Address     string       `json:"address"`
+		Verified    bool         `json:"verified"`
+		Jobs        []CompanyJob `json:"jobs"`
+	}
+
+	// Companies whose owner has been suspended are hidden from fresh grads
+	query := "SELECT co.company_id, co.name, co.logo_url, co.industry, co.company_size, co.website, co.description, " +
+		"co.address, co.verified FROM companies co INNER JOIN users u ON u.user_id = co.owner_id " +
+		"WHERE co.company_id = ? AND u.suspended = FALSE"
+
+	var company Company
+	if err := db.QueryRow(query, companyID).Scan(
+		&company.ID, &company.Name, &company.LogoURL, &company.Industry, &company.CompanySize,
+		&company.Website, &company.Description, &company.Address, &company.Verified,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("Company not found: %s", companyID)
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Company not found"})
+			return
+		}
+		log.Printf("Row scan error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
+		return
+	}
+
+	// Only jobs passing the visibility policy are listed on the company page
+	jobsQuery := "SELECT j.job_id, j.title, j.job_type, j.location, j.application_deadline, j.job_status FROM jobs j " +
+		"WHERE j.company_id = ? AND " + services.VisibleJobCondition("j", time.Now()) + " ORDER BY j.created_at DESC"
+	rows, err := db.Query(jobsQuery, company.ID)
+	if err != nil {
+		log.Printf("Query execution error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var job CompanyJob
+		if err := rows.Scan(&job.ID, &job.Title, &job.JobType, &job.Location, &job.ApplicationDeadline, &job.JobStatus); err != nil {
+			log.Printf("Row scan error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
+			return
+		}
+		company.Jobs = append(company.Jobs, job)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": company})
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,7 +13,7 @@ import (
 // TODO: View job ❌
 // ดูประกาศงานที่มีอยู่ในระบบ
 
-// TODO: Apply for job ❌
+// TODO: Apply for job ✅
 // สมัครงานที่สนใจจากประกาศงานที่ดู
 
 // TODO: View applied jobs ❌
@@ -32,6 +33,21 @@ import (
 // TODO: Job alerts ❌
 // รับการแจ้งเตือนเมื่อมีงานใหม่ที่ตรงกับทักษะหรือความสนใจของตน
 
+// TODO: Job recommendations ✅
+// แนะนำงานที่เหมาะสมตามทักษะ ประสบการณ์ สถานที่ เงินเดือนที่คาดหวัง และระดับงาน พร้อมคำอธิบายคะแนน
+
+// TODO: Withdraw application ✅
+// ถอนใบสมัครพร้อมเหตุผล และรอระยะเวลาที่กำหนดก่อนสมัครงานเดิมหรือบริษัทเดิมอีกครั้ง
+
+// TODO: Multiple resumes ✅
+// เก็บเรซูเม่หลายฉบับพร้อมชื่อ เลือกฉบับที่ใช้ในแต่ละใบสมัคร และนายจ้างเห็นเรซูเม่กับโปรไฟล์ตามที่ส่งไปตอนสมัคร
+
+// TODO: Resume parsing ✅
+// อ่านเรซูเม่ PDF/DOCX เพื่อร่างโปรไฟล์ (การศึกษา ทักษะ ช่องทางติดต่อ ประสบการณ์) ให้ตรวจสอบก่อนบันทึก
+
+// TODO: Privacy controls ✅
+// กำหนดว่าใครค้นหาโปรไฟล์ได้ (ส่วนตัว / เฉพาะบริษัทที่สมัคร / นายจ้างทุกราย) บล็อกบริษัท และอนุมัติคำขอดูช่องทางติดต่อ
+
 // AuthMiddleware checks for freshGrad role in the JWT and retrieves frashgrad_id
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -140,66 +156,86 @@ func JobViews(c *gin.Context) {
 		JobStatus           string  `json:"job_status"`
 		SkillsRequired      string  `json:"skills_required"`
 		JobLevel            string  `json:"job_level"`
+		CompanyID           *int    `json:"company_id"`
+		CompanyName         *string `json:"company_name"`
+		CompanyVerified     *bool   `json:"company_verified"`
+		Positions           int     `json:"positions"`
+		PositionsRemaining  int     `json:"positions_remaining"`
+
+		Skills []services.Skill `json:"skills"`
 	}
 
+	// Job columns joined with the company's public badge
+	jobColumns := "j.job_id, j.title, j.employer_id, j.job_category, j.job_type, j.min_salary, j.max_salary, j.min_experience, " +
+		"j.max_experience, j.job_responsibility, j.qualification, j.benefits, j.job_description, j.approved, j.created_at, " +
+		"j.location, j.posted_by, j.application_deadline, j.job_status, j.skills_required, j.job_level, " +
+		"j.company_id, co.name, co.verified, j.positions, GREATEST(j.positions - j.positions_filled, 0) FROM jobs j LEFT JOIN companies co ON co.company_id = j.company_id"
+
 	// Prepare base query
 	var query string
 	var args []interface{}
 
 	if jobID == "" {
-		query = "SELECT job_id, title, employer_id, job_category, job_type, min_salary, max_salary, min_experience, " +
-			"max_experience, job_responsibility, qualification, benefits, job_description, approved, created_at, " +
-			"location, posted_by, application_deadline, job_status, skills_required, job_level FROM jobs WHERE 1=1"
+		// Only jobs passing the visibility policy are listed
+		query = "SELECT " + jobColumns + " WHERE " + services.VisibleJobCondition("j", time.Now())
 
 		// Append filters conditionally
 		if jobType := c.Query("job_type"); jobType != "" {
-			query += " AND job_type = ?"
+			query += " AND j.job_type = ?"
 			args = append(args, jobType)
 		}
 		if jobCategory := c.Query("job_category"); jobCategory != "" {
-			query += " AND job_category = ?"
+			query += " AND j.job_category = ?"
 			args = append(args, jobCategory)
 		}
 		if minSalary := c.Query("min_salary"); minSalary != "" {
-			query += " AND min_salary >= ?"
+			query += " AND j.min_salary >= ?"
 			args = append(args, minSalary)
 		}
 		if maxSalary := c.Query("max_salary"); maxSalary != "" {
-			query += " AND max_salary <= ?"
+			query += " AND j.max_salary <= ?"
 			args = append(args, maxSalary)
 		}
 		if minExperience := c.Query("min_experience"); minExperience != "" {
-			query += " AND min_experience >= ?"
+			query += " AND j.min_experience >= ?"
 			args = append(args, minExperience)
 		}
 		if maxExperience := c.Query("max_experience"); maxExperience != "" {
-			query += " AND max_experience <= ?"
+			query += " AND j.max_experience <= ?"
 			args = append(args, maxExperience)
 		}
 		if location := c.Query("location"); location != "" {
-			query += " AND location = ?"
+			query += " AND j.location = ?"
 			args = append(args, location)
 		}
-		if approvedFilter := c.Query("approved"); approvedFilter != "" {
-			query += " AND approved = ?"
-			if approvedFilter == "true" {
-				args = append(args, true)
-			} else {
-				args = append(args, false)
-			}
-		}
 		if createdAfter := c.Query("created_after"); createdAfter != "" {
-			query += " AND created_at >= ?"
+			query += " AND j.created_at >= ?"
 			args = append(args, createdAfter)
 		}
 		if createdBefore := c.Query("created_before"); createdBefore != "" {
-			query += " AND created_at <= ?"
+			query += " AND j.created_at <= ?"
 			args = append(args, createdBefore)
 		}
+
+		// Skills filter, e.g. skills=JS,Go&skills_match=all; aliases and Thai names resolve to catalogue skills
+		skillCondition, skillArgs, err := services.SkillFilter(db, c.Query("skills"), c.Query("skills_match"),
+			"job_skills", "job_id", "j.job_id")
+		if err != nil {
+			log.Printf("Error resolving skills filter: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+			return
+		}
+		if skillCondition != "" {
+			query += " AND " + skillCondition
+			args = append(args, skillArgs...)
+		}
 	} else {
-		query = "SELECT job_id, title, employer_id, job_category, job_type, min_salary, max_salary, min_experience, " +
-			"max_experience, job_responsibility, qualification, benefits, job_description, approved, created_at, " +
-			"location, posted_by, application_deadline, job_status, skills_required, job_level FROM jobs WHERE job_id = ?"
+		// Check the visibility policy first so hidden jobs read as not found
+		if !requireVisibleJob(c, db, jobID, freshGradID) {
+			return
+		}
+
+		query = "SELECT " + jobColumns + " WHERE j.job_id = ?"
 		args = append(args, jobID)
 	}
 
@@ -220,7 +256,8 @@ func JobViews(c *gin.Context) {
 			&job.ID, &job.Title, &job.EmployerID, &job.JobCategory, &job.JobType, &job.MinSalary, &job.MaxSalary,
 			&job.MinExperience, &job.MaxExperience, &job.JobResponsibility, &job.Qualification, &job.Benefits,
 			&job.JobDescription, &job.Approved, &job.CreatedAt, &job.Location, &job.PostedBy, &job.ApplicationDeadline,
-			&job.JobStatus, &job.SkillsRequired, &job.JobLevel,
+			&job.JobStatus, &job.SkillsRequired, &job.JobLevel, &job.CompanyID, &job.CompanyName, &job.CompanyVerified,
+			&job.Positions, &job.PositionsRemaining,
 		); err != nil {
 			log.Printf("Row scan error: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
@@ -229,6 +266,21 @@ func JobViews(c *gin.Context) {
 		jobs = append(jobs, job)
 	}
 
+	// Attach the catalogue skills of each job
+	jobIDs := make([]string, len(jobs))
+	for i, job := range jobs {
+		jobIDs[i] = job.ID
+	}
+	jobSkills, err := services.JobSkills(db, jobIDs)
+	if err != nil {
+		log.Printf("Error loading job skills: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	for i := range jobs {
+		jobs[i].Skills = jobSkills[jobs[i].ID]
+	}
+
 	// Final response
 	c.JSON(http.StatusOK, gin.H{"status": "success", "data": jobs})
 }
@@ -0,0 +1,69 @@
+package freshGrad
+
+import (
+	"database/sql"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkFreshGradStatus verifies the fresh grad is approved and not suspended, writing the error response if not
+func checkFreshGradStatus(c *gin.Context, db *sql.DB, freshGradID interface{}) bool {
+	var isApproved, isSuspended bool
+	if err := db.QueryRow("SELECT approved, suspended FROM users WHERE user_id=?", freshGradID).Scan(&isApproved, &isSuspended); err != nil {
+		log.Printf("Error checking approval status for freshGrad %v: %v", freshGradID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error checking approval status"})
+		return false
+	}
+	if !isApproved {
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": "Account not approved"})
+		return false
+	}
+	if isSuspended {
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": "Account is suspended"})
+		return false
+	}
+	return true
+}
+
+// freshGradProfileID looks up the profile of a fresh grad, writing a 404 when they have not created one
+func freshGradProfileID(c *gin.Context, db *sql.DB, freshGradID interface{}) (int, bool) {
+	var profileID int
+	err := db.QueryRow("SELECT freshgradprofile_id FROM freshgradprofiles WHERE user_id = ?", freshGradID).Scan(&profileID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Profile not found"})
+		return 0, false
+	}
+	if err != nil {
+		log.Printf("Error loading profile of freshGrad %v: %v", freshGradID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return 0, false
+	}
+	return profileID, true
+}
+
+// requireVisibleJob checks the job passes the visibility policy, writing a 404 when it does not exist or is hidden
+func requireVisibleJob(c *gin.Context, db *sql.DB, jobID string, freshGradID interface{}) bool {
+	var facts services.JobVisibility
+	if err := db.QueryRow(services.VisibilityFactsQuery, jobID).Scan(
+		&facts.Approved, &facts.JobStatus, &facts.ApplicationDeadline, &facts.EmployerExists, &facts.EmployerSuspended,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("Job not found: %s", jobID)
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Job not found"})
+			return false
+		}
+		log.Printf("Error checking visibility of job %s: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return false
+	}
+	if err := services.CheckJobVisibility(facts, time.Now()); err != nil {
+		log.Printf("Job %s hidden from freshGrad %v: %v", jobID, freshGradID, err)
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Job not found"})
+		return false
+	}
+	return true
+}
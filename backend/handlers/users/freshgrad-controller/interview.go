@@ -0,0 +1,185 @@
+package freshGrad
+
+import (
+	"database/sql"
+	"fmt"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ownInterviewCondition limits LoadInterviews to the fresh grad's own interviews
+const ownInterviewCondition = "f.user_id = ?"
+
+// InterviewViews lists the fresh grad's interviews, soonest first
+func InterviewViews(c *gin.Context) {
+	freshGradID := c.MustGet("freshGrad_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		return
+	}
+
+	interviews, err := services.LoadInterviews(db, ownInterviewCondition, freshGradID)
+	if err != nil {
+		log.Printf("Error loading interviews of freshGrad %v: %v", freshGradID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": interviews})
+}
+
+// openOwnInterview connects to the database and loads the fresh grad's interview in the request path, writing
+// the error response if it is not theirs. The caller closes the returned database.
+func openOwnInterview(c *gin.Context) (*sql.DB, services.Interview, bool) {
+	interviewID := c.Param("interview-id")
+	freshGradID := c.MustGet("freshGrad_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return nil, services.Interview{}, false
+	}
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		db.Close()
+		return nil, services.Interview{}, false
+	}
+
+	interviews, err := services.LoadInterviews(db, "i.interview_id = ? AND "+ownInterviewCondition, interviewID, freshGradID)
+	if err != nil {
+		log.Printf("Error loading interview %s: %v", interviewID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		db.Close()
+		return nil, services.Interview{}, false
+	}
+	if len(interviews) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Interview not found"})
+		db.Close()
+		return nil, services.Interview{}, false
+	}
+	return db, interviews[0], true
+}
+
+// InterviewAccept accepts one of the employer's proposed slots
+func InterviewAccept(c *gin.Context) {
+	var request struct {
+		SlotID int `json:"slot_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	db, interview, ok := openOwnInterview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	changeInterview(c, db, interview.ID, "Interview accepted successfully", func(tx *sql.Tx) (services.InterviewChange, error) {
+		return services.AcceptInterviewSlot(tx, interview.ID, request.SlotID, services.InterviewPartyCandidate, nil, time.Now())
+	}, "interview.scheduled", "The candidate accepted an interview time for \"%s\"")
+}
+
+// InterviewPropose proposes alternative slots to the employer
+func InterviewPropose(c *gin.Context) {
+	var request struct {
+		Slots []string `json:"slots" binding:"required"`
+		Note  string   `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	slots, fieldErrors := services.ParseInterviewSlots(request.Slots, time.Now())
+	if len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": fieldErrors})
+		return
+	}
+
+	db, interview, ok := openOwnInterview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	changeInterview(c, db, interview.ID, "Alternative slots proposed successfully", func(tx *sql.Tx) (services.InterviewChange, error) {
+		return services.ProposeInterviewSlots(tx, interview.ID, services.InterviewPartyCandidate, slots, strings.TrimSpace(request.Note))
+	}, "interview.counter_proposed", "The candidate proposed other interview times for \"%s\"")
+}
+
+// InterviewDecline declines an interview with an optional reason
+func InterviewDecline(c *gin.Context) {
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	db, interview, ok := openOwnInterview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	changeInterview(c, db, interview.ID, "Interview declined successfully", func(tx *sql.Tx) (services.InterviewChange, error) {
+		return services.CloseInterview(tx, interview.ID, services.InterviewPartyCandidate, strings.TrimSpace(request.Reason))
+	}, "interview.declined", "The candidate declined the interview for \"%s\"")
+}
+
+// changeInterview runs an interview change in a transaction and notifies the organizer; format takes the job title
+func changeInterview(c *gin.Context, db *sql.DB, interviewID int, successMessage string,
+	change func(tx *sql.Tx) (services.InterviewChange, error), kind, format string) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := change(tx)
+	if err == nil {
+		data := map[string]interface{}{"interview_id": result.InterviewID, "application_id": result.ApplicationID, "status": result.Status}
+		err = services.CreateNotification(tx, result.EmployerID, kind, fmt.Sprintf(format, result.JobTitle), data)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if !services.RespondInterviewError(c, err) {
+			log.Printf("Error updating interview %d: %v", interviewID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update interview"})
+		}
+		return
+	}
+
+	log.Printf("Interview %d is now %s", interviewID, result.Status)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": successMessage, "interview_status": result.Status})
+}
+
+// InterviewCalendar downloads a scheduled interview as an iCalendar (.ics) file
+func InterviewCalendar(c *gin.Context) {
+	db, interview, ok := openOwnInterview(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	services.WriteInterviewICS(c, interview)
+}
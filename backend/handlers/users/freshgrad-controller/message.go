@@ -0,0 +1,151 @@
+package freshGrad
+
+import (
+	"database/sql"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openOwnApplication connects to the database and checks the application in the request path belongs to the
+// fresh grad, returning its status and writing the error response if not. The caller closes the returned database.
+func openOwnApplication(c *gin.Context) (*sql.DB, services.ApplicationStatus, bool) {
+	applicationID := c.Param("application-id")
+	freshGradID := c.MustGet("freshGrad_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return nil, "", false
+	}
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		db.Close()
+		return nil, "", false
+	}
+
+	var status services.ApplicationStatus
+	query := "SELECT a.status FROM applications a " +
+		"INNER JOIN freshgradprofiles f ON f.freshgradprofile_id = a.freshgradprofile_id " +
+		"WHERE a.application_id = ? AND f.user_id = ?"
+	if err := db.QueryRow(query, applicationID, freshGradID).Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Application not found"})
+		} else {
+			log.Printf("Error loading application %s: %v", applicationID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		}
+		db.Close()
+		return nil, "", false
+	}
+	return db, status, true
+}
+
+// MessageViews returns the conversation of the fresh grad's application and marks it read
+func MessageViews(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	freshGradID := c.MustGet("freshGrad_id")
+
+	db, _, ok := openOwnApplication(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	if err := services.MarkMessagesRead(db, applicationID, freshGradID); err != nil {
+		log.Printf("Error marking messages of application %s read: %v", applicationID, err)
+	}
+	messages, err := services.LoadMessages(db, applicationID, false)
+	if err != nil {
+		log.Printf("Error loading messages of application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": messages})
+}
+
+// MessageCreate sends a message, with optional attachments, to the employer of the fresh grad's application
+func MessageCreate(c *gin.Context) {
+	applicationID := c.Param("application-id")
+	freshGradID := c.MustGet("freshGrad_id")
+
+	input, fieldErrors, err := services.ParseMessageInput(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": fieldErrors})
+		return
+	}
+
+	db, status, ok := openOwnApplication(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	if status == services.ApplicationStatusWithdrawn {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": "Cannot send messages on a withdrawn application"})
+		return
+	}
+
+	storage, err := services.NewStorage()
+	if err != nil {
+		log.Printf("File storage error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "File storage error"})
+		return
+	}
+	messageID, err := services.SendMessage(db, storage, applicationID, freshGradID, services.MessageSenderFreshGrad, input)
+	if err != nil {
+		log.Printf("Error sending message on application %s: %v", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to send message"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Message sent successfully", "message_id": messageID})
+}
+
+// MessageAttachmentView downloads an attachment of the fresh grad's conversation
+func MessageAttachmentView(c *gin.Context) {
+	db, _, ok := openOwnApplication(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	services.WriteMessageAttachment(c, db, c.Param("application-id"), false)
+}
+
+// UnreadMessageViews counts the fresh grad's unread messages per application
+func UnreadMessageViews(c *gin.Context) {
+	freshGradID := c.MustGet("freshGrad_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		return
+	}
+
+	counts, err := services.UnreadMessageCounts(db, freshGradID, "f.user_id = ?", freshGradID)
+	if err != nil {
+		log.Printf("Error counting unread messages of freshGrad %v: %v", freshGradID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count.Unread
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "total": total, "data": counts})
+}
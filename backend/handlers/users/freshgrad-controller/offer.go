@@ -0,0 +1,138 @@
+package freshGrad
+
+import (
+	"database/sql"
+	"fmt"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OfferViews lists the fresh grad's offers, newest first, optionally filtered by status
+func OfferViews(c *gin.Context) {
+	freshGradID := c.MustGet("freshGrad_id")
+
+	conditions := []string{"f.user_id = ?"}
+	args := []interface{}{freshGradID}
+	if value := strings.TrimSpace(c.Query("status")); value != "" {
+		status, ok := services.ParseOfferStatus(value)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": gin.H{"status": "is not a valid offer status"}})
+			return
+		}
+		statusCondition, statusArgs := services.OfferStatusCondition(status)
+		conditions = append(conditions, statusCondition)
+		args = append(args, statusArgs...)
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		return
+	}
+
+	offers, err := services.LoadOffers(db, strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		log.Printf("Error loading offers of freshGrad %v: %v", freshGradID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": offers})
+}
+
+// OfferAccept accepts an offer; the application becomes hired and fills one of the job's positions
+func OfferAccept(c *gin.Context) {
+	freshGradID := c.MustGet("freshGrad_id")
+	entry := services.NewAuditEntry(c, freshGradID, "freshGrad", "", "", nil)
+	answerOffer(c, "Offer accepted successfully", func(tx *sql.Tx, offerID, note string) (services.OfferChange, error) {
+		change, err := services.AcceptOffer(tx, offerID, note, entry)
+		if err == nil && change.JobFilled {
+			message := fmt.Sprintf("Every position of \"%s\" is now filled", change.JobTitle)
+			err = services.CreateNotification(tx, change.EmployerID, "job.filled", message, map[string]interface{}{"job_id": change.JobID})
+		}
+		return change, err
+	}, "offer.accepted", "The candidate accepted your offer for \"%s\"")
+}
+
+// OfferDecline declines an offer with an optional note
+func OfferDecline(c *gin.Context) {
+	answerOffer(c, "Offer declined successfully", func(tx *sql.Tx, offerID, note string) (services.OfferChange, error) {
+		return services.DeclineOffer(tx, offerID, note)
+	}, "offer.declined", "The candidate declined your offer for \"%s\"")
+}
+
+// answerOffer checks the offer in the request path is the fresh grad's, runs answer in a transaction and
+// notifies the employer who made the offer; format takes the job title
+func answerOffer(c *gin.Context, successMessage string, answer func(tx *sql.Tx, offerID, note string) (services.OfferChange, error), kind, format string) {
+	offerID := c.Param("offer-id")
+	freshGradID := c.MustGet("freshGrad_id")
+
+	var request struct {
+		Note string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		return
+	}
+
+	var own bool
+	query := "SELECT EXISTS (SELECT 1 " + services.OfferFrom + "WHERE o.offer_id = ? AND f.user_id = ?)"
+	if err := db.QueryRow(query, offerID, freshGradID).Scan(&own); err != nil {
+		log.Printf("Error checking offer %s: %v", offerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if !own {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Offer not found"})
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	change, err := answer(tx, offerID, strings.TrimSpace(request.Note))
+	if err == nil {
+		data := map[string]interface{}{"offer_id": change.OfferID, "application_id": change.ApplicationID, "status": change.Status}
+		err = services.CreateNotification(tx, change.EmployerID, kind, fmt.Sprintf(format, change.JobTitle), data)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if !services.RespondOfferError(c, err) {
+			log.Printf("Error answering offer %s: %v", offerID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update offer"})
+		}
+		return
+	}
+
+	log.Printf("Offer %s is now %s", offerID, change.Status)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": successMessage, "offer_status": change.Status})
+}
@@ -0,0 +1,170 @@
+package freshGrad
+
+import (
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BlockedCompanyViews lists the companies the fresh grad has blocked
+func BlockedCompanyViews(c *gin.Context) {
+	db, profileID, ok := openOwnProfile(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	companies, err := services.LoadBlockedCompanies(db, profileID)
+	if err != nil {
+		log.Printf("Error loading blocked companies of profile %d: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": companies})
+}
+
+// CompanyBlock blocks a company from finding the fresh grad's profile, contacting them or seeing contact details
+// they shared; the company still sees applications the fresh grad sends it
+func CompanyBlock(c *gin.Context) {
+	companyID := c.Param("company-id")
+
+	db, profileID, ok := openOwnProfile(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	err = services.BlockCompany(tx, profileID, companyID)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if !services.RespondPrivacyError(c, err) {
+			log.Printf("Error blocking company %s for profile %d: %v", companyID, profileID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to block company"})
+		}
+		return
+	}
+
+	log.Printf("Profile %d blocked company %s", profileID, companyID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Company blocked successfully"})
+}
+
+// CompanyUnblock lifts a block on a company
+func CompanyUnblock(c *gin.Context) {
+	companyID := c.Param("company-id")
+
+	db, profileID, ok := openOwnProfile(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	unblocked, err := services.UnblockCompany(db, profileID, companyID)
+	if err != nil {
+		log.Printf("Error unblocking company %s for profile %d: %v", companyID, profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to unblock company"})
+		return
+	}
+	if !unblocked {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Company is not blocked"})
+		return
+	}
+
+	log.Printf("Profile %d unblocked company %s", profileID, companyID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Company unblocked successfully"})
+}
+
+// ContactRequestViews lists the requests employers sent to see the fresh grad's contact details, newest first,
+// optionally filtered by status
+func ContactRequestViews(c *gin.Context) {
+	conditions := []string{"cr.freshgradprofile_id = ?"}
+	var filterArgs []interface{}
+	if value := strings.TrimSpace(c.Query("status")); value != "" {
+		status, ok := services.ParseContactRequestStatus(value)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": gin.H{"status": "is not a valid contact request status"}})
+			return
+		}
+		conditions = append(conditions, "cr.status = ?")
+		filterArgs = append(filterArgs, string(status))
+	}
+
+	db, profileID, ok := openOwnProfile(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	requests, err := services.LoadContactRequests(db, strings.Join(conditions, " AND "), append([]interface{}{profileID}, filterArgs...)...)
+	if err != nil {
+		log.Printf("Error loading contact requests of profile %d: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": requests})
+}
+
+// ContactRequestAccept shares the fresh grad's email and phone with the requesting team
+func ContactRequestAccept(c *gin.Context) {
+	answerContactRequest(c, true, "Contact request accepted successfully", "contact.accepted",
+		"A candidate accepted your contact request")
+}
+
+// ContactRequestDecline declines a contact request
+func ContactRequestDecline(c *gin.Context) {
+	answerContactRequest(c, false, "Contact request declined successfully", "contact.declined",
+		"A candidate declined your contact request")
+}
+
+// answerContactRequest answers a pending contact request to the fresh grad in a transaction and notifies the
+// employer who sent it
+func answerContactRequest(c *gin.Context, accept bool, successMessage, kind, message string) {
+	requestID := c.Param("request-id")
+
+	db, profileID, ok := openOwnProfile(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	change, err := services.AnswerContactRequest(tx, requestID, profileID, accept)
+	if err == nil {
+		data := map[string]interface{}{"contact_request_id": change.RequestID, "fresh_grad_profile_id": profileID, "status": change.Status}
+		err = services.CreateNotification(tx, change.EmployerID, kind, message, data)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if !services.RespondPrivacyError(c, err) {
+			log.Printf("Error answering contact request %s: %v", requestID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update contact request"})
+		}
+		return
+	}
+
+	log.Printf("Contact request %s is now %s", requestID, change.Status)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": successMessage, "contact_request_status": change.Status})
+}
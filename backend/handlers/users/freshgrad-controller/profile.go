@@ -0,0 +1,190 @@
+package freshGrad
+
+import (
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Profile is a fresh grad's own profile
+type Profile struct {
+	ProfileID         int      `json:"freshgradprofile_id"`
+	ResumeFileLink    *string  `json:"resume_file_link"`
+	ExperienceYears   *int     `json:"experience_years"`
+	PreferredLocation *string  `json:"preferred_location"`
+	ExpectedSalary    *float64 `json:"expected_salary"`
+	PreferredJobLevel *string  `json:"preferred_job_level"`
+	University        *string  `json:"university"`
+	GraduationYear    *int     `json:"graduation_year"`
+	GPA               *float64 `json:"gpa"`
+	Location          *string  `json:"location"`
+	FullName          *string  `json:"full_name"`
+	Phone             *string  `json:"phone"`
+	// Visibility is who can find the profile outside the fresh grad's applications
+	Visibility string `json:"visibility"`
+}
+
+// profileColumns are the freshgradprofiles columns scanned into Profile
+const profileColumns = "freshgradprofile_id, resume_file_link, experience_years, preferred_location, expected_salary, preferred_job_level, " +
+	"university, graduation_year, gpa, location, full_name, phone, visibility"
+
+// scanTargets returns pointers to the profile's fields in profileColumns order
+func (profile *Profile) scanTargets() []interface{} {
+	return []interface{}{
+		&profile.ProfileID, &profile.ResumeFileLink, &profile.ExperienceYears, &profile.PreferredLocation,
+		&profile.ExpectedSalary, &profile.PreferredJobLevel, &profile.University, &profile.GraduationYear, &profile.GPA,
+		&profile.Location, &profile.FullName, &profile.Phone, &profile.Visibility,
+	}
+}
+
+// ProfileView returns the fresh grad's profile
+func ProfileView(c *gin.Context) {
+	freshGradID := c.MustGet("freshGrad_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		return
+	}
+	profileID, ok := freshGradProfileID(c, db, freshGradID)
+	if !ok {
+		return
+	}
+
+	var profile Profile
+	query := "SELECT " + profileColumns + " FROM freshgradprofiles WHERE freshgradprofile_id = ?"
+	if err := db.QueryRow(query, profileID).Scan(profile.scanTargets()...); err != nil {
+		log.Printf("Error loading profile %d: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": profile})
+}
+
+// ProfileUpdate changes the fields present in the request; null clears a preference
+func ProfileUpdate(c *gin.Context) {
+	freshGradID := c.MustGet("freshGrad_id")
+
+	var request map[string]interface{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	var fields []string
+	var values []interface{}
+	fieldErrors := services.FieldErrors{}
+	for field, value := range request {
+		switch field {
+		case "experience_years", "expected_salary":
+			if value == nil {
+				break
+			}
+			number, ok := value.(float64)
+			if !ok || number < 0 {
+				fieldErrors[field] = "must be a non-negative number"
+				continue
+			}
+			if field == "experience_years" && number != float64(int(number)) {
+				fieldErrors[field] = "must be a whole number"
+				continue
+			}
+		case "graduation_year":
+			if value == nil {
+				break
+			}
+			year, ok := value.(float64)
+			if !ok || year != float64(int(year)) || year < 1950 || year > 2100 {
+				fieldErrors[field] = "must be a year"
+				continue
+			}
+		case "gpa":
+			if value == nil {
+				break
+			}
+			gpa, ok := value.(float64)
+			if !ok || gpa < 0 || gpa > 4 {
+				fieldErrors[field] = "must be a number between 0 and 4"
+				continue
+			}
+		case "preferred_location", "university", "location", "full_name", "phone":
+			if value == nil {
+				break
+			}
+			text, ok := value.(string)
+			if !ok {
+				fieldErrors[field] = "must be a string"
+				continue
+			}
+			value = strings.TrimSpace(text)
+		case "preferred_job_level":
+			if value == nil {
+				break
+			}
+			text, _ := value.(string)
+			level, ok := services.ParseJobLevel(text)
+			if !ok {
+				fieldErrors[field] = "must be one of the job levels"
+				continue
+			}
+			value = string(level)
+		case "visibility":
+			text, _ := value.(string)
+			visibility, ok := services.ParseProfileVisibility(text)
+			if !ok {
+				fieldErrors[field] = services.ProfileVisibilityMessage
+				continue
+			}
+			value = string(visibility)
+		default:
+			fieldErrors[field] = "is not an editable profile field"
+			continue
+		}
+		fields = append(fields, field+" = ?")
+		values = append(values, value)
+	}
+	if len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": fieldErrors})
+		return
+	}
+	if len(fields) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "No fields to update"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		return
+	}
+	profileID, ok := freshGradProfileID(c, db, freshGradID)
+	if !ok {
+		return
+	}
+
+	updateQuery := "UPDATE freshgradprofiles SET " + strings.Join(fields, ", ") + " WHERE freshgradprofile_id = ?"
+	if _, err := db.Exec(updateQuery, append(values, profileID)...); err != nil {
+		log.Printf("Error updating profile %d: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update profile"})
+		return
+	}
+
+	log.Printf("Profile %d updated", profileID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Profile updated successfully"})
+}
@@ -0,0 +1,129 @@
+package freshGrad
+
+import (
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recommendations scores every visible job against the fresh grad's profile and returns the best matches
+// with an explanation of each score
+func Recommendations(c *gin.Context) {
+	freshGradID := c.MustGet("freshGrad_id")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "limit must be between 1 and 100"})
+		return
+	}
+	minScore, err := strconv.ParseFloat(c.DefaultQuery("min_score", "0"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "min_score must be a number"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		return
+	}
+	profileID, ok := freshGradProfileID(c, db, freshGradID)
+	if !ok {
+		return
+	}
+
+	var profile services.CandidateProfile
+	profileQuery := "SELECT " + services.CandidateProfileColumns + " FROM freshgradprofiles f WHERE f.freshgradprofile_id = ?"
+	if err := db.QueryRow(profileQuery, profileID).Scan(profile.ScanTargets()...); err != nil {
+		log.Printf("Error loading profile %d: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	profileSkills, err := services.SkillIDsByOwner(db, "freshgradprofile_skills", "freshgradprofile_id", []int{profileID})
+	if err != nil {
+		log.Printf("Error loading skills of profile %d: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	profile.SkillIDs = profileSkills[profileID]
+
+	type Recommendation struct {
+		JobID       int                 `json:"job_id"`
+		Title       string              `json:"title"`
+		CompanyName *string             `json:"company_name"`
+		Location    string              `json:"location"`
+		JobType     string              `json:"job_type"`
+		JobLevel    string              `json:"job_level"`
+		MinSalary   float64             `json:"min_salary"`
+		MaxSalary   float64             `json:"max_salary"`
+		Match       services.MatchScore `json:"match"`
+		job         services.JobRequirements
+	}
+
+	// Only jobs passing the visibility policy are recommended
+	query := "SELECT " + services.JobRequirementsColumns + ", j.title, co.name, j.job_type FROM jobs j " +
+		"LEFT JOIN companies co ON co.company_id = j.company_id WHERE " + services.VisibleJobCondition("j", time.Now())
+	rows, err := db.Query(query)
+	if err != nil {
+		log.Printf("Query execution error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	defer rows.Close()
+
+	var candidates []Recommendation
+	var jobIDs []int
+	for rows.Next() {
+		var recommendation Recommendation
+		targets := append(recommendation.job.ScanTargets(), &recommendation.Title, &recommendation.CompanyName, &recommendation.JobType)
+		if err := rows.Scan(targets...); err != nil {
+			log.Printf("Row scan error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
+			return
+		}
+		candidates = append(candidates, recommendation)
+		jobIDs = append(jobIDs, recommendation.job.JobID)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Rows iteration error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	jobSkills, err := services.SkillIDsByOwner(db, "job_skills", "job_id", jobIDs)
+	if err != nil {
+		log.Printf("Error loading job skills: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	scores := make([]services.MatchScore, len(candidates))
+	for i := range candidates {
+		candidate := &candidates[i]
+		candidate.job.SkillIDs = jobSkills[candidate.job.JobID]
+		candidate.JobID, candidate.Location, candidate.JobLevel = candidate.job.JobID, candidate.job.Location, candidate.job.JobLevel
+		candidate.MinSalary, candidate.MaxSalary = candidate.job.MinSalary, candidate.job.MaxSalary
+		candidate.Match = services.ScoreMatch(profile, candidate.job)
+		scores[i] = candidate.Match
+	}
+
+	recommendations := []Recommendation{}
+	for _, i := range services.SortByScore(scores) {
+		if len(recommendations) == limit || scores[i].Total < minScore {
+			break
+		}
+		recommendations = append(recommendations, candidates[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": recommendations})
+}
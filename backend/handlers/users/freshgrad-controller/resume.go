@@ -0,0 +1,272 @@
+package freshGrad
+
+import (
+	"database/sql"
+	"errors"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openOwnProfile connects to the database and returns the fresh grad's profile ID, writing the error response
+// if the account cannot use it. The caller closes the returned database.
+func openOwnProfile(c *gin.Context) (*sql.DB, int, bool) {
+	freshGradID := c.MustGet("freshGrad_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return nil, 0, false
+	}
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		db.Close()
+		return nil, 0, false
+	}
+	profileID, ok := freshGradProfileID(c, db, freshGradID)
+	if !ok {
+		db.Close()
+		return nil, 0, false
+	}
+	return db, profileID, true
+}
+
+// ResumeViews lists the fresh grad's resumes, the default first, with every version
+func ResumeViews(c *gin.Context) {
+	db, profileID, ok := openOwnProfile(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	resumes, err := services.LoadResumes(db, profileID)
+	if err != nil {
+		log.Printf("Error loading resumes of profile %d: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": resumes})
+}
+
+// ResumeCreate adds a named resume from an uploaded PDF or DOCX file, or from a link
+func ResumeCreate(c *gin.Context) {
+	input, fieldErrors, err := services.ParseResumeInput(c, true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": fieldErrors})
+		return
+	}
+
+	db, profileID, ok := openOwnProfile(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	storage, err := services.NewStorage()
+	if err != nil {
+		log.Printf("File storage error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "File storage error"})
+		return
+	}
+	resumeID, err := services.CreateResume(db, storage, profileID, input)
+	if err != nil {
+		if !services.RespondResumeError(c, err) {
+			log.Printf("Error creating resume for profile %d: %v", profileID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to save resume"})
+		}
+		return
+	}
+
+	log.Printf("Resume %d created for profile %d", resumeID, profileID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Resume saved successfully", "resume_id": resumeID})
+}
+
+// ResumeVersionCreate replaces the file of a resume with a new version; applications keep the version they
+// were submitted with
+func ResumeVersionCreate(c *gin.Context) {
+	resumeID := c.Param("resume-id")
+
+	input, fieldErrors, err := services.ParseResumeInput(c, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": fieldErrors})
+		return
+	}
+
+	db, profileID, ok := openOwnProfile(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	storage, err := services.NewStorage()
+	if err != nil {
+		log.Printf("File storage error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "File storage error"})
+		return
+	}
+	version, err := services.AddResumeVersion(db, storage, profileID, resumeID, input)
+	if err != nil {
+		if !services.RespondResumeError(c, err) {
+			log.Printf("Error adding a version to resume %s: %v", resumeID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to save resume"})
+		}
+		return
+	}
+
+	log.Printf("Resume %s is now at version %d", resumeID, version.Version)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Resume updated successfully", "data": version})
+}
+
+// ResumeUpdate renames a resume or makes it the default
+func ResumeUpdate(c *gin.Context) {
+	resumeID := c.Param("resume-id")
+
+	var update services.ResumeUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if fieldErrors := update.Validate(); len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": fieldErrors})
+		return
+	}
+
+	changeResume(c, "Resume updated successfully", func(tx *sql.Tx, profileID int) error {
+		return services.UpdateResume(tx, profileID, resumeID, update)
+	})
+}
+
+// ResumeDelete removes a resume from the fresh grad's list; applications submitted with it still show it
+func ResumeDelete(c *gin.Context) {
+	resumeID := c.Param("resume-id")
+
+	changeResume(c, "Resume deleted successfully", func(tx *sql.Tx, profileID int) error {
+		return services.DeleteResume(tx, profileID, resumeID)
+	})
+}
+
+// changeResume runs change on the fresh grad's profile in a transaction
+func changeResume(c *gin.Context, successMessage string, change func(tx *sql.Tx, profileID int) error) {
+	resumeID := c.Param("resume-id")
+
+	db, profileID, ok := openOwnProfile(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	err = change(tx, profileID)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if !services.RespondResumeError(c, err) {
+			log.Printf("Error changing resume %s: %v", resumeID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update resume"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": successMessage})
+}
+
+// ResumeVersionFile downloads a version of one of the fresh grad's resumes, including deleted ones their
+// applications were submitted with
+func ResumeVersionFile(c *gin.Context) {
+	db, profileID, ok := openOwnProfile(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	services.WriteResumeVersion(c, db, "rv.resume_version_id = ? AND r.resume_id = ? AND r.freshgradprofile_id = ?",
+		c.Param("version-id"), c.Param("resume-id"), profileID)
+}
+
+// ResumeParse reads an uploaded PDF or DOCX resume without saving it and returns a draft profile for the fresh
+// grad to review; the reviewed fields are saved with PUT /profile and PUT /profile/skills
+func ResumeParse(c *gin.Context) {
+	data, contentType, fieldErrors, err := services.ReadResumeUpload(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": fieldErrors})
+		return
+	}
+
+	db, _, ok := openOwnProfile(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	respondResumeDraft(c, db, contentType, data)
+}
+
+// ResumeVersionParse drafts a profile from an uploaded version of one of the fresh grad's resumes
+func ResumeVersionParse(c *gin.Context) {
+	resumeID, versionID := c.Param("resume-id"), c.Param("version-id")
+
+	db, profileID, ok := openOwnProfile(c)
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	storage, err := services.NewStorage()
+	if err != nil {
+		log.Printf("File storage error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "File storage error"})
+		return
+	}
+	data, contentType, err := services.ReadResumeVersion(db, storage, profileID, resumeID, versionID)
+	if errors.Is(err, services.ErrResumeFileType) {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": "Linked resumes cannot be parsed; upload the file instead"})
+		return
+	}
+	if err != nil {
+		if !services.RespondResumeError(c, err) {
+			log.Printf("Error reading version %s of resume %s: %v", versionID, resumeID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "File storage error"})
+		}
+		return
+	}
+
+	respondResumeDraft(c, db, contentType, data)
+}
+
+// respondResumeDraft parses a resume file and writes the draft profile
+func respondResumeDraft(c *gin.Context, db *sql.DB, contentType string, data []byte) {
+	draft, err := services.ParseResume(db, contentType, data, time.Now())
+	if err != nil {
+		if !services.RespondResumeError(c, err) {
+			log.Printf("Error parsing resume: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to parse resume"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": draft})
+}
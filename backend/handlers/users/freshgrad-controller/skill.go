@@ -0,0 +1,119 @@
+package freshGrad
+
+import (
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileSkillsView lists the catalogue skills on the fresh grad's profile
+func ProfileSkillsView(c *gin.Context) {
+	freshGradID := c.MustGet("freshGrad_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		return
+	}
+	profileID, ok := freshGradProfileID(c, db, freshGradID)
+	if !ok {
+		return
+	}
+
+	query := "SELECT s.skill_id, s.name, s.name_th FROM freshgradprofile_skills ps " +
+		"INNER JOIN skills s ON s.skill_id = ps.skill_id WHERE ps.freshgradprofile_id = ? ORDER BY s.name"
+	rows, err := db.Query(query, profileID)
+	if err != nil {
+		log.Printf("Error querying skills of profile %d: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	defer rows.Close()
+
+	skills := []services.Skill{}
+	for rows.Next() {
+		var skill services.Skill
+		if err := rows.Scan(&skill.ID, &skill.Name, &skill.NameTH); err != nil {
+			log.Printf("Row scan error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Row scan error"})
+			return
+		}
+		skills = append(skills, skill)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": skills})
+}
+
+// ProfileSkillsUpdate replaces the skills on the fresh grad's profile; names may be aliases or Thai names
+func ProfileSkillsUpdate(c *gin.Context) {
+	freshGradID := c.MustGet("freshGrad_id")
+
+	var request struct {
+		Skills []string `json:"skills"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	if !checkFreshGradStatus(c, db, freshGradID) {
+		return
+	}
+	profileID, ok := freshGradProfileID(c, db, freshGradID)
+	if !ok {
+		return
+	}
+
+	skills, unknown, err := services.ResolveSkills(db, request.Skills)
+	if err != nil {
+		log.Printf("Error resolving profile skills: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if len(unknown) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validation failed",
+			"errors":  services.FieldErrors{"skills": "unknown skills: " + strings.Join(unknown, ", ")},
+		})
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error starting transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	if err := services.SetProfileSkills(tx, profileID, services.SkillIDs(skills)); err != nil {
+		log.Printf("Error updating skills of profile %d: %v", profileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update skills"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Error committing transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Skills updated successfully", "data": skills})
+}
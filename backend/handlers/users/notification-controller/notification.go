@@ -0,0 +1,336 @@
+package notification
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeat keeps idle streams open through proxies
+const streamHeartbeat = 25 * time.Second
+
+// streamBatchSize caps the notifications sent per database read
+const streamBatchSize = 100
+
+// AuthMiddleware accepts a JWT of any role and stores user_id. The token comes from the Authorization header,
+// or the token query parameter for EventSource clients, which cannot set headers.
+func AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+
+		var token string
+		switch {
+		case strings.HasPrefix(authHeader, "Bearer "):
+			token = strings.TrimPrefix(authHeader, "Bearer ")
+		case authHeader == "" && c.Query("token") != "":
+			token = c.Query("token")
+		default:
+			log.Println("Authorization header missing or malformed")
+			c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "message": "Authorization header missing or malformed"})
+			c.Abort()
+			return
+		}
+
+		jwtClaims, err := services.ValidateJWT(token)
+		if err != nil {
+			log.Printf("Token validation failed: %v", err)
+			c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "message": "Invalid token", "details": err.Error()})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", jwtClaims.ID)
+		c.Next()
+	}
+}
+
+// NotificationViews lists the user's notifications, newest first, with unread=true for unread ones only
+// and limit (default 50, max 200)
+func NotificationViews(c *gin.Context) {
+	userID := c.MustGet("user_id")
+
+	limit := 50
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 200 {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": gin.H{"limit": "must be between 1 and 200"}})
+			return
+		}
+		limit = parsed
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	notifications, err := services.ListNotifications(db, userID, 0, c.Query("unread") == "true", limit)
+	if err != nil {
+		log.Printf("Error loading notifications of user %v: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	unread, err := services.UnreadNotificationCount(db, userID)
+	if err != nil {
+		log.Printf("Error counting notifications of user %v: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "unread": unread, "data": notifications})
+}
+
+// UnreadCountView returns the number of unread notifications
+func UnreadCountView(c *gin.Context) {
+	userID := c.MustGet("user_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	unread, err := services.UnreadNotificationCount(db, userID)
+	if err != nil {
+		log.Printf("Error counting notifications of user %v: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "unread": unread})
+}
+
+// NotificationRead marks one notification read
+func NotificationRead(c *gin.Context) {
+	userID := c.MustGet("user_id")
+	notificationID, err := strconv.ParseInt(c.Param("notification-id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Notification not found"})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1 FROM notifications WHERE notification_id = ? AND user_id = ?)"
+	if err := db.QueryRow(query, notificationID, userID).Scan(&exists); err != nil {
+		log.Printf("Error checking notification %d: %v", notificationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Notification not found"})
+		return
+	}
+
+	if _, err := services.MarkNotificationsRead(db, userID, notificationID); err != nil {
+		log.Printf("Error marking notification %d read: %v", notificationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update notification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Notification marked as read"})
+}
+
+// NotificationReadAll marks every notification of the user read
+func NotificationReadAll(c *gin.Context) {
+	userID := c.MustGet("user_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	changed, err := services.MarkNotificationsRead(db, userID)
+	if err != nil {
+		log.Printf("Error marking notifications of user %v read: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Notifications marked as read", "updated": changed})
+}
+
+// Stream pushes the user's new notifications as Server-Sent Events. Each event has the notification ID, so a
+// reconnecting client resumes after its Last-Event-ID header (or last_event_id query parameter); a new
+// client starts with a "ready" event carrying the unread count and then receives notifications created later.
+// The stream closes at the next poll once the user is suspended.
+func Stream(c *gin.Context) {
+	userID := c.MustGet("user_id").(int)
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	suspended, err := userSuspended(db, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "message": "User not found"})
+			return
+		}
+		log.Printf("Error checking user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if suspended {
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": "Account is suspended"})
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	lastID, _ := strconv.ParseInt(lastEventID, 10, 64)
+	if lastID <= 0 {
+		if lastID, err = services.LatestNotificationID(db, userID); err != nil {
+			log.Printf("Error starting notification stream for user %d: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+			return
+		}
+	}
+	unread, err := services.UnreadNotificationCount(db, userID)
+	if err != nil {
+		log.Printf("Error starting notification stream for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+
+	wake, unsubscribe := services.Notifications.Subscribe(userID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if writeEvent(c, "", "ready", gin.H{"unread": unread}) != nil {
+		return
+	}
+	// A catching-up client receives what it missed straight away
+	cursor := services.NewNotificationCursor(lastID)
+	if lastEventID != "" && sendNew(c, db, userID, cursor) != nil {
+		return
+	}
+
+	poll := time.NewTicker(services.NotificationPollInterval())
+	defer poll.Stop()
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	// A wake-up can arrive before the creating transaction commits, so the database is read once more shortly after
+	var recheck <-chan time.Time
+
+	log.Printf("Notification stream opened for user %d", userID)
+	defer log.Printf("Notification stream closed for user %d", userID)
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case _, open := <-wake:
+			if !open {
+				return
+			}
+			recheck = time.After(time.Second)
+			err = sendNew(c, db, userID, cursor)
+		case <-recheck:
+			recheck = nil
+			err = sendNew(c, db, userID, cursor)
+		case <-poll.C:
+			// Users suspended or deleted after connecting lose their stream; on reconnecting they are refused
+			if suspended, err = userSuspended(db, userID); err == sql.ErrNoRows || suspended {
+				err = errStreamRevoked
+			}
+			if err == nil {
+				err = sendNew(c, db, userID, cursor)
+			}
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		}
+		if err != nil {
+			log.Printf("Notification stream for user %d ended: %v", userID, err)
+			return
+		}
+	}
+}
+
+// errStreamRevoked ends the stream of a user suspended or deleted while connected
+var errStreamRevoked = errors.New("account suspended or deleted")
+
+// userSuspended reports whether the user is suspended, or returns sql.ErrNoRows when they no longer exist
+func userSuspended(db *sql.DB, userID int) (bool, error) {
+	var suspended bool
+	err := db.QueryRow("SELECT suspended FROM users WHERE user_id = ?", userID).Scan(&suspended)
+	return suspended, err
+}
+
+// sendNew writes the user's notifications the cursor has not sent yet
+func sendNew(c *gin.Context, db *sql.DB, userID int, cursor *services.NotificationCursor) error {
+	now := time.Now()
+	afterID := cursor.After(now)
+	for {
+		notifications, err := services.ListNotifications(db, userID, afterID, false, streamBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, notification := range notifications {
+			afterID = notification.ID
+			if !cursor.Send(notification.ID, now) {
+				continue
+			}
+			if err := writeEvent(c, strconv.FormatInt(notification.ID, 10), "notification", notification); err != nil {
+				return err
+			}
+		}
+		if len(notifications) < streamBatchSize {
+			return nil
+		}
+	}
+}
+
+// writeEvent writes one Server-Sent Event with a JSON payload and flushes it
+func writeEvent(c *gin.Context, id, event string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	var frame strings.Builder
+	if id != "" {
+		frame.WriteString("id: " + id + "\n")
+	}
+	frame.WriteString("event: " + event + "\n")
+	frame.WriteString("data: " + string(data) + "\n\n")
+	if _, err := c.Writer.WriteString(frame.String()); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
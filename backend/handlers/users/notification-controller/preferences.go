@@ -0,0 +1,69 @@
+package notification
+
+import (
+	"fresh-grad-jobs/services"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailPreferencesView returns the user's email language and opt-ins
+func EmailPreferencesView(c *gin.Context) {
+	userID := c.MustGet("user_id")
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	preferences, err := services.LoadEmailPreferences(db, userID)
+	if err != nil {
+		log.Printf("Error loading email preferences of user %v: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": preferences})
+}
+
+// EmailPreferencesUpdate changes the user's email preferences; omitted fields keep their value
+func EmailPreferencesUpdate(c *gin.Context) {
+	userID := c.MustGet("user_id")
+
+	var input services.EmailPreferencesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	db, err := services.ConnectDB()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database connection error"})
+		return
+	}
+	defer db.Close()
+
+	preferences, err := services.LoadEmailPreferences(db, userID)
+	if err != nil {
+		log.Printf("Error loading email preferences of user %v: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Query execution error"})
+		return
+	}
+	if fieldErrors := input.Apply(&preferences); len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": fieldErrors})
+		return
+	}
+	if err := services.SaveEmailPreferences(db, userID, preferences); err != nil {
+		log.Printf("Error saving email preferences of user %v: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to update email preferences"})
+		return
+	}
+
+	log.Printf("Email preferences of user %v updated", userID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Email preferences updated successfully", "data": preferences})
+}
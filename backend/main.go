@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	public "fresh-grad-jobs/handlers/public-controller"
 	admin "fresh-grad-jobs/handlers/users/admin-controller"
 	auth "fresh-grad-jobs/handlers/users/auth"
 	employer "fresh-grad-jobs/handlers/users/employer-controller"
 	freshGrad "fresh-grad-jobs/handlers/users/freshgrad-controller"
+	notification "fresh-grad-jobs/handlers/users/notification-controller"
+	"fresh-grad-jobs/services"
 	"log"
 	"net/http"
 	"os"
@@ -18,12 +21,24 @@ import (
 // TODO: CORS
 
 func main() {
-	// Create a new Gin router
-	router := gin.Default()
+	// Create a new Gin router, logging requests without the credentials some clients send in the query string
+	router := gin.New()
+	router.Use(services.RequestLogger(), gin.Recovery())
+
+	// Attach a request ID to every request for logging and auditing
+	router.Use(services.RequestIDMiddleware())
 
 	// Use the SignInHandler for the /signin route
 	router.POST("/signin", auth.SignInHandler)
 
+	// Public routes, no authentication required
+	publicRoute := router.Group("/public", public.RateLimitMiddleware())
+	{
+		publicRoute.GET("/jobs", public.JobViews)
+		publicRoute.GET("/jobs/:job-id", public.JobViews)
+		publicRoute.GET("/skills", public.SkillViews)
+	}
+
 	// Admin routes
 	adminRoute := router.Group("/admin", admin.AuthMiddleware())
 	{
@@ -36,6 +51,22 @@ func main() {
 		adminRoute.DELETE("/jobs/delete/:job-id", admin.JobDelete)
 		adminRoute.GET("/jobs", admin.JobViews)
 		adminRoute.GET("/jobs/:job-id", admin.JobViews)
+		adminRoute.GET("/audit", admin.AuditViews)
+		adminRoute.POST("/companies/verify/:company-id", admin.CompanyVerify)
+		adminRoute.POST("/companies/unverify/:company-id", admin.CompanyUnverify)
+		adminRoute.GET("/companies", admin.CompanyViews)
+		adminRoute.GET("/companies/:company-id", admin.CompanyViews)
+		adminRoute.GET("/skills", admin.SkillViews)
+		adminRoute.POST("/skills", admin.SkillCreate)
+		adminRoute.PUT("/skills/:skill-id", admin.SkillUpdate)
+		adminRoute.DELETE("/skills/:skill-id", admin.SkillDelete)
+		adminRoute.POST("/skills/:skill-id/aliases", admin.SkillAliasCreate)
+		adminRoute.DELETE("/skills/:skill-id/aliases/:alias", admin.SkillAliasDelete)
+		adminRoute.GET("/applications/:application-id/messages", admin.MessageViews)
+		adminRoute.GET("/applications/:application-id/messages/:message-id/attachments/:attachment-id", admin.MessageAttachmentView)
+		adminRoute.POST("/messages/:message-id/hide", admin.MessageHide)
+		adminRoute.POST("/messages/:message-id/unhide", admin.MessageUnhide)
+		adminRoute.GET("/analytics/hires", admin.HireAnalyticsView)
 	}
 
 	// Employer routes
@@ -46,9 +77,55 @@ func main() {
 		employerRoute.PUT("/jobs/update/:job-id", employer.JobUpdate)
 		employerRoute.GET("/jobs", employer.JobViews)
 		employerRoute.GET("/jobs/:job-id", employer.JobViews)
+		employerRoute.PUT("/jobs/:job-id/deadline", employer.JobDeadlineUpdate)
+		employerRoute.GET("/jobs/:job-id/questions", employer.ScreeningQuestionViews)
+		employerRoute.PUT("/jobs/:job-id/questions", employer.ScreeningQuestionsUpdate)
+		employerRoute.GET("/jobs/:job-id/suggested-candidates", employer.SuggestedCandidates)
+		employerRoute.GET("/candidates/:profile-id", employer.CandidateView)
+		employerRoute.POST("/candidates/:profile-id/contact-requests", employer.ContactRequestCreate)
+		employerRoute.GET("/contact-requests", employer.ContactRequestViews)
+		employerRoute.GET("/applications", employer.ApplicantSearch)
 		employerRoute.GET("/jobs/:job-id/applications", employer.ApplicationViews)
+		employerRoute.POST("/jobs/:job-id/applications/bulk", employer.ApplicationBulkAction)
 		employerRoute.GET("/jobs/:job-id/applications/:application-id", employer.ApplicationViews)
-		employerRoute.PUT("/jobs/:job-id/applications/:application-id/isFavorited", employer.FavoritedController)
+		employerRoute.GET("/jobs/:job-id/applications/:application-id/resume", employer.ApplicationResumeView)
+		employerRoute.PUT("/jobs/:job-id/applications/:application-id/status", employer.ApplicationStatusUpdate)
+		employerRoute.POST("/jobs/:job-id/applications/:application-id/notes", employer.ApplicationNoteCreate)
+		employerRoute.PUT("/jobs/:job-id/applications/:application-id/notes/:note-id", employer.ApplicationNoteUpdate)
+		employerRoute.DELETE("/jobs/:job-id/applications/:application-id/notes/:note-id", employer.ApplicationNoteDelete)
+		employerRoute.POST("/jobs/:job-id/applications/:application-id/tags", employer.ApplicationTagAdd)
+		employerRoute.DELETE("/jobs/:job-id/applications/:application-id/tags/:tag", employer.ApplicationTagRemove)
+		employerRoute.PUT("/jobs/:job-id/applications/:application-id/rating", employer.ApplicationRatingSet)
+		employerRoute.DELETE("/jobs/:job-id/applications/:application-id/rating", employer.ApplicationRatingDelete)
+		employerRoute.PUT("/jobs/:job-id/applications/:application-id/favorite", employer.FavoritedController)
+		employerRoute.DELETE("/jobs/:job-id/applications/:application-id/favorite", employer.FavoritedController)
+		// Deprecated: toggles the favorite; use PUT and DELETE .../favorite
+		employerRoute.PUT("/jobs/:job-id/applications/:application-id/isFavorited", employer.FavoritedToggle)
+		employerRoute.POST("/jobs/:job-id/applications/:application-id/interviews", employer.InterviewCreate)
+		employerRoute.GET("/jobs/:job-id/applications/:application-id/interviews", employer.ApplicationInterviewViews)
+		employerRoute.POST("/jobs/:job-id/applications/:application-id/offers", employer.OfferCreate)
+		employerRoute.GET("/jobs/:job-id/applications/:application-id/offers", employer.ApplicationOfferViews)
+		employerRoute.GET("/jobs/:job-id/applications/:application-id/messages", employer.MessageViews)
+		employerRoute.POST("/jobs/:job-id/applications/:application-id/messages", employer.MessageCreate)
+		employerRoute.GET("/jobs/:job-id/applications/:application-id/messages/:message-id/attachments/:attachment-id", employer.MessageAttachmentView)
+		employerRoute.GET("/messages/unread", employer.UnreadMessageViews)
+		employerRoute.GET("/interviews", employer.InterviewViews)
+		employerRoute.POST("/interviews/:interview-id/accept", employer.InterviewAccept)
+		employerRoute.POST("/interviews/:interview-id/propose", employer.InterviewPropose)
+		employerRoute.POST("/interviews/:interview-id/cancel", employer.InterviewCancel)
+		employerRoute.GET("/interviews/:interview-id/ics", employer.InterviewCalendar)
+		employerRoute.GET("/offers", employer.OfferViews)
+		employerRoute.POST("/offers/:offer-id/rescind", employer.OfferRescind)
+		employerRoute.POST("/company", employer.CompanyCreate)
+		employerRoute.PUT("/company", employer.CompanyUpdate)
+		employerRoute.GET("/company", employer.CompanyView)
+		employerRoute.GET("/company/members", employer.TeamMemberViews)
+		employerRoute.PUT("/company/members/:user-id", employer.TeamMemberUpdate)
+		employerRoute.DELETE("/company/members/:user-id", employer.TeamMemberRemove)
+		employerRoute.POST("/company/invitations", employer.InvitationCreate)
+		employerRoute.GET("/company/invitations", employer.InvitationViews)
+		employerRoute.DELETE("/company/invitations/:invitation-id", employer.InvitationRevoke)
+		employerRoute.POST("/company/invitations/:token/accept", employer.InvitationAccept)
 	}
 
 	//Freshgrad routes
@@ -56,6 +133,53 @@ func main() {
 	{
 		freshGradRoute.GET("/jobs", freshGrad.JobViews)
 		freshGradRoute.GET("/jobs/:job-id", freshGrad.JobViews)
+		freshGradRoute.GET("/jobs/:job-id/questions", freshGrad.JobQuestionViews)
+		freshGradRoute.POST("/jobs/:job-id/apply", freshGrad.JobApply)
+		freshGradRoute.GET("/companies/:company-id", freshGrad.CompanyView)
+		freshGradRoute.GET("/profile/skills", freshGrad.ProfileSkillsView)
+		freshGradRoute.PUT("/profile/skills", freshGrad.ProfileSkillsUpdate)
+		freshGradRoute.GET("/resumes", freshGrad.ResumeViews)
+		freshGradRoute.POST("/resumes", freshGrad.ResumeCreate)
+		freshGradRoute.PUT("/resumes/:resume-id", freshGrad.ResumeUpdate)
+		freshGradRoute.DELETE("/resumes/:resume-id", freshGrad.ResumeDelete)
+		freshGradRoute.POST("/resumes/:resume-id/versions", freshGrad.ResumeVersionCreate)
+		freshGradRoute.GET("/resumes/:resume-id/versions/:version-id/file", freshGrad.ResumeVersionFile)
+		freshGradRoute.POST("/resumes/:resume-id/versions/:version-id/parse", freshGrad.ResumeVersionParse)
+		freshGradRoute.POST("/profile/resume-parse", freshGrad.ResumeParse)
+		freshGradRoute.GET("/profile", freshGrad.ProfileView)
+		freshGradRoute.PUT("/profile", freshGrad.ProfileUpdate)
+		freshGradRoute.GET("/blocked-companies", freshGrad.BlockedCompanyViews)
+		freshGradRoute.PUT("/blocked-companies/:company-id", freshGrad.CompanyBlock)
+		freshGradRoute.DELETE("/blocked-companies/:company-id", freshGrad.CompanyUnblock)
+		freshGradRoute.GET("/contact-requests", freshGrad.ContactRequestViews)
+		freshGradRoute.POST("/contact-requests/:request-id/accept", freshGrad.ContactRequestAccept)
+		freshGradRoute.POST("/contact-requests/:request-id/decline", freshGrad.ContactRequestDecline)
+		freshGradRoute.GET("/recommendations", freshGrad.Recommendations)
+		freshGradRoute.GET("/interviews", freshGrad.InterviewViews)
+		freshGradRoute.POST("/interviews/:interview-id/accept", freshGrad.InterviewAccept)
+		freshGradRoute.POST("/interviews/:interview-id/propose", freshGrad.InterviewPropose)
+		freshGradRoute.POST("/interviews/:interview-id/decline", freshGrad.InterviewDecline)
+		freshGradRoute.GET("/interviews/:interview-id/ics", freshGrad.InterviewCalendar)
+		freshGradRoute.GET("/offers", freshGrad.OfferViews)
+		freshGradRoute.POST("/offers/:offer-id/accept", freshGrad.OfferAccept)
+		freshGradRoute.POST("/offers/:offer-id/decline", freshGrad.OfferDecline)
+		freshGradRoute.DELETE("/applications/:application-id", freshGrad.ApplicationWithdraw)
+		freshGradRoute.GET("/applications/:application-id/messages", freshGrad.MessageViews)
+		freshGradRoute.POST("/applications/:application-id/messages", freshGrad.MessageCreate)
+		freshGradRoute.GET("/applications/:application-id/messages/:message-id/attachments/:attachment-id", freshGrad.MessageAttachmentView)
+		freshGradRoute.GET("/messages/unread", freshGrad.UnreadMessageViews)
+	}
+
+	// Notification routes, for any signed-in user
+	notificationRoute := router.Group("/notifications", notification.AuthMiddleware())
+	{
+		notificationRoute.GET("", notification.NotificationViews)
+		notificationRoute.GET("/unread-count", notification.UnreadCountView)
+		notificationRoute.GET("/stream", notification.Stream)
+		notificationRoute.POST("/read-all", notification.NotificationReadAll)
+		notificationRoute.POST("/:notification-id/read", notification.NotificationRead)
+		notificationRoute.GET("/email-preferences", notification.EmailPreferencesView)
+		notificationRoute.PUT("/email-preferences", notification.EmailPreferencesUpdate)
 	}
 
 	// Get port from environment variable or default to 8080
@@ -68,6 +192,18 @@ func main() {
 		Addr:    ":" + port,
 		Handler: router,
 	}
+	// Open notification streams would otherwise hold up the graceful shutdown
+	srv.RegisterOnShutdown(services.Notifications.Close)
+
+	// Start background jobs, stopped when the server shuts down
+	schedulerCtx, stopSchedulers := context.WithCancel(context.Background())
+	defer stopSchedulers()
+	go services.StartJobExpiryScheduler(schedulerCtx, services.LoadJobExpiryConfig())
+	if emailConfig := services.LoadEmailConfig(); emailConfig.Enabled() {
+		go services.StartEmailWorker(schedulerCtx, emailConfig)
+	} else {
+		log.Println("SMTP_HOST is not set, emails stay in the outbox")
+	}
 
 	// Run the server in a goroutine to enable graceful shutdown
 	go func() {
@@ -84,6 +220,7 @@ func main() {
 
 	// Gracefully shutdown the server, waiting for 5 seconds for ongoing processes
 	log.Println("Shutting down server...")
+	stopSchedulers()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -0,0 +1,17 @@
+-- Audit trail of admin and employer actions, written in the same transaction as the change
+CREATE TABLE IF NOT EXISTS audit_logs (
+    audit_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    actor_id INT NOT NULL,
+    actor_role VARCHAR(32) NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32) NOT NULL,
+    target_id VARCHAR(64) NOT NULL,
+    before_data JSON NULL,
+    after_data JSON NULL,
+    request_id VARCHAR(64) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_audit_logs_target (target_type, target_id),
+    INDEX idx_audit_logs_actor (actor_id, created_at),
+    INDEX idx_audit_logs_action (action, created_at)
+);
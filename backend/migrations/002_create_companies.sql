@@ -0,0 +1,24 @@
+-- Company profiles managed by employers, with an admin verification badge
+CREATE TABLE IF NOT EXISTS companies (
+    company_id INT AUTO_INCREMENT PRIMARY KEY,
+    owner_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    logo_url VARCHAR(512) NOT NULL DEFAULT '',
+    industry VARCHAR(128) NOT NULL DEFAULT '',
+    company_size VARCHAR(16) NOT NULL DEFAULT '',
+    website VARCHAR(512) NOT NULL DEFAULT '',
+    description TEXT NOT NULL,
+    address TEXT NOT NULL,
+    verified BOOLEAN NOT NULL DEFAULT FALSE,
+    verified_at TIMESTAMP NULL,
+    verified_by INT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_companies_owner (owner_id),
+    CONSTRAINT fk_companies_owner FOREIGN KEY (owner_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+-- Jobs are linked to the company of the employer who posted them
+ALTER TABLE jobs
+    ADD COLUMN company_id INT NULL,
+    ADD CONSTRAINT fk_jobs_company FOREIGN KEY (company_id) REFERENCES companies (company_id) ON DELETE SET NULL;
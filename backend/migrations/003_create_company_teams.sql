@@ -0,0 +1,32 @@
+-- Company teams: each employer belongs to at most one company with an owner, recruiter or viewer role
+CREATE TABLE IF NOT EXISTS company_members (
+    company_id INT NOT NULL,
+    user_id INT NOT NULL,
+    role ENUM('owner', 'recruiter', 'viewer') NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (company_id, user_id),
+    UNIQUE KEY uq_company_members_user (user_id),
+    CONSTRAINT fk_company_members_company FOREIGN KEY (company_id) REFERENCES companies (company_id) ON DELETE CASCADE,
+    CONSTRAINT fk_company_members_user FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+-- Invitations to join a company, accepted by the invited employer using the token
+CREATE TABLE IF NOT EXISTS company_invitations (
+    invitation_id INT AUTO_INCREMENT PRIMARY KEY,
+    company_id INT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    role ENUM('owner', 'recruiter', 'viewer') NOT NULL,
+    token CHAR(64) NOT NULL,
+    invited_by INT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_company_invitations_token (token),
+    INDEX idx_company_invitations_email (email),
+    CONSTRAINT fk_company_invitations_company FOREIGN KEY (company_id) REFERENCES companies (company_id) ON DELETE CASCADE
+);
+
+-- Existing company creators become owners
+INSERT IGNORE INTO company_members (company_id, user_id, role)
+SELECT company_id, owner_id, 'owner' FROM companies;
@@ -0,0 +1,6 @@
+-- Track when a job last changed so public responses can send Last-Modified
+ALTER TABLE jobs
+    ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;
+
+-- Speeds up the public listing of approved, open jobs
+CREATE INDEX idx_jobs_public_listing ON jobs (approved, job_status, application_deadline, created_at);
@@ -0,0 +1,22 @@
+-- Leader lease so only one replica runs each scheduler at a time
+CREATE TABLE IF NOT EXISTS scheduler_locks (
+    name VARCHAR(64) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Persisted notifications for users
+CREATE TABLE IF NOT EXISTS notifications (
+    notification_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    message TEXT NOT NULL,
+    data JSON NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_notifications_user (user_id, created_at),
+    CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+-- When the employer was warned that the job is about to close
+ALTER TABLE jobs
+    ADD COLUMN expiry_notified_at TIMESTAMP NULL;
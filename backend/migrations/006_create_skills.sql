@@ -0,0 +1,62 @@
+-- Normalized skills catalogue curated by admins
+CREATE TABLE IF NOT EXISTS skills (
+    skill_id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    name_th VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_skills_name (name)
+);
+
+-- Alternative spellings that resolve to a catalogue skill, e.g. "JS" for JavaScript
+CREATE TABLE IF NOT EXISTS skill_aliases (
+    alias VARCHAR(100) PRIMARY KEY,
+    skill_id INT NOT NULL,
+    CONSTRAINT fk_skill_aliases_skill FOREIGN KEY (skill_id) REFERENCES skills (skill_id) ON DELETE CASCADE
+);
+
+-- Skills required by a job
+CREATE TABLE IF NOT EXISTS job_skills (
+    job_id INT NOT NULL,
+    skill_id INT NOT NULL,
+    PRIMARY KEY (job_id, skill_id),
+    INDEX idx_job_skills_skill (skill_id),
+    CONSTRAINT fk_job_skills_job FOREIGN KEY (job_id) REFERENCES jobs (job_id) ON DELETE CASCADE,
+    CONSTRAINT fk_job_skills_skill FOREIGN KEY (skill_id) REFERENCES skills (skill_id) ON DELETE CASCADE
+);
+
+-- Skills listed on a fresh grad profile
+CREATE TABLE IF NOT EXISTS freshgradprofile_skills (
+    freshgradprofile_id INT NOT NULL,
+    skill_id INT NOT NULL,
+    PRIMARY KEY (freshgradprofile_id, skill_id),
+    INDEX idx_freshgradprofile_skills_skill (skill_id),
+    CONSTRAINT fk_freshgradprofile_skills_profile FOREIGN KEY (freshgradprofile_id)
+        REFERENCES freshgradprofiles (freshgradprofile_id) ON DELETE CASCADE,
+    CONSTRAINT fk_freshgradprofile_skills_skill FOREIGN KEY (skill_id) REFERENCES skills (skill_id) ON DELETE CASCADE
+);
+
+-- Starter catalogue
+INSERT IGNORE INTO skills (name, name_th) VALUES
+    ('JavaScript', 'จาวาสคริปต์'),
+    ('TypeScript', 'ไทป์สคริปต์'),
+    ('Go', 'ภาษาโก'),
+    ('Python', 'ไพธอน'),
+    ('Java', 'จาวา'),
+    ('SQL', 'เอสคิวแอล'),
+    ('React', 'รีแอค'),
+    ('Microsoft Excel', 'ไมโครซอฟท์เอ็กเซล'),
+    ('Communication', 'การสื่อสาร'),
+    ('English', 'ภาษาอังกฤษ');
+
+INSERT IGNORE INTO skill_aliases (alias, skill_id)
+SELECT aliases.alias, s.skill_id
+FROM (
+    SELECT 'JS' AS alias, 'JavaScript' AS name
+    UNION ALL SELECT 'ECMAScript', 'JavaScript'
+    UNION ALL SELECT 'TS', 'TypeScript'
+    UNION ALL SELECT 'Golang', 'Go'
+    UNION ALL SELECT 'ReactJS', 'React'
+    UNION ALL SELECT 'React.js', 'React'
+    UNION ALL SELECT 'Excel', 'Microsoft Excel'
+) aliases
+INNER JOIN skills s ON s.name = aliases.name;
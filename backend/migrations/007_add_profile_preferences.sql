@@ -0,0 +1,6 @@
+-- Job preferences used to score fresh grads against jobs
+ALTER TABLE freshgradprofiles
+    ADD COLUMN experience_years INT NULL,
+    ADD COLUMN preferred_location VARCHAR(255) NULL,
+    ADD COLUMN expected_salary DECIMAL(12, 2) NULL,
+    ADD COLUMN preferred_job_level VARCHAR(32) NULL;
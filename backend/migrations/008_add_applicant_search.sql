@@ -0,0 +1,12 @@
+-- Application lifecycle and submission time
+ALTER TABLE applications
+    ADD COLUMN status VARCHAR(32) NOT NULL DEFAULT 'submitted',
+    ADD COLUMN applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD INDEX idx_applications_job_status (job_id, status, applied_at);
+
+-- Education and location shown to employers searching applicants
+ALTER TABLE freshgradprofiles
+    ADD COLUMN university VARCHAR(255) NULL,
+    ADD COLUMN graduation_year INT NULL,
+    ADD COLUMN gpa DECIMAL(3, 2) NULL,
+    ADD COLUMN location VARCHAR(255) NULL;
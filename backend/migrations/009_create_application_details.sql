@@ -0,0 +1,53 @@
+-- Cover letter sent with the application
+ALTER TABLE applications
+    ADD COLUMN cover_letter TEXT NULL;
+
+-- Contact details, shown to employers once an application is far enough in the pipeline
+ALTER TABLE freshgradprofiles
+    ADD COLUMN full_name VARCHAR(255) NULL,
+    ADD COLUMN phone VARCHAR(32) NULL;
+
+-- Every status change of an application
+CREATE TABLE IF NOT EXISTS application_status_history (
+    history_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    application_id INT NOT NULL,
+    from_status VARCHAR(32) NULL,
+    to_status VARCHAR(32) NOT NULL,
+    changed_by INT NULL,
+    note TEXT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_application_status_history_application (application_id, created_at),
+    CONSTRAINT fk_application_status_history_application FOREIGN KEY (application_id)
+        REFERENCES applications (application_id) ON DELETE CASCADE
+);
+
+-- Answers to the job's screening questions; the question text is kept as it was when answered
+CREATE TABLE IF NOT EXISTS application_answers (
+    answer_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    application_id INT NOT NULL,
+    question_id INT NULL,
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_application_answers_application (application_id),
+    CONSTRAINT fk_application_answers_application FOREIGN KEY (application_id)
+        REFERENCES applications (application_id) ON DELETE CASCADE
+);
+
+-- Private notes written by the employer's team, never shown to fresh grads
+CREATE TABLE IF NOT EXISTS application_notes (
+    note_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    application_id INT NOT NULL,
+    author_id INT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_application_notes_application (application_id, created_at),
+    CONSTRAINT fk_application_notes_application FOREIGN KEY (application_id)
+        REFERENCES applications (application_id) ON DELETE CASCADE,
+    CONSTRAINT fk_application_notes_author FOREIGN KEY (author_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+-- Existing applications start their history at submission
+INSERT INTO application_status_history (application_id, from_status, to_status, created_at)
+SELECT application_id, NULL, status, applied_at FROM applications;
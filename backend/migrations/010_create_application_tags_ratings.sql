@@ -0,0 +1,24 @@
+-- Custom tags the employer's team puts on applications, e.g. "strong Go" or "relocate"
+CREATE TABLE IF NOT EXISTS application_tags (
+    application_id INT NOT NULL,
+    tag VARCHAR(50) NOT NULL,
+    created_by INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (application_id, tag),
+    INDEX idx_application_tags_tag (tag),
+    CONSTRAINT fk_application_tags_application FOREIGN KEY (application_id)
+        REFERENCES applications (application_id) ON DELETE CASCADE
+);
+
+-- One 1–5 rating per reviewer and application
+CREATE TABLE IF NOT EXISTS application_ratings (
+    application_id INT NOT NULL,
+    reviewer_id INT NOT NULL,
+    rating TINYINT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (application_id, reviewer_id),
+    CONSTRAINT chk_application_ratings_rating CHECK (rating BETWEEN 1 AND 5),
+    CONSTRAINT fk_application_ratings_application FOREIGN KEY (application_id)
+        REFERENCES applications (application_id) ON DELETE CASCADE,
+    CONSTRAINT fk_application_ratings_reviewer FOREIGN KEY (reviewer_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,21 @@
+-- Favorites are kept per recruiter instead of one flag shared by the whole team
+CREATE TABLE IF NOT EXISTS application_favorites (
+    application_id INT NOT NULL,
+    recruiter_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (application_id, recruiter_id),
+    INDEX idx_application_favorites_recruiter (recruiter_id),
+    CONSTRAINT fk_application_favorites_application FOREIGN KEY (application_id)
+        REFERENCES applications (application_id) ON DELETE CASCADE,
+    CONSTRAINT fk_application_favorites_recruiter FOREIGN KEY (recruiter_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+-- Existing favorites belong to the employer who posted the job
+INSERT IGNORE INTO application_favorites (application_id, recruiter_id)
+SELECT a.application_id, j.employer_id
+FROM applications a
+INNER JOIN jobs j ON j.job_id = a.job_id
+WHERE a.favorited = TRUE;
+
+ALTER TABLE applications
+    DROP COLUMN favorited;
@@ -0,0 +1,23 @@
+-- Screening questions asked when applying to a job
+CREATE TABLE IF NOT EXISTS screening_questions (
+    question_id INT AUTO_INCREMENT PRIMARY KEY,
+    job_id INT NOT NULL,
+    position INT NOT NULL,
+    question TEXT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    options JSON NULL,
+    required BOOLEAN NOT NULL DEFAULT TRUE,
+    -- Knockout rule: choices that reject the applicant, or the accepted numeric range
+    knockout_options JSON NULL,
+    min_value DECIMAL(12, 2) NULL,
+    max_value DECIMAL(12, 2) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_screening_questions_job (job_id, position),
+    CONSTRAINT fk_screening_questions_job FOREIGN KEY (job_id) REFERENCES jobs (job_id) ON DELETE CASCADE
+);
+
+-- Answers keep their question text when the question is later replaced
+ALTER TABLE application_answers
+    ADD COLUMN knockout BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD CONSTRAINT fk_application_answers_question FOREIGN KEY (question_id)
+        REFERENCES screening_questions (question_id) ON DELETE SET NULL;
@@ -0,0 +1,34 @@
+-- Interviews arranged for an application; times are stored in UTC
+CREATE TABLE IF NOT EXISTS interviews (
+    interview_id INT AUTO_INCREMENT PRIMARY KEY,
+    application_id INT NOT NULL,
+    -- The employer who organizes the interview; conflicts are checked against their calendar
+    employer_id INT NOT NULL,
+    mode VARCHAR(16) NOT NULL,
+    location VARCHAR(255) NULL,
+    meeting_link VARCHAR(512) NULL,
+    duration_minutes INT NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'proposed',
+    scheduled_at DATETIME NULL,
+    note TEXT NULL,
+    close_reason TEXT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_interviews_application (application_id),
+    INDEX idx_interviews_employer_schedule (employer_id, status, scheduled_at),
+    CONSTRAINT fk_interviews_application FOREIGN KEY (application_id)
+        REFERENCES applications (application_id) ON DELETE CASCADE,
+    CONSTRAINT fk_interviews_employer FOREIGN KEY (employer_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+-- Times proposed for an interview by either party; the other party accepts one of them
+CREATE TABLE IF NOT EXISTS interview_slots (
+    slot_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    interview_id INT NOT NULL,
+    starts_at DATETIME NOT NULL,
+    proposed_by VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_interview_slots_interview (interview_id, starts_at),
+    CONSTRAINT fk_interview_slots_interview FOREIGN KEY (interview_id)
+        REFERENCES interviews (interview_id) ON DELETE CASCADE
+);
@@ -0,0 +1,43 @@
+-- Conversation between the employer's team and the applicant of an application
+CREATE TABLE IF NOT EXISTS application_messages (
+    message_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    application_id INT NOT NULL,
+    sender_id INT NOT NULL,
+    sender_role VARCHAR(16) NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- Set when an admin hides the message from both parties
+    hidden_at TIMESTAMP NULL,
+    hidden_by INT NULL,
+    hidden_reason TEXT NULL,
+    INDEX idx_application_messages_application (application_id, created_at),
+    CONSTRAINT fk_application_messages_application FOREIGN KEY (application_id)
+        REFERENCES applications (application_id) ON DELETE CASCADE,
+    CONSTRAINT fk_application_messages_sender FOREIGN KEY (sender_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+-- Files attached to a message; the content lives in the file storage under storage_key
+CREATE TABLE IF NOT EXISTS message_attachments (
+    attachment_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    message_id BIGINT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_message_attachments_message (message_id),
+    CONSTRAINT fk_message_attachments_message FOREIGN KEY (message_id)
+        REFERENCES application_messages (message_id) ON DELETE CASCADE
+);
+
+-- Read receipts, one per reader of a message
+CREATE TABLE IF NOT EXISTS message_reads (
+    message_id BIGINT NOT NULL,
+    user_id INT NOT NULL,
+    read_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (message_id, user_id),
+    INDEX idx_message_reads_user (user_id),
+    CONSTRAINT fk_message_reads_message FOREIGN KEY (message_id)
+        REFERENCES application_messages (message_id) ON DELETE CASCADE,
+    CONSTRAINT fk_message_reads_user FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+-- Read/unread state of persisted notifications
+ALTER TABLE notifications
+    ADD COLUMN read_at TIMESTAMP NULL,
+    ADD INDEX idx_notifications_user_unread (user_id, read_at);
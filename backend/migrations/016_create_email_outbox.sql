@@ -0,0 +1,27 @@
+-- Transactional outbox of emails, written in the same transaction as the change they announce
+CREATE TABLE IF NOT EXISTS email_outbox (
+    email_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    template VARCHAR(64) NOT NULL,
+    data JSON NULL,
+    -- pending until sent; failed after the last attempt; skipped when the user opted out
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP NULL,
+    INDEX idx_email_outbox_due (status, next_attempt_at),
+    CONSTRAINT fk_email_outbox_user FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+-- Per-user email preferences; users without a row get the defaults
+CREATE TABLE IF NOT EXISTS email_preferences (
+    user_id INT PRIMARY KEY,
+    language VARCHAR(8) NOT NULL DEFAULT 'th',
+    account_emails BOOLEAN NOT NULL DEFAULT TRUE,
+    job_emails BOOLEAN NOT NULL DEFAULT TRUE,
+    application_emails BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_email_preferences_user FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
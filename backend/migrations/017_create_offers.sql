@@ -0,0 +1,22 @@
+-- Offers issued against an application; expires_at and responded_at are stored in UTC
+CREATE TABLE IF NOT EXISTS offers (
+    offer_id INT AUTO_INCREMENT PRIMARY KEY,
+    application_id INT NOT NULL,
+    -- The employer who issued the offer; the candidate's answer is sent to them
+    employer_id INT NOT NULL,
+    salary DECIMAL(12, 2) NOT NULL,
+    start_date DATE NOT NULL,
+    expires_at DATETIME NOT NULL,
+    note TEXT NULL,
+    -- pending, accepted, declined or rescinded; a pending offer past expires_at is reported as expired
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    response_note TEXT NULL,
+    responded_at DATETIME NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_offers_application (application_id, status),
+    INDEX idx_offers_hires (status, responded_at),
+    CONSTRAINT fk_offers_application FOREIGN KEY (application_id)
+        REFERENCES applications (application_id) ON DELETE CASCADE,
+    CONSTRAINT fk_offers_employer FOREIGN KEY (employer_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+-- Headcount of a job: positions to fill and positions filled by accepted offers
+ALTER TABLE jobs
+    ADD COLUMN positions INT NOT NULL DEFAULT 1,
+    ADD COLUMN positions_filled INT NOT NULL DEFAULT 0;
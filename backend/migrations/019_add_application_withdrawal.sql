@@ -0,0 +1,5 @@
+-- Withdrawal details of applications; withdrawn_at is stored in UTC and drives the reapply cooldown
+ALTER TABLE applications
+    ADD COLUMN withdrawn_at DATETIME NULL,
+    ADD COLUMN withdraw_reason TEXT NULL,
+    ADD INDEX idx_applications_withdrawn (freshgradprofile_id, status, withdrawn_at);
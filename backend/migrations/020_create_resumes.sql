@@ -0,0 +1,52 @@
+-- Named resumes of a fresh grad; deleted resumes are kept so applications can still show what was submitted
+CREATE TABLE IF NOT EXISTS resumes (
+    resume_id INT AUTO_INCREMENT PRIMARY KEY,
+    freshgradprofile_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    -- The resume used when an application does not choose one
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_resumes_profile (freshgradprofile_id, deleted_at),
+    CONSTRAINT fk_resumes_profile FOREIGN KEY (freshgradprofile_id)
+        REFERENCES freshgradprofiles (freshgradprofile_id) ON DELETE CASCADE
+);
+
+-- Every file a resume has had; versions are never changed, replacing a resume adds a version.
+-- A version is either an uploaded file (storage_key) or an external link (file_link).
+CREATE TABLE IF NOT EXISTS resume_versions (
+    resume_version_id INT AUTO_INCREMENT PRIMARY KEY,
+    resume_id INT NOT NULL,
+    version INT NOT NULL,
+    file_name VARCHAR(255) NULL,
+    content_type VARCHAR(100) NULL,
+    size_bytes BIGINT NULL,
+    storage_key VARCHAR(64) NULL,
+    file_link VARCHAR(500) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_resume_versions (resume_id, version),
+    CONSTRAINT fk_resume_versions_resume FOREIGN KEY (resume_id) REFERENCES resumes (resume_id) ON DELETE CASCADE
+);
+
+-- What the applicant submitted: the resume version and their profile at apply time
+ALTER TABLE applications
+    ADD COLUMN resume_version_id INT NULL,
+    ADD COLUMN profile_snapshot JSON NULL,
+    ADD CONSTRAINT fk_applications_resume_version FOREIGN KEY (resume_version_id)
+        REFERENCES resume_versions (resume_version_id) ON DELETE SET NULL;
+
+-- Existing resume links become each profile's default resume, and existing applications point at it
+INSERT INTO resumes (freshgradprofile_id, name, is_default)
+SELECT freshgradprofile_id, 'Resume', TRUE FROM freshgradprofiles
+WHERE resume_file_link IS NOT NULL AND resume_file_link <> '';
+
+INSERT INTO resume_versions (resume_id, version, file_link)
+SELECT r.resume_id, 1, f.resume_file_link FROM resumes r
+INNER JOIN freshgradprofiles f ON f.freshgradprofile_id = r.freshgradprofile_id;
+
+UPDATE applications a
+INNER JOIN resumes r ON r.freshgradprofile_id = a.freshgradprofile_id
+INNER JOIN resume_versions rv ON rv.resume_id = r.resume_id
+SET a.resume_version_id = rv.resume_version_id
+WHERE a.resume_version_id IS NULL;
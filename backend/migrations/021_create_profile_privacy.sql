@@ -0,0 +1,37 @@
+-- Who can find a fresh grad's profile outside their applications: nobody (private), employers they applied
+-- to (applied) or every approved employer (searchable)
+ALTER TABLE freshgradprofiles
+    ADD COLUMN visibility VARCHAR(16) NOT NULL DEFAULT 'applied';
+
+-- Companies a fresh grad has blocked from finding or contacting them
+CREATE TABLE IF NOT EXISTS profile_blocked_companies (
+    freshgradprofile_id INT NOT NULL,
+    company_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (freshgradprofile_id, company_id),
+    CONSTRAINT fk_profile_blocked_companies_profile FOREIGN KEY (freshgradprofile_id)
+        REFERENCES freshgradprofiles (freshgradprofile_id) ON DELETE CASCADE,
+    CONSTRAINT fk_profile_blocked_companies_company FOREIGN KEY (company_id)
+        REFERENCES companies (company_id) ON DELETE CASCADE
+);
+
+-- Requests by an employer's team to see a fresh grad's contact details. An accepted request shares them with
+-- the requester's company, or with the requester alone when they have no company.
+CREATE TABLE IF NOT EXISTS contact_requests (
+    contact_request_id INT AUTO_INCREMENT PRIMARY KEY,
+    freshgradprofile_id INT NOT NULL,
+    employer_id INT NOT NULL,
+    company_id INT NULL,
+    job_id INT NULL,
+    message TEXT NULL,
+    -- pending, accepted or declined
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    responded_at TIMESTAMP NULL,
+    INDEX idx_contact_requests_profile (freshgradprofile_id, status),
+    CONSTRAINT fk_contact_requests_profile FOREIGN KEY (freshgradprofile_id)
+        REFERENCES freshgradprofiles (freshgradprofile_id) ON DELETE CASCADE,
+    CONSTRAINT fk_contact_requests_employer FOREIGN KEY (employer_id) REFERENCES users (user_id) ON DELETE CASCADE,
+    CONSTRAINT fk_contact_requests_company FOREIGN KEY (company_id) REFERENCES companies (company_id) ON DELETE CASCADE,
+    CONSTRAINT fk_contact_requests_job FOREIGN KEY (job_id) REFERENCES jobs (job_id) ON DELETE SET NULL
+);
@@ -0,0 +1,22 @@
+-- Link the free-text skills_required of jobs posted before the skills catalogue to the skills it names, so the
+-- skills filters and match scores see them. Entries are split on , ; / | and line breaks, like
+-- services.SplitSkillsText, and matched case-insensitively against skill names, Thai names and aliases.
+-- Entries matching nothing stay in skills_required only; jobs that already have linked skills are left alone.
+INSERT IGNORE INTO job_skills (job_id, skill_id)
+SELECT j.job_id, terms.skill_id
+FROM (
+    SELECT job_id, CONCAT(',',
+        REPLACE(REPLACE(REPLACE(REPLACE(
+        REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(
+            LOWER(TRIM(skills_required)), ';', ','), '/', ','), '|', ','), '\r', ','), '\n', ','), '\t', ' '), '  ', ' '),
+        ' ,', ','), ', ', ','), ' ,', ','), ', ', ','),
+    ',') AS entries
+    FROM jobs
+    WHERE skills_required IS NOT NULL AND TRIM(skills_required) <> ''
+        AND NOT EXISTS (SELECT 1 FROM job_skills js WHERE js.job_id = jobs.job_id)
+) j
+INNER JOIN (
+    SELECT skill_id, LOWER(name) AS term FROM skills
+    UNION SELECT skill_id, LOWER(name_th) FROM skills WHERE name_th <> ''
+    UNION SELECT a.skill_id, LOWER(a.alias) FROM skill_aliases a
+) terms ON LOCATE(CONCAT(',', terms.term, ','), j.entries) > 0;
@@ -0,0 +1,6 @@
+-- Pending offers of withdrawn applications are rescinded, not declined; fix those recorded as declined so they
+-- stop counting against the acceptance rate
+UPDATE offers o
+INNER JOIN applications a ON a.application_id = o.application_id
+SET o.status = 'rescinded'
+WHERE o.status = 'declined' AND o.response_note = 'Application withdrawn' AND a.status = 'withdrawn';
@@ -0,0 +1,14 @@
+-- job_status held free text before the open/closed/filled enum. SQL filters matched "Open" or "OPEN" through the
+-- case-insensitive collation while the Go checks did not, so legacy values are mapped onto the enum: open and
+-- filled keep their meaning whatever their case, and anything else is closed. The column then only accepts the enum.
+UPDATE jobs
+SET job_status = CASE LOWER(TRIM(job_status))
+        WHEN 'open' THEN 'open'
+        WHEN 'filled' THEN 'filled'
+        ELSE 'closed'
+    END,
+    updated_at = updated_at
+WHERE job_status IS NULL OR BINARY job_status NOT IN ('open', 'closed', 'filled');
+
+ALTER TABLE jobs
+    MODIFY COLUMN job_status ENUM('open', 'closed', 'filled') NOT NULL DEFAULT 'open';
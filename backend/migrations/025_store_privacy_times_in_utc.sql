@@ -0,0 +1,15 @@
+-- Times of contact requests and blocked companies are stored in UTC as DATETIME, like withdrawn_at, and are
+-- written with UTC_TIMESTAMP(). responded_at already held UTC_TIMESTAMP() values, while created_at defaulted to
+-- CURRENT_TIMESTAMP, which reads back in the session time zone, so existing created_at values are converted.
+ALTER TABLE contact_requests
+    MODIFY COLUMN created_at DATETIME NOT NULL,
+    MODIFY COLUMN responded_at DATETIME NULL;
+
+UPDATE contact_requests
+SET created_at = CONVERT_TZ(created_at, @@session.time_zone, '+00:00');
+
+ALTER TABLE profile_blocked_companies
+    MODIFY COLUMN created_at DATETIME NOT NULL;
+
+UPDATE profile_blocked_companies
+SET created_at = CONVERT_TZ(created_at, @@session.time_zone, '+00:00');
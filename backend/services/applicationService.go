@@ -0,0 +1,406 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ApplicationStatus is the stage of an application in the hiring pipeline
+type ApplicationStatus string
+
+const (
+	ApplicationStatusSubmitted    ApplicationStatus = "submitted"
+	ApplicationStatusReviewing    ApplicationStatus = "reviewing"
+	ApplicationStatusShortlisted  ApplicationStatus = "shortlisted"
+	ApplicationStatusInterviewing ApplicationStatus = "interviewing"
+	ApplicationStatusOffered      ApplicationStatus = "offered"
+	ApplicationStatusHired        ApplicationStatus = "hired"
+	ApplicationStatusRejected     ApplicationStatus = "rejected"
+	ApplicationStatusWithdrawn    ApplicationStatus = "withdrawn"
+)
+
+// ApplicationStatuses lists every ApplicationStatus in pipeline order
+var ApplicationStatuses = []ApplicationStatus{
+	ApplicationStatusSubmitted, ApplicationStatusReviewing, ApplicationStatusShortlisted, ApplicationStatusInterviewing,
+	ApplicationStatusOffered, ApplicationStatusHired, ApplicationStatusRejected, ApplicationStatusWithdrawn,
+}
+
+// ParseApplicationStatus matches value case-insensitively against ApplicationStatuses
+func ParseApplicationStatus(value string) (ApplicationStatus, bool) {
+	return parseEnum(value, ApplicationStatuses)
+}
+
+// AverageRatingExpr is the mean reviewer rating of application a, NULL when unrated
+const AverageRatingExpr = "(SELECT AVG(r.rating) FROM application_ratings r WHERE r.application_id = a.application_id)"
+
+// FavoritedExpr reports whether application a is a favorite of the recruiter bound to its placeholder
+const FavoritedExpr = "EXISTS (SELECT 1 FROM application_favorites af WHERE af.application_id = a.application_id AND af.recruiter_id = ?)"
+
+// ApplicationTagsExpr lists the tags of application a separated by newlines, NULL when untagged
+const ApplicationTagsExpr = "(SELECT GROUP_CONCAT(t.tag ORDER BY t.tag SEPARATOR '\\n') FROM application_tags t " +
+	"WHERE t.application_id = a.application_id)"
+
+// NormalizeTag trims a tag and collapses inner whitespace
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(tag), " ")
+}
+
+// SplitTags splits an ApplicationTagsExpr value
+func SplitTags(value *string) []string {
+	if value == nil || *value == "" {
+		return []string{}
+	}
+	return strings.Split(*value, "\n")
+}
+
+// submittedProfileExpr reads a field of the profile submitted with application a from its profile_snapshot,
+// falling back to the live profile f for applications from before snapshots were kept. A JSON null stays NULL;
+// cast, when set, is the SQL type the JSON value is converted to.
+func submittedProfileExpr(field, cast string) string {
+	value := "JSON_EXTRACT(a.profile_snapshot, '$." + field + "')"
+	converted := "JSON_UNQUOTE(" + value + ")"
+	if cast != "" {
+		converted = "CAST(" + converted + " AS " + cast + ")"
+	}
+	return "(CASE WHEN a.profile_snapshot IS NULL THEN f." + field +
+		" WHEN JSON_TYPE(" + value + ") = 'NULL' THEN NULL ELSE " + converted + " END)"
+}
+
+// Profile fields of applications a as the employer received them, for listing, filtering and sorting
+var (
+	SubmittedUniversityExpr     = submittedProfileExpr("university", "")
+	SubmittedGraduationYearExpr = submittedProfileExpr("graduation_year", "SIGNED")
+	SubmittedGPAExpr            = submittedProfileExpr("gpa", "DECIMAL(4,2)")
+	SubmittedLocationExpr       = submittedProfileExpr("location", "")
+)
+
+// applicantSortColumns maps the sort parameter to the column it orders by
+var applicantSortColumns = map[string]string{
+	"applied_at":      "a.applied_at",
+	"gpa":             SubmittedGPAExpr,
+	"graduation_year": SubmittedGraduationYearExpr,
+	"university":      SubmittedUniversityExpr,
+	"status":          "a.status",
+	"rating":          AverageRatingExpr,
+}
+
+// ApplicantSearch is a parsed applicant filter, as SQL conditions over applications a and freshgradprofiles f
+type ApplicantSearch struct {
+	Conditions []string
+	Args       []interface{}
+	OrderBy    string
+}
+
+// ParseApplicantSearch builds an ApplicantSearch from query parameters:
+// skills, skills_match, graduation_year, graduation_year_min, graduation_year_max, university, gpa_min, gpa_max,
+// location, status (comma-separated), favorited (by recruiterID), applied_after, applied_before, tags (comma-separated, any),
+// min_rating (average of reviewers), has_notes, include_withdrawn and sort (a column, "-" for descending).
+// Withdrawn applications are left out unless include_withdrawn is true or status asks for them. Profile filters
+// and sorting use the profile submitted with each application, like the employer sees it.
+func ParseApplicantSearch(q Querier, params url.Values, recruiterID interface{}) (ApplicantSearch, FieldErrors, error) {
+	search := ApplicantSearch{OrderBy: "a.applied_at DESC"}
+	errs := FieldErrors{}
+
+	add := func(condition string, args ...interface{}) {
+		search.Conditions = append(search.Conditions, condition)
+		search.Args = append(search.Args, args...)
+	}
+	integer := func(field, condition string) {
+		if value := params.Get(field); value != "" {
+			if number, err := strconv.Atoi(value); err != nil {
+				errs[field] = "must be a whole number"
+			} else {
+				add(condition, number)
+			}
+		}
+	}
+	decimal := func(field, condition string) {
+		if value := params.Get(field); value != "" {
+			if number, err := strconv.ParseFloat(value, 64); err != nil {
+				errs[field] = "must be a number"
+			} else {
+				add(condition, number)
+			}
+		}
+	}
+	date := func(field, condition string) {
+		if value := params.Get(field); value != "" {
+			if _, err := ParseDeadline(value); err != nil {
+				errs[field] = "must be a date in YYYY-MM-DD format"
+			} else {
+				add(condition, value)
+			}
+		}
+	}
+
+	integer("graduation_year", SubmittedGraduationYearExpr+" = ?")
+	integer("graduation_year_min", SubmittedGraduationYearExpr+" >= ?")
+	integer("graduation_year_max", SubmittedGraduationYearExpr+" <= ?")
+	decimal("gpa_min", SubmittedGPAExpr+" >= ?")
+	decimal("gpa_max", SubmittedGPAExpr+" <= ?")
+	decimal("min_rating", AverageRatingExpr+" >= ?")
+	date("applied_after", "a.applied_at >= ?")
+	date("applied_before", "a.applied_at < DATE_ADD(?, INTERVAL 1 DAY)")
+
+	if university := strings.TrimSpace(params.Get("university")); university != "" {
+		add(SubmittedUniversityExpr+" LIKE ?", "%"+university+"%")
+	}
+	if location := strings.TrimSpace(params.Get("location")); location != "" {
+		add(SubmittedLocationExpr+" LIKE ?", "%"+location+"%")
+	}
+
+	if tagParam := params.Get("tags"); tagParam != "" {
+		var tags []interface{}
+		for _, tag := range strings.Split(tagParam, ",") {
+			if tag = NormalizeTag(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		if len(tags) > 0 {
+			add("EXISTS (SELECT 1 FROM application_tags tf WHERE tf.application_id = a.application_id AND tf.tag IN (?"+
+				strings.Repeat(", ?", len(tags)-1)+"))", tags...)
+		}
+	}
+
+	if hasNotes := params.Get("has_notes"); hasNotes != "" {
+		if value, err := strconv.ParseBool(hasNotes); err != nil {
+			errs["has_notes"] = "must be true or false"
+		} else {
+			notes := "EXISTS (SELECT 1 FROM application_notes n WHERE n.application_id = a.application_id)"
+			if !value {
+				notes = "NOT " + notes
+			}
+			add(notes)
+		}
+	}
+
+	if favorited := params.Get("favorited"); favorited != "" {
+		if value, err := strconv.ParseBool(favorited); err != nil {
+			errs["favorited"] = "must be true or false"
+		} else {
+			if value {
+				add(FavoritedExpr, recruiterID)
+			} else {
+				add("NOT "+FavoritedExpr, recruiterID)
+			}
+		}
+	}
+
+	if statusParam := params.Get("status"); statusParam != "" {
+		var statuses []interface{}
+		for _, value := range strings.Split(statusParam, ",") {
+			status, ok := ParseApplicationStatus(value)
+			if !ok {
+				errs["status"] = enumMessage(ApplicationStatuses)
+				break
+			}
+			statuses = append(statuses, string(status))
+		}
+		if len(statuses) > 0 && errs["status"] == "" {
+			add("a.status IN (?"+strings.Repeat(", ?", len(statuses)-1)+")", statuses...)
+		}
+	}
+
+	includeWithdrawn := strings.Contains(params.Get("status"), string(ApplicationStatusWithdrawn))
+	if value := params.Get("include_withdrawn"); value != "" {
+		if include, err := strconv.ParseBool(value); err != nil {
+			errs["include_withdrawn"] = "must be true or false"
+		} else {
+			includeWithdrawn = includeWithdrawn || include
+		}
+	}
+	if !includeWithdrawn {
+		add("a.status <> ?", string(ApplicationStatusWithdrawn))
+	}
+
+	if sortParam := params.Get("sort"); sortParam != "" {
+		direction := "ASC"
+		if strings.HasPrefix(sortParam, "-") {
+			direction = "DESC"
+			sortParam = sortParam[1:]
+		}
+		column, ok := applicantSortColumns[sortParam]
+		if !ok {
+			errs["sort"] = "must be one of: applied_at, gpa, graduation_year, university, status, rating (prefix - for descending)"
+		} else {
+			// Applicants without the sorted value go last either way
+			search.OrderBy = column + " IS NULL, " + column + " " + direction + ", a.application_id"
+		}
+	}
+
+	if len(errs) > 0 {
+		return search, errs, nil
+	}
+
+	skillCondition, skillArgs, err := submittedSkillFilter(q, params.Get("skills"), params.Get("skills_match"))
+	if err != nil {
+		return search, nil, err
+	}
+	if skillCondition != "" {
+		add(skillCondition, skillArgs...)
+	}
+	return search, nil, nil
+}
+
+// submittedSkillFilter is SkillFilter over the skills submitted with applications a: those in the profile
+// snapshot, and the live profile skills for applications from before snapshots were kept
+func submittedSkillFilter(q Querier, param, match string) (string, []interface{}, error) {
+	skillIDs, matchAll, condition, err := resolveSkillFilter(q, param, match)
+	if err != nil || condition != "" || skillIDs == nil {
+		return condition, nil, err
+	}
+
+	live, args := SkillFilterCondition("freshgradprofile_skills", "freshgradprofile_id", "a.freshgradprofile_id", skillIDs, matchAll)
+	submitted := make([]string, len(skillIDs))
+	for i, skillID := range skillIDs {
+		submitted[i] = "JSON_CONTAINS(JSON_EXTRACT(a.profile_snapshot, '$.skills[*].skill_id'), ?)"
+		args = append(args, strconv.Itoa(skillID))
+	}
+	join := " OR "
+	if matchAll {
+		join = " AND "
+	}
+	return "(CASE WHEN a.profile_snapshot IS NULL THEN " + live + " ELSE COALESCE(" + strings.Join(submitted, join) + ", FALSE) END)", args, nil
+}
+
+// Where returns the search conditions joined for a WHERE clause, prefixed with " AND ", or ""
+func (search ApplicantSearch) Where() string {
+	if len(search.Conditions) == 0 {
+		return ""
+	}
+	return " AND " + strings.Join(search.Conditions, " AND ")
+}
+
+// Errors returned by ChangeApplicationStatus
+var (
+	ErrApplicationNotFound     = errors.New("application not found")
+	ErrApplicationStatusSame   = errors.New("application already has this status")
+	ErrApplicationStatusLocked = errors.New("application status can no longer change")
+	ErrApplicationWithdrawOnly = errors.New("only the applicant can withdraw an application")
+	ErrApplicationOfferOnly    = errors.New("offered and hired are set through offers: make an offer with " +
+		"POST /employer/jobs/:job-id/applications/:application-id/offers; the application is hired when the candidate accepts it")
+)
+
+// CheckEmployerStatusTarget rejects the statuses employers cannot set directly. Offered and hired are only
+// reached through CreateOffer and AcceptOffer, which keep the job's filled positions and hire analytics right.
+func CheckEmployerStatusTarget(status ApplicationStatus) error {
+	if status == ApplicationStatusOffered || status == ApplicationStatusHired {
+		return ErrApplicationOfferOnly
+	}
+	return nil
+}
+
+// contactStatuses are the statuses at which employers see an applicant's contact details
+var contactStatuses = []ApplicationStatus{
+	ApplicationStatusShortlisted, ApplicationStatusInterviewing, ApplicationStatusOffered, ApplicationStatusHired,
+}
+
+// ContactVisible reports whether an employer may see the applicant's contact details at status
+func ContactVisible(status ApplicationStatus) bool {
+	for _, candidate := range contactStatuses {
+		if status == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// ContactStatusCondition limits the application status column to the statuses passing ContactVisible
+func ContactStatusCondition(column string) (string, []interface{}) {
+	args := make([]interface{}, len(contactStatuses))
+	for i, status := range contactStatuses {
+		args[i] = string(status)
+	}
+	return column + " IN (?" + strings.Repeat(", ?", len(contactStatuses)-1) + ")", args
+}
+
+// ApplicationStatusChange is the result of ChangeApplicationStatus
+type ApplicationStatusChange struct {
+	From            ApplicationStatus
+	To              ApplicationStatus
+	JobID           int
+	JobTitle        string
+	FreshGradUserID int
+}
+
+// ChangeApplicationStatus moves an application to status inside tx, locking the row, recording the change in
+// application_status_history and notifying the applicant. Withdrawn and hired applications no longer change,
+// and only the applicant may withdraw. changedBy is the employer user making the change.
+func ChangeApplicationStatus(tx *sql.Tx, applicationID interface{}, status ApplicationStatus, changedBy interface{}, note string) (ApplicationStatusChange, error) {
+	change := ApplicationStatusChange{To: status}
+	query := "SELECT a.status, a.job_id, j.title, f.user_id FROM applications a " +
+		"INNER JOIN jobs j ON j.job_id = a.job_id " +
+		"INNER JOIN freshgradprofiles f ON f.freshgradprofile_id = a.freshgradprofile_id " +
+		"WHERE a.application_id = ? FOR UPDATE"
+	err := tx.QueryRow(query, applicationID).Scan(&change.From, &change.JobID, &change.JobTitle, &change.FreshGradUserID)
+	if err == sql.ErrNoRows {
+		return change, ErrApplicationNotFound
+	}
+	if err != nil {
+		return change, fmt.Errorf("error loading application: %v", err)
+	}
+
+	switch {
+	case status == ApplicationStatusWithdrawn:
+		return change, ErrApplicationWithdrawOnly
+	case change.From == status:
+		return change, ErrApplicationStatusSame
+	case change.From == ApplicationStatusWithdrawn || change.From == ApplicationStatusHired:
+		return change, ErrApplicationStatusLocked
+	}
+
+	if _, err := tx.Exec("UPDATE applications SET status = ? WHERE application_id = ?", string(status), applicationID); err != nil {
+		return change, fmt.Errorf("error updating application status: %v", err)
+	}
+	if err := RecordStatusHistory(tx, applicationID, change.From, status, changedBy, note); err != nil {
+		return change, err
+	}
+
+	message := fmt.Sprintf("Your application for \"%s\" is now %s", change.JobTitle, status)
+	data := map[string]interface{}{"application_id": applicationID, "job_id": change.JobID, "status": status}
+	if err := CreateNotification(tx, change.FreshGradUserID, "application.status", message, data); err != nil {
+		return change, err
+	}
+	return change, nil
+}
+
+// RecordStatusHistory appends a status change to application_status_history; an empty from is the first status
+func RecordStatusHistory(exec Execer, applicationID interface{}, from, to ApplicationStatus, changedBy interface{}, note string) error {
+	var fromValue, noteValue interface{}
+	if from != "" {
+		fromValue = string(from)
+	}
+	if note != "" {
+		noteValue = note
+	}
+	query := "INSERT INTO application_status_history (application_id, from_status, to_status, changed_by, note) VALUES (?, ?, ?, ?, ?)"
+	if _, err := exec.Exec(query, applicationID, fromValue, string(to), changedBy, noteValue); err != nil {
+		return fmt.Errorf("error recording status history: %v", err)
+	}
+	return nil
+}
+
+// SetApplicationFavorite marks or unmarks an application as a favorite of one recruiter; repeating either is a no-op
+func SetApplicationFavorite(exec Execer, applicationID, recruiterID interface{}, favorite bool) error {
+	query := "INSERT IGNORE INTO application_favorites (application_id, recruiter_id) VALUES (?, ?)"
+	if !favorite {
+		query = "DELETE FROM application_favorites WHERE application_id = ? AND recruiter_id = ?"
+	}
+	if _, err := exec.Exec(query, applicationID, recruiterID); err != nil {
+		return fmt.Errorf("error updating favorite: %v", err)
+	}
+	return nil
+}
+
+// AddApplicationTag tags an application; tagging twice with the same tag is a no-op
+func AddApplicationTag(exec Execer, applicationID interface{}, tag string, createdBy interface{}) error {
+	query := "INSERT IGNORE INTO application_tags (application_id, tag, created_by) VALUES (?, ?, ?)"
+	if _, err := exec.Exec(query, applicationID, tag, createdBy); err != nil {
+		return fmt.Errorf("error tagging application: %v", err)
+	}
+	return nil
+}
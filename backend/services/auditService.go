@@ -0,0 +1,158 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Snapshot queries used for the before/after state of audited rows
+const (
+	UserSnapshotQuery    = "SELECT user_id, email, role, approved, suspended, created_at FROM users WHERE user_id = ?"
+	JobSnapshotQuery     = "SELECT * FROM jobs WHERE job_id = ?"
+	CompanySnapshotQuery = "SELECT * FROM companies WHERE company_id = ?"
+	MemberSnapshotQuery  = "SELECT company_id, user_id, role, created_at FROM company_members WHERE user_id = ?"
+	InviteSnapshotQuery  = "SELECT invitation_id, company_id, email, role, invited_by, expires_at, accepted_at, revoked_at, created_at " +
+		"FROM company_invitations WHERE invitation_id = ?"
+	ApplicationSnapshotQuery = "SELECT application_id, job_id, freshgradprofile_id, status, applied_at, withdrawn_at, withdraw_reason " +
+		"FROM applications WHERE application_id = ?"
+	MessageSnapshotQuery = "SELECT message_id, application_id, sender_id, sender_role, hidden_at, hidden_by, hidden_reason " +
+		"FROM application_messages WHERE message_id = ?"
+)
+
+// AuditEntry holds a single change written to the audit_logs table
+type AuditEntry struct {
+	ActorID    int
+	ActorRole  string
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     map[string]interface{}
+	After      map[string]interface{}
+	RequestID  string
+	IP         string
+}
+
+// NewAuditEntry creates an audit entry for the current request with actor, request ID and IP filled in
+func NewAuditEntry(c *gin.Context, actorID interface{}, actorRole, action, targetType string, targetID interface{}) AuditEntry {
+	id, _ := actorID.(int)
+	return AuditEntry{
+		ActorID:    id,
+		ActorRole:  actorRole,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   fmt.Sprint(targetID),
+		RequestID:  RequestID(c),
+		IP:         c.ClientIP(),
+	}
+}
+
+// RecordAudit writes the entry inside tx so it is committed or rolled back together with the change
+func RecordAudit(tx *sql.Tx, entry AuditEntry) error {
+	before, err := marshalSnapshot(entry.Before)
+	if err != nil {
+		return fmt.Errorf("error encoding before snapshot: %v", err)
+	}
+	after, err := marshalSnapshot(entry.After)
+	if err != nil {
+		return fmt.Errorf("error encoding after snapshot: %v", err)
+	}
+
+	query := `INSERT INTO audit_logs (
+		actor_id, actor_role, action, target_type, target_id, before_data, after_data, request_id, ip_address
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := tx.Exec(query,
+		entry.ActorID, entry.ActorRole, entry.Action, entry.TargetType, entry.TargetID,
+		before, after, entry.RequestID, entry.IP,
+	); err != nil {
+		return fmt.Errorf("error writing audit log: %v", err)
+	}
+	return nil
+}
+
+// ExecWithAudit runs changeQuery and records the entry with before/after snapshots of the target in one transaction
+func ExecWithAudit(db *sql.DB, entry AuditEntry, snapshotQuery, changeQuery string, args ...interface{}) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := ExecWithAuditTx(tx, entry, snapshotQuery, changeQuery, args...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", err)
+	}
+	return nil
+}
+
+// ExecWithAuditTx is ExecWithAudit inside a transaction owned by the caller, for changes spanning several statements
+func ExecWithAuditTx(tx *sql.Tx, entry AuditEntry, snapshotQuery, changeQuery string, args ...interface{}) error {
+	var err error
+	if entry.Before, err = SnapshotRow(tx, snapshotQuery, entry.TargetID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(changeQuery, args...); err != nil {
+		return fmt.Errorf("error executing change: %v", err)
+	}
+
+	if entry.After, err = SnapshotRow(tx, snapshotQuery, entry.TargetID); err != nil {
+		return err
+	}
+
+	return RecordAudit(tx, entry)
+}
+
+// SnapshotRow reads a single row as a column -> value map, returning nil if the row does not exist
+func SnapshotRow(tx *sql.Tx, query string, args ...interface{}) (map[string]interface{}, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying snapshot: %v", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot columns: %v", err)
+	}
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	values := make([]sql.RawBytes, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, fmt.Errorf("error scanning snapshot: %v", err)
+	}
+
+	snapshot := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		if values[i] == nil {
+			snapshot[column] = nil
+		} else {
+			snapshot[column] = string(values[i])
+		}
+	}
+	return snapshot, rows.Err()
+}
+
+// marshalSnapshot encodes a snapshot as JSON, keeping NULL for missing rows
+func marshalSnapshot(snapshot map[string]interface{}) (interface{}, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,540 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Email outbox: changes that warrant an email insert an email_outbox row in their own transaction, and a
+// background worker renders and sends the pending rows over SMTP, retrying failures with exponential backoff.
+// Like the job expiry scheduler, only the replica holding the scheduler_locks lease sends.
+
+const emailOutboxLockName = "email_outbox"
+
+// Email templates, each with a Thai and an English version in templates/email
+const (
+	EmailTemplateUserApproved   = "user_approved"
+	EmailTemplateJobApproved    = "job_approved"
+	EmailTemplateApplicationNew = "application_new"
+)
+
+// EmailLanguage is the language emails are rendered in
+type EmailLanguage string
+
+const (
+	EmailLanguageThai    EmailLanguage = "th"
+	EmailLanguageEnglish EmailLanguage = "en"
+)
+
+// EmailLanguages lists the supported email languages
+var EmailLanguages = []EmailLanguage{EmailLanguageThai, EmailLanguageEnglish}
+
+// Outbox statuses
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+	EmailStatusSkipped = "skipped"
+)
+
+// emailCategories maps each template to the email_preferences column that lets users opt out of it
+var emailCategories = map[string]string{
+	EmailTemplateUserApproved:   "account_emails",
+	EmailTemplateJobApproved:    "job_emails",
+	EmailTemplateApplicationNew: "application_emails",
+}
+
+// emailBatchSize caps the emails sent per worker run
+const emailBatchSize = 50
+
+// Retry backoff doubles from emailRetryBase up to emailRetryMax
+const (
+	emailRetryBase = time.Minute
+	emailRetryMax  = 6 * time.Hour
+)
+
+//go:embed templates/email/*.tmpl
+var emailTemplateFiles embed.FS
+
+// emailTemplate is one language version of a template: the subject and text body are plain text, the HTML
+// body is escaped
+type emailTemplate struct {
+	text *template.Template
+	html *htmltemplate.Template
+}
+
+// emailTemplates holds every template by "<name>.<language>"
+var emailTemplates = loadEmailTemplates()
+
+// loadEmailTemplates parses the embedded templates; each file defines "subject", "text" and "html"
+func loadEmailTemplates() map[string]emailTemplate {
+	templates := map[string]emailTemplate{}
+	for name := range emailCategories {
+		for _, language := range EmailLanguages {
+			file := fmt.Sprintf("templates/email/%s.%s.tmpl", name, language)
+			templates[name+"."+string(language)] = emailTemplate{
+				text: template.Must(template.ParseFS(emailTemplateFiles, file)),
+				html: htmltemplate.Must(htmltemplate.ParseFS(emailTemplateFiles, file)),
+			}
+		}
+	}
+	return templates
+}
+
+// EnqueueEmail adds an email for a user to the outbox, inside a transaction when exec is a *sql.Tx.
+// Preferences are applied when the email is sent, so the latest choice of the user wins.
+func EnqueueEmail(exec Execer, userID int, name string, data map[string]interface{}) error {
+	if _, ok := emailCategories[name]; !ok {
+		return fmt.Errorf("unknown email template %q", name)
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("error encoding email data: %v", err)
+	}
+
+	query := "INSERT INTO email_outbox (user_id, template, data) VALUES (?, ?, ?)"
+	if _, err := exec.Exec(query, userID, name, string(payload)); err != nil {
+		return fmt.Errorf("error queueing email: %v", err)
+	}
+	return nil
+}
+
+// RenderedEmail is an email ready to be sent
+type RenderedEmail struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// RenderEmail renders a template in a language with the outbox data; APP_URL is available as .AppURL
+func RenderEmail(name string, language EmailLanguage, data map[string]interface{}) (RenderedEmail, error) {
+	tmpl, ok := emailTemplates[name+"."+string(language)]
+	if !ok {
+		return RenderedEmail{}, fmt.Errorf("unknown email template %q in %q", name, language)
+	}
+	values := map[string]interface{}{"AppURL": os.Getenv("APP_URL")}
+	for key, value := range data {
+		values[key] = value
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", values); err != nil {
+		return RenderedEmail{}, fmt.Errorf("error rendering subject: %v", err)
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "text", values); err != nil {
+		return RenderedEmail{}, fmt.Errorf("error rendering text body: %v", err)
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "html", values); err != nil {
+		return RenderedEmail{}, fmt.Errorf("error rendering HTML body: %v", err)
+	}
+	return RenderedEmail{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\r\n",
+		HTML:    strings.TrimSpace(html.String()) + "\r\n",
+	}, nil
+}
+
+// EmailPreferences are a user's email settings
+type EmailPreferences struct {
+	Language          EmailLanguage `json:"language"`
+	AccountEmails     bool          `json:"account_emails"`
+	JobEmails         bool          `json:"job_emails"`
+	ApplicationEmails bool          `json:"application_emails"`
+}
+
+// DefaultEmailPreferences apply to users who never saved preferences
+var DefaultEmailPreferences = EmailPreferences{
+	Language:          EmailLanguageThai,
+	AccountEmails:     true,
+	JobEmails:         true,
+	ApplicationEmails: true,
+}
+
+// EmailPreferencesInput is a preferences update; nil fields keep their current value
+type EmailPreferencesInput struct {
+	Language          *string `json:"language"`
+	AccountEmails     *bool   `json:"account_emails"`
+	JobEmails         *bool   `json:"job_emails"`
+	ApplicationEmails *bool   `json:"application_emails"`
+}
+
+// Apply validates the input and applies it to preferences
+func (input EmailPreferencesInput) Apply(preferences *EmailPreferences) FieldErrors {
+	errs := FieldErrors{}
+	if input.Language != nil {
+		if language, ok := parseEnum(*input.Language, EmailLanguages); ok {
+			preferences.Language = language
+		} else {
+			errs["language"] = enumMessage(EmailLanguages)
+		}
+	}
+	if input.AccountEmails != nil {
+		preferences.AccountEmails = *input.AccountEmails
+	}
+	if input.JobEmails != nil {
+		preferences.JobEmails = *input.JobEmails
+	}
+	if input.ApplicationEmails != nil {
+		preferences.ApplicationEmails = *input.ApplicationEmails
+	}
+	return errs
+}
+
+// LoadEmailPreferences reads a user's email preferences, falling back to the defaults
+func LoadEmailPreferences(q Querier, userID interface{}) (EmailPreferences, error) {
+	preferences := DefaultEmailPreferences
+	query := "SELECT language, account_emails, job_emails, application_emails FROM email_preferences WHERE user_id = ?"
+	err := q.QueryRow(query, userID).Scan(&preferences.Language, &preferences.AccountEmails,
+		&preferences.JobEmails, &preferences.ApplicationEmails)
+	if err != nil && err != sql.ErrNoRows {
+		return EmailPreferences{}, fmt.Errorf("error querying email preferences: %v", err)
+	}
+	return preferences, nil
+}
+
+// SaveEmailPreferences stores a user's email preferences
+func SaveEmailPreferences(exec Execer, userID interface{}, preferences EmailPreferences) error {
+	query := `INSERT INTO email_preferences (user_id, language, account_emails, job_emails, application_emails)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE language = VALUES(language), account_emails = VALUES(account_emails),
+			job_emails = VALUES(job_emails), application_emails = VALUES(application_emails)`
+	if _, err := exec.Exec(query, userID, preferences.Language, preferences.AccountEmails,
+		preferences.JobEmails, preferences.ApplicationEmails); err != nil {
+		return fmt.Errorf("error saving email preferences: %v", err)
+	}
+	return nil
+}
+
+// EmailConfig configures the SMTP server and the outbox worker
+type EmailConfig struct {
+	Host        string
+	Port        int
+	Username    string
+	Password    string
+	From        string
+	Interval    time.Duration
+	MaxAttempts int
+}
+
+// Enabled reports whether an SMTP server is configured
+func (config EmailConfig) Enabled() bool {
+	return config.Host != ""
+}
+
+// LoadEmailConfig reads SMTP_HOST, SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD, SMTP_FROM,
+// EMAIL_INTERVAL (default 30s) and EMAIL_MAX_ATTEMPTS (default 8)
+func LoadEmailConfig() EmailConfig {
+	config := EmailConfig{
+		Host:        os.Getenv("SMTP_HOST"),
+		Port:        587,
+		Username:    os.Getenv("SMTP_USERNAME"),
+		Password:    os.Getenv("SMTP_PASSWORD"),
+		From:        os.Getenv("SMTP_FROM"),
+		Interval:    30 * time.Second,
+		MaxAttempts: 8,
+	}
+
+	if value := os.Getenv("SMTP_PORT"); value != "" {
+		if port, err := strconv.Atoi(value); err == nil && port > 0 && port < 65536 {
+			config.Port = port
+		} else {
+			log.Printf("Warning: invalid SMTP_PORT %q, using %d", value, config.Port)
+		}
+	}
+
+	if value := os.Getenv("EMAIL_INTERVAL"); value != "" {
+		if interval, err := time.ParseDuration(value); err == nil && interval > 0 {
+			config.Interval = interval
+		} else {
+			log.Printf("Warning: invalid EMAIL_INTERVAL %q, using %v", value, config.Interval)
+		}
+	}
+
+	if value := os.Getenv("EMAIL_MAX_ATTEMPTS"); value != "" {
+		if attempts, err := strconv.Atoi(value); err == nil && attempts > 0 {
+			config.MaxAttempts = attempts
+		} else {
+			log.Printf("Warning: invalid EMAIL_MAX_ATTEMPTS %q, using %d", value, config.MaxAttempts)
+		}
+	}
+
+	if config.From == "" {
+		config.From = config.Username
+	}
+	return config
+}
+
+// StartEmailWorker sends due outbox emails every config.Interval until ctx is cancelled
+func StartEmailWorker(ctx context.Context, config EmailConfig) {
+	holder := schedulerHolderID()
+	log.Printf("Email worker started (holder %s, server %s:%d, interval %v)", holder, config.Host, config.Port, config.Interval)
+
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	for {
+		runEmailWorker(holder, config)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Email worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// outboxEmail is a due email_outbox row with its recipient
+type outboxEmail struct {
+	id       int64
+	userID   int
+	template string
+	data     sql.NullString
+	attempts int
+	to       string
+}
+
+// runEmailWorker sends one batch of due emails if this replica holds the leader lock
+func runEmailWorker(holder string, config EmailConfig) {
+	db, err := ConnectDB()
+	if err != nil {
+		log.Printf("Email worker: database connection error: %v", err)
+		return
+	}
+	defer db.Close()
+
+	leader, err := AcquireSchedulerLock(db, emailOutboxLockName, holder, 2*config.Interval)
+	if err != nil {
+		log.Printf("Email worker: error acquiring scheduler lock: %v", err)
+		return
+	}
+	if !leader {
+		return
+	}
+
+	query := "SELECT o.email_id, o.user_id, o.template, o.data, o.attempts, u.email FROM email_outbox o " +
+		"INNER JOIN users u ON u.user_id = o.user_id " +
+		"WHERE o.status = ? AND o.next_attempt_at <= NOW() ORDER BY o.next_attempt_at, o.email_id LIMIT ?"
+	rows, err := db.Query(query, EmailStatusPending, emailBatchSize)
+	if err != nil {
+		log.Printf("Email worker: error querying outbox: %v", err)
+		return
+	}
+	var emails []outboxEmail
+	for rows.Next() {
+		var email outboxEmail
+		if err := rows.Scan(&email.id, &email.userID, &email.template, &email.data, &email.attempts, &email.to); err != nil {
+			rows.Close()
+			log.Printf("Email worker: error scanning outbox: %v", err)
+			return
+		}
+		emails = append(emails, email)
+	}
+	rows.Close()
+
+	// Stop before the lease runs out so another replica never sends the same email
+	deadline := time.Now().Add(config.Interval)
+	for _, email := range emails {
+		if time.Now().After(deadline) {
+			return
+		}
+		if err := deliverEmail(db, config, email); err != nil {
+			log.Printf("Email worker: error updating email %d: %v", email.id, err)
+		}
+	}
+}
+
+// deliverEmail sends one outbox email, honouring the recipient's preferences, and records the outcome
+func deliverEmail(db *sql.DB, config EmailConfig, email outboxEmail) error {
+	preferences, err := LoadEmailPreferences(db, email.userID)
+	if err != nil {
+		return err
+	}
+	if !preferences.allows(email.template) {
+		_, err := db.Exec("UPDATE email_outbox SET status = ? WHERE email_id = ?", EmailStatusSkipped, email.id)
+		return err
+	}
+
+	data := map[string]interface{}{}
+	if email.data.Valid {
+		if err := json.Unmarshal([]byte(email.data.String), &data); err != nil {
+			return failEmail(db, config, email, fmt.Errorf("error decoding email data: %v", err))
+		}
+	}
+	rendered, err := RenderEmail(email.template, preferences.Language, data)
+	if err == nil {
+		err = sendSMTP(config, email.to, rendered)
+	}
+	if err != nil {
+		return failEmail(db, config, email, err)
+	}
+
+	if _, err := db.Exec("UPDATE email_outbox SET status = ?, attempts = attempts + 1, last_error = NULL, sent_at = NOW() WHERE email_id = ?",
+		EmailStatusSent, email.id); err != nil {
+		return err
+	}
+	log.Printf("Email worker: sent %s email %d to user %d", email.template, email.id, email.userID)
+	return nil
+}
+
+// failEmail records a failed attempt, scheduling a retry with exponential backoff until the last attempt
+func failEmail(db *sql.DB, config EmailConfig, email outboxEmail, sendErr error) error {
+	attempts := email.attempts + 1
+	status := EmailStatusPending
+	if attempts >= config.MaxAttempts {
+		status = EmailStatusFailed
+	}
+	backoff := emailRetryBackoff(attempts)
+
+	log.Printf("Email worker: attempt %d of email %d failed (%s): %v", attempts, email.id, status, sendErr)
+	query := "UPDATE email_outbox SET status = ?, attempts = ?, last_error = ?, next_attempt_at = NOW() + INTERVAL ? SECOND WHERE email_id = ?"
+	_, err := db.Exec(query, status, attempts, sendErr.Error(), int(backoff.Seconds()), email.id)
+	return err
+}
+
+// emailRetryBackoff is the wait before retrying an email that has failed attempts times
+func emailRetryBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return emailRetryBase
+	}
+	if attempts <= 20 && emailRetryBase<<(attempts-1) < emailRetryMax {
+		return emailRetryBase << (attempts - 1)
+	}
+	return emailRetryMax
+}
+
+// allows reports whether the preferences accept emails of a template
+func (preferences EmailPreferences) allows(name string) bool {
+	switch emailCategories[name] {
+	case "account_emails":
+		return preferences.AccountEmails
+	case "job_emails":
+		return preferences.JobEmails
+	case "application_emails":
+		return preferences.ApplicationEmails
+	}
+	return false
+}
+
+// smtpTimeout bounds one SMTP conversation so a stuck server cannot stall the worker
+const smtpTimeout = 30 * time.Second
+
+// sendSMTP delivers a multipart/alternative email, upgrading to TLS when the server offers STARTTLS
+func sendSMTP(config EmailConfig, to string, email RenderedEmail) error {
+	message, err := buildMIMEMessage(config.From, to, email)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(config.Host, strconv.Itoa(config.Port)), smtpTimeout)
+	if err != nil {
+		return fmt.Errorf("error connecting to SMTP server: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error starting SMTP session: %v", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: config.Host}); err != nil {
+			return fmt.Errorf("error starting TLS: %v", err)
+		}
+	}
+	if config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", config.Username, config.Password, config.Host)); err != nil {
+			return fmt.Errorf("error authenticating: %v", err)
+		}
+	}
+	if err := client.Mail(config.From); err != nil {
+		return fmt.Errorf("error setting sender: %v", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("error setting recipient: %v", err)
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error starting message: %v", err)
+	}
+	if _, err := writer.Write(message); err != nil {
+		writer.Close()
+		return fmt.Errorf("error writing message: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("error sending message: %v", err)
+	}
+	return client.Quit()
+}
+
+// buildMIMEMessage encodes an email with quoted-printable text and HTML alternatives
+func buildMIMEMessage(from, to string, email RenderedEmail) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", email.Text},
+		{"text/html; charset=UTF-8", email.HTML},
+	} {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.contentType)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		writer, err := parts.CreatePart(header)
+		if err != nil {
+			return nil, fmt.Errorf("error building message: %v", err)
+		}
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("error building message: %v", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("error building message: %v", err)
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("error building message: %v", err)
+	}
+
+	var message bytes.Buffer
+	headers := [][2]string{
+		{"From", from},
+		{"To", to},
+		{"Subject", mime.QEncoding.Encode("UTF-8", email.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", newRequestID(), emailDomain(from))},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + parts.Boundary()},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&message, "%s: %s\r\n", header[0], header[1])
+	}
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
+
+// emailDomain is the domain of an address, used for Message-ID
+func emailDomain(address string) string {
+	if at := strings.LastIndex(address, "@"); at >= 0 {
+		return strings.Trim(address[at+1:], "> ")
+	}
+	return "localhost"
+}
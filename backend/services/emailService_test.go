@@ -0,0 +1,181 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderEmail(t *testing.T) {
+	t.Setenv("APP_URL", "https://jobs.example.com")
+	data := map[string]interface{}{"JobTitle": `Dev <Go> & "SQL"`}
+
+	tests := []struct {
+		language EmailLanguage
+		subject  string
+		text     string
+		html     string
+	}{
+		{
+			language: EmailLanguageEnglish,
+			subject:  `Your job "Dev <Go> & "SQL"" is approved`,
+			text:     "View your jobs: https://jobs.example.com",
+			html:     `<strong>Dev &lt;Go&gt; &amp; &#34;SQL&#34;</strong> has been approved`,
+		},
+		{
+			language: EmailLanguageThai,
+			subject:  `ประกาศงาน "Dev <Go> & "SQL"" ได้รับการอนุมัติแล้ว`,
+			text:     "ดูประกาศงานของคุณ: https://jobs.example.com",
+			html:     `ประกาศงาน <strong>Dev &lt;Go&gt; &amp; &#34;SQL&#34;</strong> ของคุณ`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.language), func(t *testing.T) {
+			email, err := RenderEmail(EmailTemplateJobApproved, test.language, data)
+			if err != nil {
+				t.Fatalf("RenderEmail() error = %v", err)
+			}
+			if email.Subject != test.subject {
+				t.Errorf("RenderEmail() subject = %q, want %q", email.Subject, test.subject)
+			}
+			if !strings.Contains(email.Text, test.text) || !strings.HasSuffix(email.Text, "Fresh Grad Jobs\r\n") {
+				t.Errorf("RenderEmail() text = %q, want it to contain %q", email.Text, test.text)
+			}
+			if !strings.Contains(email.HTML, test.html) || !strings.Contains(email.HTML, `<a href="https://jobs.example.com">`) {
+				t.Errorf("RenderEmail() HTML = %q, want it to contain %q and the link", email.HTML, test.html)
+			}
+		})
+	}
+
+	t.Run("without APP_URL", func(t *testing.T) {
+		t.Setenv("APP_URL", "")
+		email, err := RenderEmail(EmailTemplateJobApproved, EmailLanguageEnglish, data)
+		if err != nil {
+			t.Fatalf("RenderEmail() error = %v", err)
+		}
+		if strings.Contains(email.Text, "View your jobs") || strings.Contains(email.HTML, "<a ") {
+			t.Errorf("RenderEmail() = %+v, want no link", email)
+		}
+	})
+
+	t.Run("every template", func(t *testing.T) {
+		for name := range emailCategories {
+			for _, language := range EmailLanguages {
+				email, err := RenderEmail(name, language, data)
+				if err != nil || email.Subject == "" || strings.TrimSpace(email.Text) == "" || strings.TrimSpace(email.HTML) == "" {
+					t.Errorf("RenderEmail(%s, %s) = %+v, %v, want every part", name, language, email, err)
+				}
+			}
+		}
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		if _, err := RenderEmail("password_reset", EmailLanguageEnglish, data); err == nil {
+			t.Error("RenderEmail() error = nil, want an error for an unknown template")
+		}
+		if _, err := RenderEmail(EmailTemplateJobApproved, "fr", data); err == nil {
+			t.Error("RenderEmail() error = nil, want an error for an unknown language")
+		}
+	})
+}
+
+func TestEmailRetryBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Minute},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{9, 256 * time.Minute},
+		{10, 6 * time.Hour},
+		{21, 6 * time.Hour},
+		{64, 6 * time.Hour},
+	}
+
+	for _, test := range tests {
+		if got := emailRetryBackoff(test.attempts); got != test.want {
+			t.Errorf("emailRetryBackoff(%d) = %v, want %v", test.attempts, got, test.want)
+		}
+	}
+	for attempts := 1; attempts <= 100; attempts++ {
+		if got := emailRetryBackoff(attempts); got < emailRetryBase || got > emailRetryMax {
+			t.Errorf("emailRetryBackoff(%d) = %v, want between %v and %v", attempts, got, emailRetryBase, emailRetryMax)
+		}
+	}
+}
+
+func TestBuildMIMEMessage(t *testing.T) {
+	email := RenderedEmail{
+		Subject: `ประกาศงาน "Backend" ได้รับการอนุมัติแล้ว`,
+		Text:    "สวัสดีค่ะ\r\n\r\n" + strings.Repeat("ประกาศงานของคุณได้รับการอนุมัติแล้ว ", 4) + "= 100%\r\n",
+		HTML:    "<p>สวัสดีค่ะ</p>\r\n<p>Café &amp; ครัว</p>\r\n",
+	}
+	message, err := buildMIMEMessage("Fresh Grad Jobs <noreply@jobs.example.com>", "somchai@example.com", email)
+	if err != nil {
+		t.Fatalf("buildMIMEMessage() error = %v", err)
+	}
+
+	// The encoded subject and quoted-printable parts keep the message 7-bit, with body lines of at most 76
+	// characters
+	header, body, _ := strings.Cut(string(message), "\r\n\r\n")
+	for _, b := range []byte(header + body) {
+		if b > 127 {
+			t.Fatalf("buildMIMEMessage() = %q, want 7-bit text", message)
+		}
+	}
+	for i, line := range strings.Split(body, "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("body line %d has %d characters: %q", i, len(line), line)
+		}
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(message))
+	if err != nil {
+		t.Fatalf("mail.ReadMessage() error = %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != email.Subject {
+		t.Errorf("Subject = %q, %v, want %q", subject, err, email.Subject)
+	}
+	if !strings.HasSuffix(parsed.Header.Get("Message-ID"), "@jobs.example.com>") {
+		t.Errorf("Message-ID = %q, want the sender's domain", parsed.Header.Get("Message-ID"))
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, %v, want multipart/alternative", parsed.Header.Get("Content-Type"), err)
+	}
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	for _, want := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", email.Text},
+		{"text/html; charset=UTF-8", email.HTML},
+	} {
+		// NextRawPart keeps the Content-Transfer-Encoding header and the encoded body
+		part, err := parts.NextRawPart()
+		if err != nil {
+			t.Fatalf("NextRawPart() error = %v", err)
+		}
+		if part.Header.Get("Content-Type") != want.contentType || part.Header.Get("Content-Transfer-Encoding") != "quoted-printable" {
+			t.Errorf("part headers = %v, want %s in quoted-printable", part.Header, want.contentType)
+		}
+		content, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatalf("decoding %s: %v", want.contentType, err)
+		}
+		if string(content) != want.content {
+			t.Errorf("%s = %q, want %q", want.contentType, content, want.content)
+		}
+	}
+	if _, err := parts.NextRawPart(); err != io.EOF {
+		t.Errorf("NextRawPart() error = %v, want io.EOF after two parts", err)
+	}
+}
@@ -0,0 +1,689 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InterviewMode is where an interview takes place
+type InterviewMode string
+
+const (
+	InterviewModeOnsite InterviewMode = "onsite"
+	InterviewModeOnline InterviewMode = "online"
+)
+
+// InterviewModes lists every valid InterviewMode
+var InterviewModes = []InterviewMode{InterviewModeOnsite, InterviewModeOnline}
+
+// InterviewStatus is the stage of arranging an interview
+type InterviewStatus string
+
+const (
+	// InterviewStatusProposed waits for the candidate to pick one of the employer's slots
+	InterviewStatusProposed InterviewStatus = "proposed"
+	// InterviewStatusCounterProposed waits for the employer to pick one of the candidate's slots
+	InterviewStatusCounterProposed InterviewStatus = "counter_proposed"
+	InterviewStatusScheduled       InterviewStatus = "scheduled"
+	InterviewStatusDeclined        InterviewStatus = "declined"
+	InterviewStatusCancelled       InterviewStatus = "cancelled"
+)
+
+// InterviewStatuses lists every valid InterviewStatus
+var InterviewStatuses = []InterviewStatus{
+	InterviewStatusProposed, InterviewStatusCounterProposed, InterviewStatusScheduled, InterviewStatusDeclined, InterviewStatusCancelled,
+}
+
+// ParseInterviewStatus matches value case-insensitively against InterviewStatuses
+func ParseInterviewStatus(value string) (InterviewStatus, bool) {
+	return parseEnum(value, InterviewStatuses)
+}
+
+// InterviewParty is the side of an interview taking an action
+type InterviewParty string
+
+const (
+	InterviewPartyEmployer  InterviewParty = "employer"
+	InterviewPartyCandidate InterviewParty = "candidate"
+)
+
+// awaitingStatus is the status in which the interview waits for party to answer
+func (party InterviewParty) awaitingStatus() InterviewStatus {
+	if party == InterviewPartyCandidate {
+		return InterviewStatusProposed
+	}
+	return InterviewStatusCounterProposed
+}
+
+// proposedStatus is the status after party proposes slots
+func (party InterviewParty) proposedStatus() InterviewStatus {
+	if party == InterviewPartyCandidate {
+		return InterviewStatusCounterProposed
+	}
+	return InterviewStatusProposed
+}
+
+// InterviewTimeLayout is how interview times are stored, in UTC
+const InterviewTimeLayout = "2006-01-02 15:04:05"
+
+// Limits on interview proposals
+const (
+	MinInterviewMinutes   = 15
+	MaxInterviewMinutes   = 480
+	MaxInterviewSlots     = 10
+	interviewTimeExample  = "2025-01-31T10:00:00+07:00"
+	interviewCalendarHost = "fresh-grad-jobs"
+)
+
+// InterviewSlot is a start time proposed by one party
+type InterviewSlot struct {
+	ID         int            `json:"slot_id"`
+	StartsAt   string         `json:"starts_at"`
+	ProposedBy InterviewParty `json:"proposed_by"`
+	// Conflicts lists the organizer's scheduled interviews overlapping the slot; only filled for employers
+	Conflicts []int `json:"conflicts,omitempty"`
+}
+
+// Interview is an interview with its proposed slots. Times are RFC 3339 in UTC.
+type Interview struct {
+	ID              int             `json:"interview_id"`
+	ApplicationID   int             `json:"application_id"`
+	JobID           int             `json:"job_id"`
+	JobTitle        string          `json:"job_title"`
+	EmployerID      int             `json:"employer_id"`
+	CandidateUserID int             `json:"candidate_user_id"`
+	Mode            InterviewMode   `json:"mode"`
+	Location        *string         `json:"location"`
+	MeetingLink     *string         `json:"meeting_link"`
+	DurationMinutes int             `json:"duration_minutes"`
+	Status          InterviewStatus `json:"status"`
+	ScheduledAt     *string         `json:"scheduled_at"`
+	Note            *string         `json:"note"`
+	CloseReason     *string         `json:"close_reason"`
+	CreatedAt       string          `json:"created_at"`
+	UpdatedAt       string          `json:"updated_at"`
+	Slots           []InterviewSlot `json:"slots"`
+}
+
+// InterviewInput is an interview proposal as sent by an employer
+type InterviewInput struct {
+	Mode            string   `json:"mode"`
+	Location        string   `json:"location"`
+	MeetingLink     string   `json:"meeting_link"`
+	DurationMinutes int      `json:"duration_minutes"`
+	Slots           []string `json:"slots"`
+	Note            string   `json:"note"`
+}
+
+// Errors returned by the interview state changes
+var (
+	ErrInterviewNotFound    = errors.New("interview not found")
+	ErrInterviewClosed      = errors.New("interview has been declined or cancelled")
+	ErrInterviewNotAwaiting = errors.New("interview is not waiting for your response")
+	ErrInterviewSlotInvalid = errors.New("slot is not one of the other party's proposals")
+	ErrInterviewSlotPast    = errors.New("slot has already started")
+	ErrInterviewInactive    = errors.New("application is no longer active")
+)
+
+// InterviewConflictError reports proposed times overlapping the organizer's scheduled interviews
+type InterviewConflictError struct {
+	// Conflicts maps each conflicting start time (RFC 3339) to the interviews it overlaps
+	Conflicts map[string][]int
+}
+
+func (err *InterviewConflictError) Error() string {
+	return "interview time conflicts with another scheduled interview"
+}
+
+// InterviewChange is the result of an interview state change, with what is needed to notify the other party
+type InterviewChange struct {
+	InterviewID     int
+	ApplicationID   int
+	JobTitle        string
+	EmployerID      int
+	CandidateUserID int
+	Status          InterviewStatus
+	ScheduledAt     *time.Time
+	// ApplicationStatus is the status of the application before the change
+	ApplicationStatus ApplicationStatus
+}
+
+// Validate normalizes an interview proposal, returning its slot times and field errors
+func (input *InterviewInput) Validate(now time.Time) ([]time.Time, FieldErrors) {
+	errs := FieldErrors{}
+
+	mode, ok := parseEnum(input.Mode, InterviewModes)
+	if !ok {
+		errs["mode"] = enumMessage(InterviewModes)
+	}
+	input.Mode = string(mode)
+	input.Location = strings.TrimSpace(input.Location)
+	input.MeetingLink = strings.TrimSpace(input.MeetingLink)
+	input.Note = strings.TrimSpace(input.Note)
+
+	switch mode {
+	case InterviewModeOnsite:
+		if input.Location == "" {
+			errs["location"] = "is required for onsite interviews"
+		}
+	case InterviewModeOnline:
+		if input.MeetingLink == "" {
+			errs["meeting_link"] = "is required for online interviews"
+		}
+	}
+	if len(input.Location) > 255 {
+		errs["location"] = "must be at most 255 characters"
+	}
+	if input.MeetingLink != "" {
+		if link, err := url.Parse(input.MeetingLink); err != nil || (link.Scheme != "https" && link.Scheme != "http") || link.Host == "" {
+			errs["meeting_link"] = "must be an http or https URL"
+		} else if len(input.MeetingLink) > 512 {
+			errs["meeting_link"] = "must be at most 512 characters"
+		}
+	}
+	if input.DurationMinutes < MinInterviewMinutes || input.DurationMinutes > MaxInterviewMinutes {
+		errs["duration_minutes"] = fmt.Sprintf("must be between %d and %d", MinInterviewMinutes, MaxInterviewMinutes)
+	}
+
+	slots, slotErrors := ParseInterviewSlots(input.Slots, now)
+	for field, message := range slotErrors {
+		errs[field] = message
+	}
+	return slots, errs
+}
+
+// ParseInterviewSlots parses proposed RFC 3339 start times, which must be distinct and in the future
+func ParseInterviewSlots(values []string, now time.Time) ([]time.Time, FieldErrors) {
+	errs := FieldErrors{}
+	if len(values) == 0 || len(values) > MaxInterviewSlots {
+		errs["slots"] = fmt.Sprintf("must list 1 to %d start times", MaxInterviewSlots)
+		return nil, errs
+	}
+
+	seen := make(map[time.Time]bool, len(values))
+	slots := make([]time.Time, 0, len(values))
+	for i, value := range values {
+		field := fmt.Sprintf("slots[%d]", i)
+		start, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+		if err != nil {
+			errs[field] = "must be an RFC 3339 time such as " + interviewTimeExample
+			continue
+		}
+		// Stored at second precision
+		start = start.UTC().Truncate(time.Second)
+		switch {
+		case !start.After(now):
+			errs[field] = "must be in the future"
+		case seen[start]:
+			errs[field] = "is listed twice"
+		default:
+			seen[start] = true
+			slots = append(slots, start)
+		}
+	}
+	return slots, errs
+}
+
+// FindInterviewConflicts lists the organizer's scheduled interviews, other than excludeID, overlapping
+// durationMinutes from start
+func FindInterviewConflicts(q Querier, employerID interface{}, excludeID int, start time.Time, durationMinutes int) ([]int, error) {
+	return findInterviewConflicts(q, false, employerID, excludeID, start, durationMinutes)
+}
+
+// findInterviewConflicts is FindInterviewConflicts, locking the interviews found when lock is set so that it
+// reads interviews scheduled by transactions committed since tx began
+func findInterviewConflicts(q Querier, lock bool, employerID interface{}, excludeID int, start time.Time, durationMinutes int) ([]int, error) {
+	end := start.Add(time.Duration(durationMinutes) * time.Minute)
+	query := "SELECT interview_id FROM interviews WHERE employer_id = ? AND status = ? AND interview_id <> ? " +
+		"AND scheduled_at < ? AND DATE_ADD(scheduled_at, INTERVAL duration_minutes MINUTE) > ? ORDER BY scheduled_at"
+	if lock {
+		query += " FOR UPDATE"
+	}
+	rows, err := q.Query(query, employerID, string(InterviewStatusScheduled), excludeID,
+		end.Format(InterviewTimeLayout), start.UTC().Format(InterviewTimeLayout))
+	if err != nil {
+		return nil, fmt.Errorf("error checking interview conflicts: %v", err)
+	}
+	defer rows.Close()
+
+	var conflicts []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning interview conflict: %v", err)
+		}
+		conflicts = append(conflicts, id)
+	}
+	return conflicts, rows.Err()
+}
+
+// checkSlotConflicts returns an InterviewConflictError when any of slots overlaps the organizer's scheduled
+// interviews, locking them when lock is set
+func checkSlotConflicts(q Querier, lock bool, employerID interface{}, excludeID int, slots []time.Time, durationMinutes int) error {
+	conflicts := map[string][]int{}
+	for _, start := range slots {
+		ids, err := findInterviewConflicts(q, lock, employerID, excludeID, start, durationMinutes)
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			conflicts[start.Format(time.RFC3339)] = ids
+		}
+	}
+	if len(conflicts) > 0 {
+		return &InterviewConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// CreateInterview stores an employer's interview proposal for an application inside tx, failing with an
+// InterviewConflictError when a slot overlaps the employer's scheduled interviews and ErrInterviewInactive
+// when the application is rejected, withdrawn or hired
+func CreateInterview(tx *sql.Tx, applicationID, employerID interface{}, input InterviewInput, slots []time.Time) (int64, error) {
+	var applicationStatus ApplicationStatus
+	err := tx.QueryRow("SELECT status FROM applications WHERE application_id = ? FOR UPDATE", applicationID).Scan(&applicationStatus)
+	if err == sql.ErrNoRows {
+		return 0, ErrApplicationNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error loading application: %v", err)
+	}
+	if !interviewActive(applicationStatus) {
+		return 0, ErrInterviewInactive
+	}
+	if err := checkSlotConflicts(tx, false, employerID, 0, slots, input.DurationMinutes); err != nil {
+		return 0, err
+	}
+
+	query := "INSERT INTO interviews (application_id, employer_id, mode, location, meeting_link, duration_minutes, status, note) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	result, err := tx.Exec(query, applicationID, employerID, input.Mode, nullString(input.Location),
+		nullString(input.MeetingLink), input.DurationMinutes, string(InterviewStatusProposed), nullString(input.Note))
+	if err != nil {
+		return 0, fmt.Errorf("error creating interview: %v", err)
+	}
+	interviewID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error reading interview ID: %v", err)
+	}
+	if err := insertInterviewSlots(tx, interviewID, InterviewPartyEmployer, slots); err != nil {
+		return 0, err
+	}
+	return interviewID, nil
+}
+
+// insertInterviewSlots stores slots proposed by party
+func insertInterviewSlots(exec Execer, interviewID interface{}, party InterviewParty, slots []time.Time) error {
+	for _, start := range slots {
+		query := "INSERT INTO interview_slots (interview_id, starts_at, proposed_by) VALUES (?, ?, ?)"
+		if _, err := exec.Exec(query, interviewID, start.UTC().Format(InterviewTimeLayout), string(party)); err != nil {
+			return fmt.Errorf("error adding interview slot: %v", err)
+		}
+	}
+	return nil
+}
+
+// lockInterview loads the interview inside tx for update
+func lockInterview(tx *sql.Tx, interviewID interface{}) (InterviewChange, int, error) {
+	var change InterviewChange
+	var durationMinutes int
+	query := "SELECT i.interview_id, i.application_id, j.title, i.employer_id, f.user_id, i.status, i.duration_minutes, a.status " +
+		"FROM interviews i " +
+		"INNER JOIN applications a ON a.application_id = i.application_id " +
+		"INNER JOIN jobs j ON j.job_id = a.job_id " +
+		"INNER JOIN freshgradprofiles f ON f.freshgradprofile_id = a.freshgradprofile_id " +
+		"WHERE i.interview_id = ? FOR UPDATE"
+	err := tx.QueryRow(query, interviewID).Scan(&change.InterviewID, &change.ApplicationID, &change.JobTitle,
+		&change.EmployerID, &change.CandidateUserID, &change.Status, &durationMinutes, &change.ApplicationStatus)
+	if err == sql.ErrNoRows {
+		return change, 0, ErrInterviewNotFound
+	}
+	if err != nil {
+		return change, 0, fmt.Errorf("error loading interview: %v", err)
+	}
+	if change.Status == InterviewStatusDeclined || change.Status == InterviewStatusCancelled {
+		return change, 0, ErrInterviewClosed
+	}
+	return change, durationMinutes, nil
+}
+
+// interviewActive reports whether interviews for an application at status can still be arranged
+func interviewActive(status ApplicationStatus) bool {
+	switch status {
+	case ApplicationStatusRejected, ApplicationStatusWithdrawn, ApplicationStatusHired:
+		return false
+	}
+	return true
+}
+
+// AcceptInterviewSlot lets party accept one of the other party's slots inside tx, scheduling the interview.
+// The organizer's calendar is checked for conflicts, and an application not yet interviewing moves to
+// interviewing; actorID is the employer accepting, nil for the candidate.
+func AcceptInterviewSlot(tx *sql.Tx, interviewID, slotID interface{}, party InterviewParty, actorID interface{}, now time.Time) (InterviewChange, error) {
+	// Lock the organizer before the interview so concurrent accepts for them wait here, rather than each
+	// holding its interview while the locking conflict check below waits for the other's
+	var organizerID int
+	organizerQuery := "SELECT user_id FROM users WHERE user_id = (SELECT employer_id FROM interviews WHERE interview_id = ?) FOR UPDATE"
+	err := tx.QueryRow(organizerQuery, interviewID).Scan(&organizerID)
+	if err == sql.ErrNoRows {
+		return InterviewChange{}, ErrInterviewNotFound
+	}
+	if err != nil {
+		return InterviewChange{}, fmt.Errorf("error locking interview organizer: %v", err)
+	}
+
+	change, durationMinutes, err := lockInterview(tx, interviewID)
+	if err != nil {
+		return change, err
+	}
+	if !interviewActive(change.ApplicationStatus) {
+		return change, ErrInterviewInactive
+	}
+	if change.Status != party.awaitingStatus() {
+		return change, ErrInterviewNotAwaiting
+	}
+
+	var startsAt string
+	var proposedBy InterviewParty
+	query := "SELECT starts_at, proposed_by FROM interview_slots WHERE slot_id = ? AND interview_id = ?"
+	err = tx.QueryRow(query, slotID, change.InterviewID).Scan(&startsAt, &proposedBy)
+	if err == sql.ErrNoRows || (err == nil && proposedBy == party) {
+		return change, ErrInterviewSlotInvalid
+	}
+	if err != nil {
+		return change, fmt.Errorf("error loading interview slot: %v", err)
+	}
+	start, err := time.ParseInLocation(InterviewTimeLayout, startsAt, time.UTC)
+	if err != nil {
+		return change, fmt.Errorf("error parsing interview slot: %v", err)
+	}
+	if !start.After(now) {
+		return change, ErrInterviewSlotPast
+	}
+
+	if err := checkSlotConflicts(tx, true, change.EmployerID, change.InterviewID, []time.Time{start}, durationMinutes); err != nil {
+		return change, err
+	}
+
+	if _, err := tx.Exec("UPDATE interviews SET status = ?, scheduled_at = ? WHERE interview_id = ?",
+		string(InterviewStatusScheduled), startsAt, change.InterviewID); err != nil {
+		return change, fmt.Errorf("error scheduling interview: %v", err)
+	}
+	change.Status, change.ScheduledAt = InterviewStatusScheduled, &start
+
+	switch change.ApplicationStatus {
+	case ApplicationStatusSubmitted, ApplicationStatusReviewing, ApplicationStatusShortlisted:
+		_, err = ChangeApplicationStatus(tx, change.ApplicationID, ApplicationStatusInterviewing, actorID, "Interview scheduled")
+	}
+	return change, err
+}
+
+// ProposeInterviewSlots lets party propose new times inside tx, which also reschedules a scheduled interview.
+// Slots proposed by the employer must not conflict with the organizer's scheduled interviews.
+func ProposeInterviewSlots(tx *sql.Tx, interviewID interface{}, party InterviewParty, slots []time.Time, note string) (InterviewChange, error) {
+	change, durationMinutes, err := lockInterview(tx, interviewID)
+	if err != nil {
+		return change, err
+	}
+	if !interviewActive(change.ApplicationStatus) {
+		return change, ErrInterviewInactive
+	}
+	if party == InterviewPartyEmployer {
+		if err := checkSlotConflicts(tx, false, change.EmployerID, change.InterviewID, slots, durationMinutes); err != nil {
+			return change, err
+		}
+	}
+
+	if err := insertInterviewSlots(tx, change.InterviewID, party, slots); err != nil {
+		return change, err
+	}
+	change.Status = party.proposedStatus()
+	query := "UPDATE interviews SET status = ?, scheduled_at = NULL, note = COALESCE(?, note) WHERE interview_id = ?"
+	if _, err := tx.Exec(query, string(change.Status), nullString(note), change.InterviewID); err != nil {
+		return change, fmt.Errorf("error updating interview: %v", err)
+	}
+	return change, nil
+}
+
+// CloseInterview declines (candidate) or cancels (employer) an interview inside tx
+func CloseInterview(tx *sql.Tx, interviewID interface{}, party InterviewParty, reason string) (InterviewChange, error) {
+	change, _, err := lockInterview(tx, interviewID)
+	if err != nil {
+		return change, err
+	}
+
+	change.Status = InterviewStatusCancelled
+	if party == InterviewPartyCandidate {
+		change.Status = InterviewStatusDeclined
+	}
+	query := "UPDATE interviews SET status = ?, close_reason = ? WHERE interview_id = ?"
+	if _, err := tx.Exec(query, string(change.Status), nullString(reason), change.InterviewID); err != nil {
+		return change, fmt.Errorf("error closing interview: %v", err)
+	}
+	return change, nil
+}
+
+// interviewColumns selects an Interview from interviews i joined to applications a, jobs j and freshgradprofiles f
+const interviewColumns = "i.interview_id, i.application_id, a.job_id, j.title, i.employer_id, f.user_id, i.mode, i.location, " +
+	"i.meeting_link, i.duration_minutes, i.status, i.scheduled_at, i.note, i.close_reason, i.created_at, i.updated_at"
+
+// InterviewFrom is the FROM clause matching interviewColumns, for conditions passed to LoadInterviews
+const InterviewFrom = "FROM interviews i " +
+	"INNER JOIN applications a ON a.application_id = i.application_id " +
+	"INNER JOIN jobs j ON j.job_id = a.job_id " +
+	"INNER JOIN freshgradprofiles f ON f.freshgradprofile_id = a.freshgradprofile_id "
+
+// LoadInterviews reads the interviews matching condition with their slots, soonest first
+func LoadInterviews(q Querier, condition string, args ...interface{}) ([]Interview, error) {
+	query := "SELECT " + interviewColumns + " " + InterviewFrom + "WHERE " + condition +
+		" ORDER BY i.scheduled_at IS NULL, i.scheduled_at, i.created_at DESC"
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying interviews: %v", err)
+	}
+	defer rows.Close()
+
+	interviews := []Interview{}
+	index := map[int]int{}
+	for rows.Next() {
+		var interview Interview
+		if err := rows.Scan(&interview.ID, &interview.ApplicationID, &interview.JobID, &interview.JobTitle,
+			&interview.EmployerID, &interview.CandidateUserID, &interview.Mode, &interview.Location,
+			&interview.MeetingLink, &interview.DurationMinutes, &interview.Status, &interview.ScheduledAt,
+			&interview.Note, &interview.CloseReason, &interview.CreatedAt, &interview.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning interview: %v", err)
+		}
+		if interview.ScheduledAt != nil {
+			scheduledAt := formatInterviewTime(*interview.ScheduledAt)
+			interview.ScheduledAt = &scheduledAt
+		}
+		interview.Slots = []InterviewSlot{}
+		index[interview.ID] = len(interviews)
+		interviews = append(interviews, interview)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(interviews) == 0 {
+		return interviews, nil
+	}
+
+	ids := make([]interface{}, 0, len(interviews))
+	for _, interview := range interviews {
+		ids = append(ids, interview.ID)
+	}
+	slotQuery := "SELECT slot_id, interview_id, starts_at, proposed_by FROM interview_slots WHERE interview_id IN (?" +
+		strings.Repeat(", ?", len(ids)-1) + ") ORDER BY starts_at, slot_id"
+	slotRows, err := q.Query(slotQuery, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying interview slots: %v", err)
+	}
+	defer slotRows.Close()
+
+	for slotRows.Next() {
+		var slot InterviewSlot
+		var interviewID int
+		if err := slotRows.Scan(&slot.ID, &interviewID, &slot.StartsAt, &slot.ProposedBy); err != nil {
+			return nil, fmt.Errorf("error scanning interview slot: %v", err)
+		}
+		slot.StartsAt = formatInterviewTime(slot.StartsAt)
+		interviews[index[interviewID]].Slots = append(interviews[index[interviewID]].Slots, slot)
+	}
+	return interviews, slotRows.Err()
+}
+
+// MarkSlotConflicts fills the Conflicts of slots the employer still has to answer
+func MarkSlotConflicts(q Querier, interviews []Interview) error {
+	for i := range interviews {
+		interview := &interviews[i]
+		if interview.Status != InterviewStatusCounterProposed {
+			continue
+		}
+		for j := range interview.Slots {
+			slot := &interview.Slots[j]
+			start, err := time.Parse(time.RFC3339, slot.StartsAt)
+			if slot.ProposedBy != InterviewPartyCandidate || err != nil {
+				continue
+			}
+			if slot.Conflicts, err = FindInterviewConflicts(q, interview.EmployerID, interview.ID, start, interview.DurationMinutes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// formatInterviewTime converts a stored UTC time to RFC 3339
+func formatInterviewTime(value string) string {
+	if t, err := time.ParseInLocation(InterviewTimeLayout, value, time.UTC); err == nil {
+		return t.Format(time.RFC3339)
+	}
+	return value
+}
+
+// InterviewICS renders a scheduled interview as an iCalendar (RFC 5545) file
+func InterviewICS(interview Interview, now time.Time) (string, error) {
+	if interview.Status != InterviewStatusScheduled || interview.ScheduledAt == nil {
+		return "", errors.New("interview is not scheduled")
+	}
+	start, err := time.Parse(time.RFC3339, *interview.ScheduledAt)
+	if err != nil {
+		return "", fmt.Errorf("error parsing interview time: %v", err)
+	}
+	end := start.Add(time.Duration(interview.DurationMinutes) * time.Minute)
+
+	var description []string
+	if interview.MeetingLink != nil {
+		description = append(description, "Meeting link: "+*interview.MeetingLink)
+	}
+	if interview.Note != nil {
+		description = append(description, *interview.Note)
+	}
+	location := ""
+	if interview.Location != nil {
+		location = *interview.Location
+	} else if interview.MeetingLink != nil {
+		location = *interview.MeetingLink
+	}
+
+	const stamp = "20060102T150405Z"
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//" + interviewCalendarHost + "//Interviews//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"BEGIN:VEVENT",
+		"UID:interview-" + strconv.Itoa(interview.ID) + "@" + interviewCalendarHost,
+		"DTSTAMP:" + now.UTC().Format(stamp),
+		"DTSTART:" + start.UTC().Format(stamp),
+		"DTEND:" + end.UTC().Format(stamp),
+		"SUMMARY:" + escapeICSText("Interview: "+interview.JobTitle),
+	}
+	if location != "" {
+		lines = append(lines, "LOCATION:"+escapeICSText(location))
+	}
+	if interview.MeetingLink != nil {
+		lines = append(lines, "URL:"+*interview.MeetingLink)
+	}
+	if len(description) > 0 {
+		lines = append(lines, "DESCRIPTION:"+escapeICSText(strings.Join(description, "\n")))
+	}
+	lines = append(lines, "STATUS:CONFIRMED", "END:VEVENT", "END:VCALENDAR")
+
+	var calendar strings.Builder
+	for _, line := range lines {
+		calendar.WriteString(foldICSLine(line))
+		calendar.WriteString("\r\n")
+	}
+	return calendar.String(), nil
+}
+
+// WriteInterviewICS responds with the iCalendar file of a scheduled interview, or 409 when it is not scheduled
+func WriteInterviewICS(c *gin.Context, interview Interview) {
+	calendar, err := InterviewICS(interview, time.Now())
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": "Interview is not scheduled"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"interview-%d.ics\"", interview.ID))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar))
+}
+
+// RespondInterviewError writes the response for a rejected interview change and reports whether err was one
+func RespondInterviewError(c *gin.Context, err error) bool {
+	var conflict *InterviewConflictError
+	switch {
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error(), "conflicts": conflict.Conflicts})
+	case errors.Is(err, ErrInterviewNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Interview not found"})
+	case errors.Is(err, ErrInterviewClosed),
+		errors.Is(err, ErrInterviewNotAwaiting),
+		errors.Is(err, ErrInterviewSlotPast),
+		errors.Is(err, ErrInterviewInactive):
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+	case errors.Is(err, ErrInterviewSlotInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
+
+// escapeICSText escapes a TEXT value
+func escapeICSText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// foldICSLine splits a content line into 75-octet lines without breaking UTF-8 sequences
+func foldICSLine(line string) string {
+	var folded strings.Builder
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > 75 {
+			folded.WriteString("\r\n ")
+			length = 1
+		}
+		folded.WriteRune(r)
+		length += size
+	}
+	return folded.String()
+}
+
+// nullString maps an empty string to NULL
+func nullString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
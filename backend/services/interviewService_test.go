@@ -0,0 +1,256 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscapeICSText(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "Interview: Backend Developer", "Interview: Backend Developer"},
+		{"separators", "Floor 3, Room 2; Building A", `Floor 3\, Room 2\; Building A`},
+		{"backslash first", `C:\path;x`, `C:\\path\;x`},
+		{"line breaks", "Bring ID\r\nArrive early\nAsk for HR", `Bring ID\nArrive early\nAsk for HR`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := escapeICSText(test.value); got != test.want {
+				t.Errorf("escapeICSText(%q) = %q, want %q", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"short", "SUMMARY:Interview", "SUMMARY:Interview"},
+		{"exactly 75 octets", strings.Repeat("a", 75), strings.Repeat("a", 75)},
+		{"76 octets", strings.Repeat("a", 76), strings.Repeat("a", 75) + "\r\n a"},
+		{"continuation lines hold 74 octets", strings.Repeat("a", 75+74+1),
+			strings.Repeat("a", 75) + "\r\n " + strings.Repeat("a", 74) + "\r\n a"},
+		// 8 + 22×3 = 74 octets fit; the 23rd character would end at octet 77, so it moves to the next line whole
+		{"multi-byte boundary", "SUMMARY:" + strings.Repeat("ก", 23), "SUMMARY:" + strings.Repeat("ก", 22) + "\r\n ก"},
+		{"four-byte characters", "X:" + strings.Repeat("😀", 19), "X:" + strings.Repeat("😀", 18) + "\r\n 😀"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := foldICSLine(test.line)
+			if got != test.want {
+				t.Errorf("foldICSLine(%q) = %q, want %q", test.line, got, test.want)
+			}
+			for _, line := range strings.Split(got, "\r\n") {
+				if len(line) > 75 || !utf8.ValidString(line) {
+					t.Errorf("foldICSLine(%q) has line %q of %d octets, want at most 75 of valid UTF-8", test.line, line, len(line))
+				}
+			}
+			if unfolded := strings.ReplaceAll(got, "\r\n ", ""); unfolded != test.line {
+				t.Errorf("foldICSLine(%q) unfolds to %q", test.line, unfolded)
+			}
+		})
+	}
+}
+
+func TestInterviewICS(t *testing.T) {
+	stringp := func(value string) *string { return &value }
+	now := time.Date(2026, time.March, 15, 3, 4, 5, 0, time.UTC)
+
+	interview := Interview{
+		ID: 42, JobTitle: "Backend Developer, Platform", Mode: InterviewModeOnline, DurationMinutes: 45,
+		Status: InterviewStatusScheduled, ScheduledAt: stringp("2026-03-20T10:00:00+07:00"),
+		MeetingLink: stringp("https://meet.example.com/abc"), Note: stringp("Bring your portfolio; ask for Khun Somchai"),
+	}
+	calendar, err := InterviewICS(interview, now)
+	if err != nil {
+		t.Fatalf("InterviewICS() error = %v", err)
+	}
+	want := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"PRODID:-//fresh-grad-jobs//Interviews//EN\r\n" +
+		"CALSCALE:GREGORIAN\r\n" +
+		"METHOD:PUBLISH\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:interview-42@fresh-grad-jobs\r\n" +
+		"DTSTAMP:20260315T030405Z\r\n" +
+		"DTSTART:20260320T030000Z\r\n" +
+		"DTEND:20260320T034500Z\r\n" +
+		"SUMMARY:Interview: Backend Developer\\, Platform\r\n" +
+		"LOCATION:https://meet.example.com/abc\r\n" +
+		"URL:https://meet.example.com/abc\r\n" +
+		"DESCRIPTION:Meeting link: https://meet.example.com/abc\\nBring your portfoli\r\n" +
+		" o\\; ask for Khun Somchai\r\n" +
+		"STATUS:CONFIRMED\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	if calendar != want {
+		t.Errorf("InterviewICS() = %q, want %q", calendar, want)
+	}
+
+	t.Run("onsite uses the location", func(t *testing.T) {
+		onsite := interview
+		onsite.Mode, onsite.MeetingLink, onsite.Note, onsite.Location = InterviewModeOnsite, nil, nil, stringp("Floor 3, Room 2")
+		calendar, err := InterviewICS(onsite, now)
+		if err != nil {
+			t.Fatalf("InterviewICS() error = %v", err)
+		}
+		if !strings.Contains(calendar, "\r\nLOCATION:Floor 3\\, Room 2\r\n") ||
+			strings.Contains(calendar, "URL:") || strings.Contains(calendar, "DESCRIPTION:") {
+			t.Errorf("InterviewICS() = %q, want the escaped location without a URL or description", calendar)
+		}
+	})
+
+	t.Run("not scheduled", func(t *testing.T) {
+		proposed := interview
+		proposed.Status, proposed.ScheduledAt = InterviewStatusProposed, nil
+		if _, err := InterviewICS(proposed, now); err == nil {
+			t.Error("InterviewICS() error = nil, want an error for an interview that is not scheduled")
+		}
+	})
+}
+
+func TestParseInterviewSlots(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 3, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		values []string
+		want   []time.Time
+		errs   FieldErrors
+	}{
+		{
+			name:   "converted to UTC at second precision",
+			values: []string{" 2026-03-16T10:00:00+07:00 ", "2026-03-16T05:30:15.75Z"},
+			want:   []time.Time{time.Date(2026, time.March, 16, 3, 0, 0, 0, time.UTC), time.Date(2026, time.March, 16, 5, 30, 15, 0, time.UTC)},
+		},
+		{name: "none", values: nil, errs: FieldErrors{"slots": "must list 1 to 10 start times"}},
+		{name: "too many", values: make([]string, MaxInterviewSlots+1), errs: FieldErrors{"slots": "must list 1 to 10 start times"}},
+		{
+			name:   "not RFC 3339",
+			values: []string{"2026-03-16 10:00"},
+			errs:   FieldErrors{"slots[0]": "must be an RFC 3339 time such as " + interviewTimeExample},
+		},
+		{
+			name:   "now and in the past",
+			values: []string{"2026-03-15T10:00:00+07:00", "2026-03-14T10:00:00Z", "2026-03-15T03:00:01Z"},
+			want:   []time.Time{time.Date(2026, time.March, 15, 3, 0, 1, 0, time.UTC)},
+			errs:   FieldErrors{"slots[0]": "must be in the future", "slots[1]": "must be in the future"},
+		},
+		{
+			name:   "same time in another zone",
+			values: []string{"2026-03-16T03:00:00Z", "2026-03-16T10:00:00+07:00"},
+			want:   []time.Time{time.Date(2026, time.March, 16, 3, 0, 0, 0, time.UTC)},
+			errs:   FieldErrors{"slots[1]": "is listed twice"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			slots, errs := ParseInterviewSlots(test.values, now)
+
+			wantErrs := test.errs
+			if wantErrs == nil {
+				wantErrs = FieldErrors{}
+			}
+			if !reflect.DeepEqual(errs, wantErrs) {
+				t.Errorf("ParseInterviewSlots() errors = %v, want %v", errs, wantErrs)
+			}
+			if len(slots) != len(test.want) {
+				t.Fatalf("ParseInterviewSlots() = %v, want %v", slots, test.want)
+			}
+			for i := range slots {
+				if !slots[i].Equal(test.want[i]) || slots[i].Location() != time.UTC {
+					t.Errorf("ParseInterviewSlots()[%d] = %v, want %v", i, slots[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestInterviewInputValidate(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 3, 0, 0, 0, time.UTC)
+	slots := []string{"2026-03-16T10:00:00+07:00"}
+
+	tests := []struct {
+		name  string
+		input InterviewInput
+		// want is compared when no errors are expected
+		want InterviewInput
+		errs FieldErrors
+	}{
+		{
+			name:  "onsite",
+			input: InterviewInput{Mode: " Onsite ", Location: " Floor 3 ", DurationMinutes: 60, Slots: slots, Note: " Bring ID "},
+			want:  InterviewInput{Mode: "onsite", Location: "Floor 3", DurationMinutes: 60, Slots: slots, Note: "Bring ID"},
+		},
+		{
+			name:  "online",
+			input: InterviewInput{Mode: "online", MeetingLink: " https://meet.example.com/abc ", DurationMinutes: MinInterviewMinutes, Slots: slots},
+			want:  InterviewInput{Mode: "online", MeetingLink: "https://meet.example.com/abc", DurationMinutes: MinInterviewMinutes, Slots: slots},
+		},
+		{
+			name:  "onsite without a location",
+			input: InterviewInput{Mode: "onsite", Location: " ", DurationMinutes: 60, Slots: slots},
+			errs:  FieldErrors{"location": "is required for onsite interviews"},
+		},
+		{
+			name:  "online without a link",
+			input: InterviewInput{Mode: "online", DurationMinutes: 60, Slots: slots},
+			errs:  FieldErrors{"meeting_link": "is required for online interviews"},
+		},
+		{
+			name:  "link that is not http",
+			input: InterviewInput{Mode: "online", MeetingLink: "javascript:alert(1)", DurationMinutes: 60, Slots: slots},
+			errs:  FieldErrors{"meeting_link": "must be an http or https URL"},
+		},
+		{
+			name:  "long location",
+			input: InterviewInput{Mode: "onsite", Location: strings.Repeat("a", 256), DurationMinutes: 60, Slots: slots},
+			errs:  FieldErrors{"location": "must be at most 255 characters"},
+		},
+		{
+			name:  "unknown mode, duration out of range and no slots",
+			input: InterviewInput{Mode: "phone", DurationMinutes: MaxInterviewMinutes + 1},
+			errs: FieldErrors{
+				"mode":             enumMessage(InterviewModes),
+				"duration_minutes": "must be between 15 and 480",
+				"slots":            "must list 1 to 10 start times",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := test.input
+			parsed, errs := input.Validate(now)
+
+			wantErrs := test.errs
+			if wantErrs == nil {
+				wantErrs = FieldErrors{}
+			}
+			if !reflect.DeepEqual(errs, wantErrs) {
+				t.Fatalf("Validate() errors = %v, want %v", errs, wantErrs)
+			}
+			if len(wantErrs) > 0 {
+				return
+			}
+			if !reflect.DeepEqual(input, test.want) {
+				t.Errorf("Validate() input = %+v, want %+v", input, test.want)
+			}
+			if len(parsed) != 1 || !parsed[0].Equal(time.Date(2026, time.March, 16, 3, 0, 0, 0, time.UTC)) {
+				t.Errorf("Validate() slots = %v, want the one proposed slot", parsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Job expiry scheduler: closes open jobs whose application_deadline has passed and warns the owning
+// employer a few days before. Every replica runs the scheduler, but only the one holding the
+// scheduler_locks lease does the work.
+
+const jobExpiryLockName = "job_expiry"
+
+// JobExpiryConfig controls how often the scheduler runs and how early employers are notified
+type JobExpiryConfig struct {
+	Interval   time.Duration
+	NoticeDays int
+}
+
+// LoadJobExpiryConfig reads JOB_EXPIRY_INTERVAL (default 1h) and JOB_EXPIRY_NOTICE_DAYS (default 3)
+func LoadJobExpiryConfig() JobExpiryConfig {
+	config := JobExpiryConfig{Interval: time.Hour, NoticeDays: 3}
+
+	if value := os.Getenv("JOB_EXPIRY_INTERVAL"); value != "" {
+		if interval, err := time.ParseDuration(value); err == nil && interval > 0 {
+			config.Interval = interval
+		} else {
+			log.Printf("Warning: invalid JOB_EXPIRY_INTERVAL %q, using %v", value, config.Interval)
+		}
+	}
+
+	if value := os.Getenv("JOB_EXPIRY_NOTICE_DAYS"); value != "" {
+		if days, err := strconv.Atoi(value); err == nil && days >= 0 {
+			config.NoticeDays = days
+		} else {
+			log.Printf("Warning: invalid JOB_EXPIRY_NOTICE_DAYS %q, using %d", value, config.NoticeDays)
+		}
+	}
+
+	return config
+}
+
+// StartJobExpiryScheduler runs the job expiry checks every config.Interval until ctx is cancelled
+func StartJobExpiryScheduler(ctx context.Context, config JobExpiryConfig) {
+	holder := schedulerHolderID()
+	log.Printf("Job expiry scheduler started (holder %s, interval %v, notice %d days)", holder, config.Interval, config.NoticeDays)
+
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	for {
+		runJobExpiry(holder, config)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Job expiry scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runJobExpiry performs one scheduler run if this replica holds the leader lock
+func runJobExpiry(holder string, config JobExpiryConfig) {
+	db, err := ConnectDB()
+	if err != nil {
+		log.Printf("Job expiry: database connection error: %v", err)
+		return
+	}
+	defer db.Close()
+
+	// The lease outlives one interval so a slow run doesn't let another replica take over mid-run
+	leader, err := AcquireSchedulerLock(db, jobExpiryLockName, holder, 2*config.Interval)
+	if err != nil {
+		log.Printf("Job expiry: error acquiring scheduler lock: %v", err)
+		return
+	}
+	if !leader {
+		return
+	}
+
+	runID := "scheduler-" + newRequestID()
+	if err := notifyExpiringJobs(db, config.NoticeDays); err != nil {
+		log.Printf("Job expiry: error notifying employers: %v", err)
+	}
+	if err := closeExpiredJobs(db, runID); err != nil {
+		log.Printf("Job expiry: error closing expired jobs: %v", err)
+	}
+}
+
+// AcquireSchedulerLock takes or renews the named lease for holder, reporting whether holder owns it.
+// A lease held by another replica is only taken over once it has expired.
+func AcquireSchedulerLock(db *sql.DB, name, holder string, lease time.Duration) (bool, error) {
+	// MySQL applies the assignments left to right, so expires_at sees the new holder
+	query := `INSERT INTO scheduler_locks (name, holder, expires_at) VALUES (?, ?, NOW() + INTERVAL ? SECOND)
+		ON DUPLICATE KEY UPDATE
+			holder = IF(expires_at < NOW() OR holder = VALUES(holder), VALUES(holder), holder),
+			expires_at = IF(holder = VALUES(holder), VALUES(expires_at), expires_at)`
+	if _, err := db.Exec(query, name, holder, int(lease.Seconds())); err != nil {
+		return false, fmt.Errorf("error updating scheduler lock: %v", err)
+	}
+
+	var current string
+	if err := db.QueryRow("SELECT holder FROM scheduler_locks WHERE name = ?", name).Scan(&current); err != nil {
+		return false, fmt.Errorf("error reading scheduler lock: %v", err)
+	}
+	return current == holder, nil
+}
+
+// notifyExpiringJobs warns employers once about open jobs whose deadline is within noticeDays
+func notifyExpiringJobs(db *sql.DB, noticeDays int) error {
+	query := "SELECT job_id, employer_id, title, application_deadline FROM jobs WHERE job_status = ? " +
+		"AND expiry_notified_at IS NULL AND application_deadline >= ? " +
+		"AND application_deadline <= DATE_ADD(?, INTERVAL ? DAY)"
+	today := DeadlineDate(time.Now())
+	rows, err := db.Query(query, JobStatusOpen, today, today, noticeDays)
+	if err != nil {
+		return fmt.Errorf("error querying expiring jobs: %v", err)
+	}
+
+	type expiringJob struct {
+		id, employerID  int
+		title, deadline string
+	}
+	var jobs []expiringJob
+	for rows.Next() {
+		var job expiringJob
+		if err := rows.Scan(&job.id, &job.employerID, &job.title, &job.deadline); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning expiring job: %v", err)
+		}
+		jobs = append(jobs, job)
+	}
+	rows.Close()
+
+	for _, job := range jobs {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("error starting transaction: %v", err)
+		}
+
+		message := fmt.Sprintf("Your job posting \"%s\" closes on %s", job.title, job.deadline)
+		data := map[string]interface{}{"job_id": job.id, "application_deadline": job.deadline}
+		if err := CreateNotification(tx, job.employerID, "job.expiring", message, data); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec("UPDATE jobs SET expiry_notified_at = NOW() WHERE job_id = ?", job.id); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error marking job %d as notified: %v", job.id, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("error committing transaction: %v", err)
+		}
+		log.Printf("Job expiry: notified employer %d about job %d closing on %s", job.employerID, job.id, job.deadline)
+	}
+	return nil
+}
+
+// closeExpiredJobs closes open jobs past their deadline, auditing each change as the system actor
+func closeExpiredJobs(db *sql.DB, runID string) error {
+	today := DeadlineDate(time.Now())
+	query := "SELECT job_id, employer_id, title FROM jobs WHERE job_status = ? AND application_deadline < ?"
+	rows, err := db.Query(query, JobStatusOpen, today)
+	if err != nil {
+		return fmt.Errorf("error querying expired jobs: %v", err)
+	}
+
+	type expiredJob struct {
+		id, employerID int
+		title          string
+	}
+	var jobs []expiredJob
+	for rows.Next() {
+		var job expiredJob
+		if err := rows.Scan(&job.id, &job.employerID, &job.title); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning expired job: %v", err)
+		}
+		jobs = append(jobs, job)
+	}
+	rows.Close()
+
+	for _, job := range jobs {
+		entry := AuditEntry{
+			ActorRole:  "system",
+			Action:     "job.expire",
+			TargetType: "job",
+			TargetID:   strconv.Itoa(job.id),
+			RequestID:  runID,
+		}
+		closed, err := closeExpiredJob(db, entry, job.id, today)
+		if err != nil {
+			return fmt.Errorf("error closing job %d: %v", job.id, err)
+		}
+		if !closed {
+			continue
+		}
+
+		message := fmt.Sprintf("Your job posting \"%s\" was closed because its application deadline passed", job.title)
+		if err := CreateNotification(db, job.employerID, "job.expired", message, map[string]interface{}{"job_id": job.id}); err != nil {
+			log.Printf("Job expiry: error notifying employer %d about job %d: %v", job.employerID, job.id, err)
+		}
+		log.Printf("Job expiry: closed job %d", job.id)
+	}
+	return nil
+}
+
+// closeExpiredJob closes the job with an audit entry if it is still open and past its deadline, reporting
+// false when a concurrent change, such as a reopen or a new deadline, got there first
+func closeExpiredJob(db *sql.DB, entry AuditEntry, jobID int, today string) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Re-check the status under a lock so a concurrent reopen is neither overwritten nor audited as an expiry
+	lockQuery := "SELECT job_id FROM jobs WHERE job_id = ? AND job_status = ? AND application_deadline < ? FOR UPDATE"
+	if err := tx.QueryRow(lockQuery, jobID, JobStatusOpen, today).Scan(&jobID); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("error locking job: %v", err)
+	}
+	updateQuery := "UPDATE jobs SET job_status = ? WHERE job_id = ?"
+	if err := ExecWithAuditTx(tx, entry, JobSnapshotQuery, updateQuery, JobStatusClosed, jobID); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing transaction: %v", err)
+	}
+	return true, nil
+}
+
+// schedulerHolderID identifies this replica in scheduler_locks
+func schedulerHolderID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), newRequestID()[:8])
+}
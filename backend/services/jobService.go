@@ -0,0 +1,338 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JobType is the employment type of a job posting
+type JobType string
+
+const (
+	JobTypeFullTime   JobType = "Full-time"
+	JobTypePartTime   JobType = "Part-time"
+	JobTypeContract   JobType = "Contract"
+	JobTypeInternship JobType = "Internship"
+	JobTypeTemporary  JobType = "Temporary"
+)
+
+// JobTypes lists every valid JobType
+var JobTypes = []JobType{JobTypeFullTime, JobTypePartTime, JobTypeContract, JobTypeInternship, JobTypeTemporary}
+
+// JobStatus is the lifecycle state of a job posting
+type JobStatus string
+
+const (
+	JobStatusOpen   JobStatus = "open"
+	JobStatusClosed JobStatus = "closed"
+	// JobStatusFilled is set automatically once every position is filled; it is never set by employers
+	JobStatusFilled JobStatus = "filled"
+)
+
+// JobStatuses lists every JobStatus an employer may set
+var JobStatuses = []JobStatus{JobStatusOpen, JobStatusClosed}
+
+// MaxJobPositions caps the headcount of a single job
+const MaxJobPositions = 1000
+
+// JobLevel is the seniority of a job posting, from lowest to highest
+type JobLevel string
+
+const (
+	JobLevelInternship JobLevel = "Internship"
+	JobLevelEntry      JobLevel = "Entry"
+	JobLevelJunior     JobLevel = "Junior"
+	JobLevelMid        JobLevel = "Mid"
+	JobLevelSenior     JobLevel = "Senior"
+)
+
+// JobLevels lists every valid JobLevel in order of seniority
+var JobLevels = []JobLevel{JobLevelInternship, JobLevelEntry, JobLevelJunior, JobLevelMid, JobLevelSenior}
+
+// FieldErrors maps JSON field names to validation messages
+type FieldErrors map[string]string
+
+// JobInput is a job posting as sent by an employer; nil fields were not in the request
+type JobInput struct {
+	Title               *string  `json:"title"`
+	JobCategory         *string  `json:"job_category"`
+	JobType             *string  `json:"job_type"`
+	MinSalary           *float64 `json:"min_salary"`
+	MaxSalary           *float64 `json:"max_salary"`
+	MinExperience       *int     `json:"min_experience"`
+	MaxExperience       *int     `json:"max_experience"`
+	JobResponsibility   *string  `json:"job_responsibility"`
+	Qualification       *string  `json:"qualification"`
+	Benefits            *string  `json:"benefits"`
+	JobDescription      *string  `json:"job_description"`
+	Location            *string  `json:"location"`
+	PostedBy            *string  `json:"posted_by"`
+	ApplicationDeadline *string  `json:"application_deadline"`
+	JobStatus           *string  `json:"job_status"`
+	SkillsRequired      *string  `json:"skills_required"`
+	JobLevel            *string  `json:"job_level"`
+	Positions           *int     `json:"positions"`
+
+	// Skills are catalogue skill names or aliases; when present they replace skills_required
+	Skills *[]string `json:"skills"`
+}
+
+// Job is a validated job posting
+type Job struct {
+	Title               string
+	JobCategory         string
+	JobType             JobType
+	MinSalary           float64
+	MaxSalary           float64
+	MinExperience       int
+	MaxExperience       int
+	JobResponsibility   string
+	Qualification       string
+	Benefits            string
+	JobDescription      string
+	Location            string
+	PostedBy            string
+	ApplicationDeadline string
+	JobStatus           JobStatus
+	SkillsRequired      string
+	JobLevel            JobLevel
+	Positions           int
+
+	// PositionsFilled is maintained by accepted offers and only read by BuildJob
+	PositionsFilled int
+}
+
+// JobColumns are the editable job columns, in the order returned by Job.Values
+const JobColumns = "title, job_category, job_type, min_salary, max_salary, min_experience, max_experience, " +
+	"job_responsibility, qualification, benefits, job_description, location, posted_by, application_deadline, " +
+	"job_status, skills_required, job_level, positions"
+
+// Values returns the job's column values in JobColumns order
+func (job Job) Values() []interface{} {
+	return []interface{}{
+		job.Title, job.JobCategory, string(job.JobType), job.MinSalary, job.MaxSalary, job.MinExperience,
+		job.MaxExperience, job.JobResponsibility, job.Qualification, job.Benefits, job.JobDescription,
+		job.Location, job.PostedBy, job.ApplicationDeadline, string(job.JobStatus), job.SkillsRequired,
+		string(job.JobLevel), job.Positions,
+	}
+}
+
+// ScanTargets returns pointers to the job's fields in JobColumns order, for loading an existing job
+func (job *Job) ScanTargets() []interface{} {
+	return []interface{}{
+		&job.Title, &job.JobCategory, &job.JobType, &job.MinSalary, &job.MaxSalary, &job.MinExperience,
+		&job.MaxExperience, &job.JobResponsibility, &job.Qualification, &job.Benefits, &job.JobDescription,
+		&job.Location, &job.PostedBy, &job.ApplicationDeadline, &job.JobStatus, &job.SkillsRequired,
+		&job.JobLevel, &job.Positions,
+	}
+}
+
+// BuildJob applies input over base and validates the result. With a nil base (job creation) every
+// field is required; otherwise only the fields present in input change.
+func BuildJob(base *Job, input JobInput, now time.Time) (Job, FieldErrors) {
+	errs := FieldErrors{}
+	var job Job
+	if base != nil {
+		job = *base
+	}
+
+	text := func(field string, value *string, target *string) {
+		if value == nil {
+			if base == nil {
+				errs[field] = "is required"
+			}
+			return
+		}
+		trimmed := strings.TrimSpace(*value)
+		if trimmed == "" {
+			errs[field] = "must not be empty"
+			return
+		}
+		*target = trimmed
+	}
+	text("title", input.Title, &job.Title)
+	text("job_category", input.JobCategory, &job.JobCategory)
+	text("job_responsibility", input.JobResponsibility, &job.JobResponsibility)
+	text("qualification", input.Qualification, &job.Qualification)
+	text("benefits", input.Benefits, &job.Benefits)
+	text("job_description", input.JobDescription, &job.JobDescription)
+	text("location", input.Location, &job.Location)
+	text("posted_by", input.PostedBy, &job.PostedBy)
+	if input.SkillsRequired != nil {
+		job.SkillsRequired = strings.TrimSpace(*input.SkillsRequired)
+	}
+	if len(job.Title) > 255 {
+		errs["title"] = "must be at most 255 characters"
+	}
+
+	if input.JobType != nil {
+		if jobType, ok := parseEnum(*input.JobType, JobTypes); ok {
+			job.JobType = jobType
+		} else {
+			errs["job_type"] = enumMessage(JobTypes)
+		}
+	} else if base == nil {
+		errs["job_type"] = "is required"
+	}
+
+	if input.JobStatus != nil {
+		if jobStatus, ok := parseEnum(*input.JobStatus, JobStatuses); ok {
+			job.JobStatus = jobStatus
+		} else {
+			errs["job_status"] = enumMessage(JobStatuses)
+		}
+	} else if base == nil {
+		errs["job_status"] = "is required"
+	}
+
+	if input.JobLevel != nil {
+		if jobLevel, ok := parseEnum(*input.JobLevel, JobLevels); ok {
+			job.JobLevel = jobLevel
+		} else {
+			errs["job_level"] = enumMessage(JobLevels)
+		}
+	} else if base == nil {
+		errs["job_level"] = "is required"
+	}
+
+	number := func(field string, present bool) bool {
+		if !present && base == nil {
+			errs[field] = "is required"
+		}
+		return present
+	}
+	if number("min_salary", input.MinSalary != nil) {
+		job.MinSalary = *input.MinSalary
+	}
+	if number("max_salary", input.MaxSalary != nil) {
+		job.MaxSalary = *input.MaxSalary
+	}
+	if number("min_experience", input.MinExperience != nil) {
+		job.MinExperience = *input.MinExperience
+	}
+	if number("max_experience", input.MaxExperience != nil) {
+		job.MaxExperience = *input.MaxExperience
+	}
+
+	if job.MinSalary < 0 {
+		errs["min_salary"] = "must not be negative"
+	}
+	if job.MaxSalary < 0 {
+		errs["max_salary"] = "must not be negative"
+	}
+	if job.MinExperience < 0 {
+		errs["min_experience"] = "must not be negative"
+	}
+	if job.MaxExperience < 0 {
+		errs["max_experience"] = "must not be negative"
+	}
+	if _, failed := errs["max_salary"]; !failed && job.MinSalary > job.MaxSalary {
+		errs["max_salary"] = "must be greater than or equal to min_salary"
+	}
+	if _, failed := errs["max_experience"]; !failed && job.MinExperience > job.MaxExperience {
+		errs["max_experience"] = "must be greater than or equal to min_experience"
+	}
+
+	if input.Positions != nil {
+		job.Positions = *input.Positions
+	} else if base == nil {
+		job.Positions = 1
+	}
+	switch {
+	case job.Positions < 1 || job.Positions > MaxJobPositions:
+		errs["positions"] = fmt.Sprintf("must be between 1 and %d", MaxJobPositions)
+	case job.Positions < job.PositionsFilled:
+		errs["positions"] = fmt.Sprintf("must be at least the %d positions already filled", job.PositionsFilled)
+	}
+
+	// job_status follows the headcount: an open job with every position filled becomes filled, and a filled
+	// job given more positions opens again
+	if _, failed := errs["positions"]; !failed {
+		full := job.PositionsFilled >= job.Positions
+		switch {
+		case job.JobStatus == JobStatusOpen && full && input.JobStatus != nil:
+			errs["job_status"] = "cannot be open while every position is filled; raise positions first"
+		case job.JobStatus == JobStatusOpen && full:
+			job.JobStatus = JobStatusFilled
+		case job.JobStatus == JobStatusFilled && !full:
+			job.JobStatus = JobStatusOpen
+		}
+	}
+
+	if input.ApplicationDeadline != nil {
+		deadline, err := ParseDeadline(strings.TrimSpace(*input.ApplicationDeadline))
+		if err != nil {
+			errs["application_deadline"] = "must be a date in YYYY-MM-DD format"
+		} else if deadline.Before(DeadlineToday(now)) {
+			errs["application_deadline"] = "must not be in the past"
+		} else {
+			job.ApplicationDeadline = deadline.Format("2006-01-02")
+		}
+	} else if base == nil {
+		errs["application_deadline"] = "is required"
+	}
+
+	return job, errs
+}
+
+// ChangedColumns returns "column = ?" assignments and values for the fields present in input, taken from job.
+// A new positions count also writes job_status, which BuildJob derives from the headcount.
+func (input JobInput) ChangedColumns(job Job) ([]string, []interface{}) {
+	columns := strings.Split(JobColumns, ", ")
+	values := job.Values()
+	present := []bool{
+		input.Title != nil, input.JobCategory != nil, input.JobType != nil, input.MinSalary != nil,
+		input.MaxSalary != nil, input.MinExperience != nil, input.MaxExperience != nil,
+		input.JobResponsibility != nil, input.Qualification != nil, input.Benefits != nil,
+		input.JobDescription != nil, input.Location != nil, input.PostedBy != nil,
+		input.ApplicationDeadline != nil, input.JobStatus != nil || input.Positions != nil, input.SkillsRequired != nil,
+		input.JobLevel != nil, input.Positions != nil,
+	}
+
+	var fields []string
+	var args []interface{}
+	for i, ok := range present {
+		if ok {
+			fields = append(fields, columns[i]+" = ?")
+			args = append(args, values[i])
+		}
+	}
+	return fields, args
+}
+
+// BindingFieldErrors turns a JSON type mismatch into a field error, or returns nil for other errors
+func BindingFieldErrors(err error) FieldErrors {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return FieldErrors{typeErr.Field: fmt.Sprintf("must be a %s", typeErr.Type.String())}
+	}
+	return nil
+}
+
+// ParseJobLevel matches value case-insensitively against JobLevels
+func ParseJobLevel(value string) (JobLevel, bool) {
+	return parseEnum(value, JobLevels)
+}
+
+// parseEnum matches value case-insensitively against the allowed values
+func parseEnum[T ~string](value string, allowed []T) (T, bool) {
+	for _, candidate := range allowed {
+		if strings.EqualFold(strings.TrimSpace(value), string(candidate)) {
+			return candidate, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// enumMessage describes the allowed values of an enum
+func enumMessage[T ~string](allowed []T) string {
+	names := make([]string, len(allowed))
+	for i, value := range allowed {
+		names[i] = string(value)
+	}
+	return "must be one of: " + strings.Join(names, ", ")
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuildJob(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 10, 0, 0, 0, time.Local)
+	str := func(value string) *string { return &value }
+	num := func(value float64) *float64 { return &value }
+	count := func(value int) *int { return &value }
+
+	// complete is a valid creation request; each case changes a copy of it
+	complete := func() JobInput {
+		return JobInput{
+			Title: str("  Backend Developer "), JobCategory: str("Engineering"), JobType: str("full-time"),
+			MinSalary: num(25000), MaxSalary: num(35000), MinExperience: count(0), MaxExperience: count(2),
+			JobResponsibility: str("Build APIs"), Qualification: str("Go"), Benefits: str("Insurance"),
+			JobDescription: str("Join the platform team"), Location: str("Bangkok"), PostedBy: str("HR"),
+			ApplicationDeadline: str("2026-04-30"), JobStatus: str("OPEN"), SkillsRequired: str(" Go, SQL "),
+			JobLevel: str("entry"),
+		}
+	}
+	created := Job{
+		Title: "Backend Developer", JobCategory: "Engineering", JobType: JobTypeFullTime,
+		MinSalary: 25000, MaxSalary: 35000, MinExperience: 0, MaxExperience: 2,
+		JobResponsibility: "Build APIs", Qualification: "Go", Benefits: "Insurance",
+		JobDescription: "Join the platform team", Location: "Bangkok", PostedBy: "HR",
+		ApplicationDeadline: "2026-04-30", JobStatus: JobStatusOpen, SkillsRequired: "Go, SQL",
+		JobLevel: JobLevelEntry, Positions: 1,
+	}
+	existing := created
+	existing.Positions, existing.PositionsFilled = 3, 1
+
+	tests := []struct {
+		name   string
+		base   *Job
+		input  JobInput
+		change func(input *JobInput)
+		// want is compared when no errors are expected; edit changes a copy of the base or created job
+		edit func(job *Job)
+		errs FieldErrors
+	}{
+		// Creation
+		{name: "create", input: complete()},
+		{name: "create with positions", input: complete(), change: func(input *JobInput) { input.Positions = count(5) },
+			edit: func(job *Job) { job.Positions = 5 }},
+		{name: "create requires every field", input: JobInput{}, errs: FieldErrors{
+			"title": "is required", "job_category": "is required", "job_responsibility": "is required",
+			"qualification": "is required", "benefits": "is required", "job_description": "is required",
+			"location": "is required", "posted_by": "is required", "job_type": "is required",
+			"job_status": "is required", "job_level": "is required", "min_salary": "is required",
+			"max_salary": "is required", "min_experience": "is required", "max_experience": "is required",
+			"application_deadline": "is required",
+		}},
+		{name: "create with blank text", input: complete(), change: func(input *JobInput) { input.Title, input.Location = str(" "), str("") },
+			errs: FieldErrors{"title": "must not be empty", "location": "must not be empty"}},
+		{name: "create with a long title", input: complete(), change: func(input *JobInput) { input.Title = str(fmt.Sprintf("%0256d", 0)) },
+			errs: FieldErrors{"title": "must be at most 255 characters"}},
+
+		// Enums
+		{name: "unknown job type", input: complete(), change: func(input *JobInput) { input.JobType = str("Freelance") },
+			errs: FieldErrors{"job_type": enumMessage(JobTypes)}},
+		{name: "unknown job level", input: complete(), change: func(input *JobInput) { input.JobLevel = str("Lead") },
+			errs: FieldErrors{"job_level": enumMessage(JobLevels)}},
+		{name: "unknown job status", input: complete(), change: func(input *JobInput) { input.JobStatus = str("draft") },
+			errs: FieldErrors{"job_status": enumMessage(JobStatuses)}},
+		{name: "filled is not set by employers", input: complete(), change: func(input *JobInput) { input.JobStatus = str("filled") },
+			errs: FieldErrors{"job_status": enumMessage(JobStatuses)}},
+		{name: "closed", input: complete(), change: func(input *JobInput) { input.JobStatus = str("Closed") },
+			edit: func(job *Job) { job.JobStatus = JobStatusClosed }},
+
+		// Ranges
+		{name: "equal salaries", input: complete(), change: func(input *JobInput) { input.MaxSalary = num(25000) },
+			edit: func(job *Job) { job.MaxSalary = 25000 }},
+		{name: "min salary above max", input: complete(), change: func(input *JobInput) { input.MinSalary = num(40000) },
+			errs: FieldErrors{"max_salary": "must be greater than or equal to min_salary"}},
+		{name: "negative salary", input: complete(), change: func(input *JobInput) { input.MinSalary = num(-1) },
+			errs: FieldErrors{"min_salary": "must not be negative"}},
+		{name: "min experience above max", input: complete(), change: func(input *JobInput) { input.MinExperience = count(3) },
+			errs: FieldErrors{"max_experience": "must be greater than or equal to min_experience"}},
+		{name: "negative experience", input: complete(), change: func(input *JobInput) { input.MaxExperience = count(-1) },
+			errs: FieldErrors{"max_experience": "must not be negative"}},
+		{name: "no positions", input: complete(), change: func(input *JobInput) { input.Positions = count(0) },
+			errs: FieldErrors{"positions": "must be between 1 and 1000"}},
+		{name: "most positions", input: complete(), change: func(input *JobInput) { input.Positions = count(MaxJobPositions) },
+			edit: func(job *Job) { job.Positions = MaxJobPositions }},
+		{name: "too many positions", input: complete(), change: func(input *JobInput) { input.Positions = count(MaxJobPositions + 1) },
+			errs: FieldErrors{"positions": "must be between 1 and 1000"}},
+
+		// Deadlines
+		{name: "deadline today", input: complete(), change: func(input *JobInput) { input.ApplicationDeadline = str("2026-03-15") },
+			edit: func(job *Job) { job.ApplicationDeadline = "2026-03-15" }},
+		{name: "deadline in the past", input: complete(), change: func(input *JobInput) { input.ApplicationDeadline = str("2026-03-14") },
+			errs: FieldErrors{"application_deadline": "must not be in the past"}},
+		{name: "deadline in another format", input: complete(), change: func(input *JobInput) { input.ApplicationDeadline = str("30/04/2026") },
+			errs: FieldErrors{"application_deadline": "must be a date in YYYY-MM-DD format"}},
+
+		// Updates
+		{name: "empty update keeps the base", base: &existing, input: JobInput{}},
+		{name: "update merges onto the base", base: &existing,
+			input: JobInput{Title: str("Senior Backend Developer"), MaxSalary: num(60000), JobLevel: str("senior"), SkillsRequired: str("")},
+			edit: func(job *Job) {
+				job.Title, job.MaxSalary, job.JobLevel, job.SkillsRequired = "Senior Backend Developer", 60000, JobLevelSenior, ""
+			}},
+		{name: "update checks ranges against the base", base: &existing, input: JobInput{MinSalary: num(50000)},
+			errs: FieldErrors{"max_salary": "must be greater than or equal to min_salary"}},
+		{name: "update with blank text", base: &existing, input: JobInput{Benefits: str("  ")},
+			errs: FieldErrors{"benefits": "must not be empty"}},
+		{name: "update with no positions", base: &existing, input: JobInput{Positions: count(0)},
+			errs: FieldErrors{"positions": "must be between 1 and 1000"}},
+		{name: "update filling every position", base: &existing, input: JobInput{Positions: count(1)},
+			edit: func(job *Job) { job.Positions, job.JobStatus = 1, JobStatusFilled }},
+		{name: "update reopening a filled job", base: &Job{JobStatus: JobStatusFilled, Positions: 1, PositionsFilled: 1,
+			MinSalary: 1, MaxSalary: 2}, input: JobInput{Positions: count(2)},
+			edit: func(job *Job) { job.Positions, job.JobStatus = 2, JobStatusOpen }},
+		{name: "update opening a full job", base: &Job{JobStatus: JobStatusFilled, Positions: 1, PositionsFilled: 1},
+			input: JobInput{JobStatus: str("open")},
+			errs:  FieldErrors{"job_status": "cannot be open while every position is filled; raise positions first"}},
+		{name: "update below the positions already filled", base: &Job{JobStatus: JobStatusOpen, Positions: 3, PositionsFilled: 2},
+			input: JobInput{Positions: count(1)},
+			errs:  FieldErrors{"positions": "must be at least the 2 positions already filled"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := test.input
+			if test.change != nil {
+				test.change(&input)
+			}
+			job, errs := BuildJob(test.base, input, now)
+
+			wantErrs := test.errs
+			if wantErrs == nil {
+				wantErrs = FieldErrors{}
+			}
+			if !reflect.DeepEqual(errs, wantErrs) {
+				t.Fatalf("BuildJob() errors = %v, want %v", errs, wantErrs)
+			}
+			if len(wantErrs) > 0 {
+				return
+			}
+
+			want := created
+			if test.base != nil {
+				want = *test.base
+			}
+			if test.edit != nil {
+				test.edit(&want)
+			}
+			if job != want {
+				t.Errorf("BuildJob() = %+v, want %+v", job, want)
+			}
+		})
+	}
+}
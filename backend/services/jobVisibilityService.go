@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Job visibility policy: a job is shown to fresh grads and on the public board only when
+//   - an admin has approved it
+//   - its job_status is open
+//   - its application_deadline has not passed (the deadline day itself is still visible)
+//   - the employer who posted it still exists and is not suspended
+// VisibleJobCondition and CheckJobVisibility must be kept in sync. Both take today's date from DeadlineToday
+// rather than the database clock, so a job cannot pass one check and fail the other around midnight.
+
+// Reasons a job is hidden from fresh grads
+var (
+	ErrJobNotApproved     = errors.New("job is not approved")
+	ErrJobNotOpen         = errors.New("job is not open")
+	ErrJobDeadlinePassed  = errors.New("job application deadline has passed")
+	ErrJobEmployerBlocked = errors.New("job employer is suspended or deleted")
+)
+
+// JobVisibility holds the facts the visibility policy is evaluated on
+type JobVisibility struct {
+	Approved            bool
+	JobStatus           JobStatus
+	ApplicationDeadline string
+	EmployerExists      bool
+	EmployerSuspended   bool
+}
+
+// VisibleJobCondition returns the SQL condition implementing the visibility policy at time now for the jobs
+// table alias. The date is formatted here, never taken from input, so it is safe to inline.
+func VisibleJobCondition(alias string, now time.Time) string {
+	return alias + ".approved = TRUE" +
+		" AND " + alias + ".job_status = '" + string(JobStatusOpen) + "'" +
+		" AND " + alias + ".application_deadline >= '" + DeadlineDate(now) + "'" +
+		" AND EXISTS (SELECT 1 FROM users visibility_employer WHERE visibility_employer.user_id = " + alias + ".employer_id" +
+		" AND visibility_employer.suspended = FALSE)"
+}
+
+// VisibilityFactsQuery selects the JobVisibility facts of a job by ID
+const VisibilityFactsQuery = "SELECT j.approved, j.job_status, j.application_deadline, u.user_id IS NOT NULL, " +
+	"COALESCE(u.suspended, FALSE) FROM jobs j LEFT JOIN users u ON u.user_id = j.employer_id WHERE j.job_id = ?"
+
+// CheckJobVisibility applies the visibility policy at time now, returning the first rule the job breaks
+func CheckJobVisibility(job JobVisibility, now time.Time) error {
+	if !job.Approved {
+		return ErrJobNotApproved
+	}
+	if job.JobStatus != JobStatusOpen {
+		return ErrJobNotOpen
+	}
+
+	deadline, err := ParseDeadline(job.ApplicationDeadline)
+	if err != nil {
+		return fmt.Errorf("invalid application deadline %q: %v", job.ApplicationDeadline, err)
+	}
+	if deadline.Before(DeadlineToday(now)) {
+		return ErrJobDeadlinePassed
+	}
+
+	if !job.EmployerExists || job.EmployerSuspended {
+		return ErrJobEmployerBlocked
+	}
+	return nil
+}
+
+// deadlineLayouts are the accepted application_deadline formats
+var deadlineLayouts = []string{deadlineDateLayout, "2006-01-02 15:04:05", time.RFC3339}
+
+// deadlineDateLayout is how deadlines are stored and compared
+const deadlineDateLayout = "2006-01-02"
+
+// DeadlineToday returns midnight of now's date in the local time zone, the zone deadlines are kept in
+func DeadlineToday(now time.Time) time.Time {
+	local := now.In(time.Local)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// DeadlineDate formats DeadlineToday for comparison with application_deadline in SQL
+func DeadlineDate(now time.Time) string {
+	return DeadlineToday(now).Format(deadlineDateLayout)
+}
+
+// ParseDeadline parses an application deadline, keeping only the date in the local time zone
+func ParseDeadline(value string) (time.Time, error) {
+	for _, layout := range deadlineLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("expected format YYYY-MM-DD")
+}
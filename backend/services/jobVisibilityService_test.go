@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckJobVisibility(t *testing.T) {
+	// One second before midnight, so the deadline day itself is at its last moment
+	now := time.Date(2026, time.March, 15, 23, 59, 59, 0, time.Local)
+	visible := JobVisibility{
+		Approved:            true,
+		JobStatus:           JobStatusOpen,
+		ApplicationDeadline: "2026-03-20",
+		EmployerExists:      true,
+	}
+
+	tests := []struct {
+		name    string
+		change  func(job *JobVisibility)
+		want    error
+		invalid bool
+	}{
+		{name: "visible", change: func(job *JobVisibility) {}},
+		{name: "not approved", change: func(job *JobVisibility) { job.Approved = false }, want: ErrJobNotApproved},
+		{name: "closed", change: func(job *JobVisibility) { job.JobStatus = JobStatusClosed }, want: ErrJobNotOpen},
+		{name: "filled", change: func(job *JobVisibility) { job.JobStatus = JobStatusFilled }, want: ErrJobNotOpen},
+		{name: "deadline passed", change: func(job *JobVisibility) { job.ApplicationDeadline = "2026-03-14" }, want: ErrJobDeadlinePassed},
+		{name: "deadline day itself", change: func(job *JobVisibility) { job.ApplicationDeadline = "2026-03-15" }},
+		{name: "deadline day as datetime", change: func(job *JobVisibility) { job.ApplicationDeadline = "2026-03-15 00:00:00" }},
+		{name: "employer suspended", change: func(job *JobVisibility) { job.EmployerSuspended = true }, want: ErrJobEmployerBlocked},
+		{name: "employer deleted", change: func(job *JobVisibility) { job.EmployerExists = false }, want: ErrJobEmployerBlocked},
+		{name: "unparsable deadline", change: func(job *JobVisibility) { job.ApplicationDeadline = "15/03/2026" }, invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			job := visible
+			test.change(&job)
+			err := CheckJobVisibility(job, now)
+			switch {
+			case test.invalid:
+				if err == nil || errors.Is(err, ErrJobDeadlinePassed) {
+					t.Fatalf("CheckJobVisibility() = %v, want an invalid deadline error", err)
+				}
+			case !errors.Is(err, test.want):
+				t.Fatalf("CheckJobVisibility() = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestVisibleJobConditionMatchesCheckJobVisibility(t *testing.T) {
+	beforeMidnight := time.Date(2026, time.March, 15, 23, 59, 59, 0, time.Local)
+	times := []time.Time{
+		beforeMidnight,
+		beforeMidnight.Add(time.Second),
+		// The same instants seen from other zones still use the local date
+		beforeMidnight.In(time.UTC),
+		beforeMidnight.In(time.FixedZone("UTC+14", 14*60*60)),
+	}
+
+	for _, now := range times {
+		today := DeadlineDate(now)
+		condition := VisibleJobCondition("j", now)
+		if !strings.Contains(condition, "j.application_deadline >= '"+today+"'") {
+			t.Errorf("VisibleJobCondition(%v) = %q, want a deadline of %s", now, condition, today)
+		}
+
+		job := JobVisibility{Approved: true, JobStatus: JobStatusOpen, ApplicationDeadline: today, EmployerExists: true}
+		if err := CheckJobVisibility(job, now); err != nil {
+			t.Errorf("CheckJobVisibility() at %v with deadline %s = %v, want visible", now, today, err)
+		}
+		job.ApplicationDeadline = DeadlineToday(now).AddDate(0, 0, -1).Format(deadlineDateLayout)
+		if err := CheckJobVisibility(job, now); !errors.Is(err, ErrJobDeadlinePassed) {
+			t.Errorf("CheckJobVisibility() at %v with deadline %s = %v, want %v", now, job.ApplicationDeadline, err, ErrJobDeadlinePassed)
+		}
+	}
+}
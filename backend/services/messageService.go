@@ -0,0 +1,398 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Roles of message senders
+const (
+	MessageSenderEmployer  = "employer"
+	MessageSenderFreshGrad = "freshGrad"
+)
+
+// Limits on a single message
+const (
+	MaxMessageLength      = 5000
+	MaxMessageAttachments = 5
+	MaxAttachmentBytes    = 10 << 20
+)
+
+// MessageAttachment is a file attached to a message
+type MessageAttachment struct {
+	ID          int64  `json:"attachment_id"`
+	MessageID   int64  `json:"message_id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+	storageKey  string
+}
+
+// MessageRead is a read receipt
+type MessageRead struct {
+	UserID int    `json:"user_id"`
+	ReadAt string `json:"read_at"`
+}
+
+// Message is one message of an application's conversation. Hidden messages keep their body and attachments
+// only for moderators.
+type Message struct {
+	ID           int64               `json:"message_id"`
+	SenderID     int                 `json:"sender_id"`
+	SenderRole   string              `json:"sender_role"`
+	Body         string              `json:"body"`
+	CreatedAt    string              `json:"created_at"`
+	Hidden       bool                `json:"hidden"`
+	HiddenReason *string             `json:"hidden_reason,omitempty"`
+	Attachments  []MessageAttachment `json:"attachments"`
+	ReadBy       []MessageRead       `json:"read_by"`
+}
+
+// MessageInput is a message to send with its uploaded attachments
+type MessageInput struct {
+	Body  string
+	Files []*multipart.FileHeader
+}
+
+// UnreadCount is the number of unread messages in one application's conversation
+type UnreadCount struct {
+	ApplicationID int    `json:"application_id"`
+	JobID         int    `json:"job_id"`
+	JobTitle      string `json:"job_title"`
+	Unread        int    `json:"unread"`
+}
+
+// ErrAttachmentNotFound is returned for attachments outside the conversation or of hidden messages
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// ParseMessageInput reads a message from a JSON body {"body": ...} or a multipart form with a body field and
+// attachments files, returning field errors when it is empty or too large
+func ParseMessageInput(c *gin.Context) (MessageInput, FieldErrors, error) {
+	var input MessageInput
+	if c.ContentType() == "multipart/form-data" {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxMessageAttachments*MaxAttachmentBytes+MaxMessageLength*4+(1<<20))
+		form, err := c.MultipartForm()
+		if err != nil {
+			return input, nil, err
+		}
+		input.Body = strings.Join(form.Value["body"], "\n")
+		input.Files = form.File["attachments"]
+	} else {
+		var request struct {
+			Body string `json:"body"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			return input, nil, err
+		}
+		input.Body = request.Body
+	}
+
+	errs := FieldErrors{}
+	input.Body = strings.TrimSpace(input.Body)
+	if input.Body == "" && len(input.Files) == 0 {
+		errs["body"] = "must not be empty without attachments"
+	}
+	if len([]rune(input.Body)) > MaxMessageLength {
+		errs["body"] = fmt.Sprintf("must be at most %d characters", MaxMessageLength)
+	}
+	if len(input.Files) > MaxMessageAttachments {
+		errs["attachments"] = fmt.Sprintf("must be at most %d files", MaxMessageAttachments)
+	}
+	for i, file := range input.Files {
+		if file.Size > MaxAttachmentBytes {
+			errs[fmt.Sprintf("attachments[%d]", i)] = fmt.Sprintf("must be at most %d MB", MaxAttachmentBytes>>20)
+		}
+	}
+	return input, errs, nil
+}
+
+// SendMessage stores the attachments, then adds the message to the application's conversation and notifies the
+// other party in one transaction. Stored files are removed again when the message cannot be saved.
+func SendMessage(db *sql.DB, storage Storage, applicationID, senderID interface{}, senderRole string, input MessageInput) (int64, error) {
+	var attachments []MessageAttachment
+	cleanup := func() {
+		for _, attachment := range attachments {
+			if err := storage.Delete(attachment.storageKey); err != nil {
+				log.Printf("Error removing attachment %s: %v", attachment.storageKey, err)
+			}
+		}
+	}
+	for _, header := range input.Files {
+		attachment, err := storeAttachment(storage, header)
+		if err != nil {
+			cleanup()
+			return 0, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	messageID, err := insertMessage(db, applicationID, senderID, senderRole, input.Body, attachments)
+	if err != nil {
+		cleanup()
+		return 0, err
+	}
+	return messageID, nil
+}
+
+// storeAttachment saves an uploaded file, detecting its content type from the first bytes
+func storeAttachment(storage Storage, header *multipart.FileHeader) (MessageAttachment, error) {
+	file, err := header.Open()
+	if err != nil {
+		return MessageAttachment{}, fmt.Errorf("error opening upload: %v", err)
+	}
+	defer file.Close()
+
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return MessageAttachment{}, fmt.Errorf("error reading upload: %v", err)
+	}
+	key, size, err := storage.Save(io.MultiReader(bytes.NewReader(sniff[:n]), file))
+	if err != nil {
+		return MessageAttachment{}, err
+	}
+	return MessageAttachment{
+		FileName:    cleanFileName(header.Filename),
+		ContentType: http.DetectContentType(sniff[:n]),
+		SizeBytes:   size,
+		storageKey:  key,
+	}, nil
+}
+
+// cleanFileName keeps the base name of an uploaded file without control characters, at most 255 bytes
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	for len(name) > 255 {
+		runes := []rune(name)
+		name = string(runes[:len(runes)-1])
+	}
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
+
+// insertMessage saves a message with its stored attachments and notifies the other party
+func insertMessage(db *sql.DB, applicationID, senderID interface{}, senderRole, body string, attachments []MessageAttachment) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO application_messages (application_id, sender_id, sender_role, body) VALUES (?, ?, ?, ?)",
+		applicationID, senderID, senderRole, body)
+	if err != nil {
+		return 0, fmt.Errorf("error saving message: %v", err)
+	}
+	messageID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error reading message ID: %v", err)
+	}
+	for _, attachment := range attachments {
+		query := "INSERT INTO message_attachments (message_id, file_name, content_type, size_bytes, storage_key) VALUES (?, ?, ?, ?, ?)"
+		if _, err := tx.Exec(query, messageID, attachment.FileName, attachment.ContentType, attachment.SizeBytes, attachment.storageKey); err != nil {
+			return 0, fmt.Errorf("error saving attachment: %v", err)
+		}
+	}
+	// The sender has read their own message
+	if _, err := tx.Exec("INSERT INTO message_reads (message_id, user_id) VALUES (?, ?)", messageID, senderID); err != nil {
+		return 0, fmt.Errorf("error saving read receipt: %v", err)
+	}
+
+	// Messages from the employer's team go to the applicant, and the applicant's to the employer who posted the job
+	var recipientID int
+	var jobTitle string
+	recipientColumn := "f.user_id"
+	if senderRole == MessageSenderFreshGrad {
+		recipientColumn = "j.employer_id"
+	}
+	query := "SELECT " + recipientColumn + ", j.title FROM applications a " +
+		"INNER JOIN jobs j ON j.job_id = a.job_id " +
+		"INNER JOIN freshgradprofiles f ON f.freshgradprofile_id = a.freshgradprofile_id WHERE a.application_id = ?"
+	if err := tx.QueryRow(query, applicationID).Scan(&recipientID, &jobTitle); err != nil {
+		return 0, fmt.Errorf("error loading message recipient: %v", err)
+	}
+	message := fmt.Sprintf("New message about \"%s\"", jobTitle)
+	data := map[string]interface{}{"application_id": applicationID, "message_id": messageID}
+	if err := CreateNotification(tx, recipientID, "message.new", message, data); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing message: %v", err)
+	}
+	return messageID, nil
+}
+
+// LoadMessages reads an application's conversation, oldest first, with attachments and read receipts.
+// Moderators see the content and reason of hidden messages.
+func LoadMessages(q Querier, applicationID interface{}, moderator bool) ([]Message, error) {
+	query := "SELECT message_id, sender_id, sender_role, body, created_at, hidden_at IS NOT NULL, hidden_reason " +
+		"FROM application_messages WHERE application_id = ? ORDER BY created_at, message_id"
+	rows, err := q.Query(query, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying messages: %v", err)
+	}
+	defer rows.Close()
+
+	messages := []Message{}
+	index := map[int64]int{}
+	for rows.Next() {
+		var message Message
+		if err := rows.Scan(&message.ID, &message.SenderID, &message.SenderRole, &message.Body, &message.CreatedAt,
+			&message.Hidden, &message.HiddenReason); err != nil {
+			return nil, fmt.Errorf("error scanning message: %v", err)
+		}
+		if message.Hidden && !moderator {
+			message.Body, message.HiddenReason = "", nil
+		}
+		message.Attachments, message.ReadBy = []MessageAttachment{}, []MessageRead{}
+		index[message.ID] = len(messages)
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	attachmentQuery := "SELECT at.attachment_id, at.message_id, at.file_name, at.content_type, at.size_bytes " +
+		"FROM message_attachments at INNER JOIN application_messages m ON m.message_id = at.message_id " +
+		"WHERE m.application_id = ? ORDER BY at.attachment_id"
+	attachmentRows, err := q.Query(attachmentQuery, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying attachments: %v", err)
+	}
+	defer attachmentRows.Close()
+	for attachmentRows.Next() {
+		var attachment MessageAttachment
+		if err := attachmentRows.Scan(&attachment.ID, &attachment.MessageID, &attachment.FileName, &attachment.ContentType,
+			&attachment.SizeBytes); err != nil {
+			return nil, fmt.Errorf("error scanning attachment: %v", err)
+		}
+		if i, ok := index[attachment.MessageID]; ok && (moderator || !messages[i].Hidden) {
+			messages[i].Attachments = append(messages[i].Attachments, attachment)
+		}
+	}
+	if err := attachmentRows.Err(); err != nil {
+		return nil, err
+	}
+
+	readQuery := "SELECT r.message_id, r.user_id, r.read_at FROM message_reads r " +
+		"INNER JOIN application_messages m ON m.message_id = r.message_id WHERE m.application_id = ? ORDER BY r.read_at"
+	readRows, err := q.Query(readQuery, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying read receipts: %v", err)
+	}
+	defer readRows.Close()
+	for readRows.Next() {
+		var messageID int64
+		var read MessageRead
+		if err := readRows.Scan(&messageID, &read.UserID, &read.ReadAt); err != nil {
+			return nil, fmt.Errorf("error scanning read receipt: %v", err)
+		}
+		if i, ok := index[messageID]; ok {
+			messages[i].ReadBy = append(messages[i].ReadBy, read)
+		}
+	}
+	return messages, readRows.Err()
+}
+
+// MarkMessagesRead records that the user has read every message of the application's conversation
+func MarkMessagesRead(exec Execer, applicationID, userID interface{}) error {
+	query := "INSERT IGNORE INTO message_reads (message_id, user_id) " +
+		"SELECT message_id, ? FROM application_messages WHERE application_id = ? AND hidden_at IS NULL"
+	if _, err := exec.Exec(query, userID, applicationID); err != nil {
+		return fmt.Errorf("error marking messages read: %v", err)
+	}
+	return nil
+}
+
+// UnreadMessageCounts counts the user's unread messages per conversation, limited by condition over
+// applications a, jobs j and freshgradprofiles f
+func UnreadMessageCounts(q Querier, userID interface{}, condition string, args ...interface{}) ([]UnreadCount, error) {
+	query := "SELECT a.application_id, j.job_id, j.title, COUNT(*) FROM application_messages m " +
+		"INNER JOIN applications a ON a.application_id = m.application_id " +
+		"INNER JOIN jobs j ON j.job_id = a.job_id " +
+		"INNER JOIN freshgradprofiles f ON f.freshgradprofile_id = a.freshgradprofile_id " +
+		"WHERE m.hidden_at IS NULL AND m.sender_id <> ? " +
+		"AND NOT EXISTS (SELECT 1 FROM message_reads r WHERE r.message_id = m.message_id AND r.user_id = ?) " +
+		"AND " + condition + " GROUP BY a.application_id, j.job_id, j.title ORDER BY MAX(m.created_at) DESC"
+	rows, err := q.Query(query, append([]interface{}{userID, userID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("error counting unread messages: %v", err)
+	}
+	defer rows.Close()
+
+	counts := []UnreadCount{}
+	for rows.Next() {
+		var count UnreadCount
+		if err := rows.Scan(&count.ApplicationID, &count.JobID, &count.JobTitle, &count.Unread); err != nil {
+			return nil, fmt.Errorf("error scanning unread count: %v", err)
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// WriteMessageAttachment streams the attachment in the request path (message-id, attachment-id) of the
+// application's conversation. Attachments of hidden messages are only served to moderators.
+func WriteMessageAttachment(c *gin.Context, q Querier, applicationID interface{}, moderator bool) {
+	messageID, attachmentID := c.Param("message-id"), c.Param("attachment-id")
+
+	var attachment MessageAttachment
+	var hidden bool
+	query := "SELECT at.attachment_id, at.file_name, at.content_type, at.size_bytes, at.storage_key, m.hidden_at IS NOT NULL " +
+		"FROM message_attachments at INNER JOIN application_messages m ON m.message_id = at.message_id " +
+		"WHERE at.attachment_id = ? AND at.message_id = ? AND m.application_id = ?"
+	err := q.QueryRow(query, attachmentID, messageID, applicationID).Scan(&attachment.ID, &attachment.FileName,
+		&attachment.ContentType, &attachment.SizeBytes, &attachment.storageKey, &hidden)
+	if err == nil && hidden && !moderator {
+		err = ErrAttachmentNotFound
+	}
+	if err == sql.ErrNoRows || errors.Is(err, ErrAttachmentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Attachment not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Error loading attachment %s: %v", attachmentID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+
+	storage, err := NewStorage()
+	if err != nil {
+		log.Printf("File storage error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "File storage error"})
+		return
+	}
+	file, err := storage.Open(attachment.storageKey)
+	if err != nil {
+		log.Printf("Error opening attachment %s: %v", attachmentID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "File storage error"})
+		return
+	}
+	defer file.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName})
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, attachment.SizeBytes, attachment.ContentType, file,
+		map[string]string{"Content-Disposition": disposition})
+}
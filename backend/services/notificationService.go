@@ -0,0 +1,239 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Execer is implemented by both *sql.DB and *sql.Tx
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Notification is a persisted notification of a user
+type Notification struct {
+	ID        int64           `json:"notification_id"`
+	Type      string          `json:"type"`
+	Message   string          `json:"message"`
+	Data      json.RawMessage `json:"data"`
+	ReadAt    *string         `json:"read_at"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// CreateNotification stores a notification for a user, inside a transaction when exec is a *sql.Tx, and wakes
+// the user's open notification streams
+func CreateNotification(exec Execer, userID int, kind, message string, data map[string]interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("error encoding notification data: %v", err)
+	}
+
+	query := "INSERT INTO notifications (user_id, type, message, data) VALUES (?, ?, ?, ?)"
+	if _, err := exec.Exec(query, userID, kind, message, string(payload)); err != nil {
+		return fmt.Errorf("error creating notification: %v", err)
+	}
+	Notifications.Wake(userID)
+	return nil
+}
+
+// ListNotifications reads the user's notifications after afterID, oldest first when afterID is set and newest
+// first otherwise, optionally only unread ones
+func ListNotifications(q Querier, userID interface{}, afterID int64, unreadOnly bool, limit int) ([]Notification, error) {
+	conditions := []string{"user_id = ?"}
+	args := []interface{}{userID}
+	order := "notification_id DESC"
+	if afterID > 0 {
+		conditions = append(conditions, "notification_id > ?")
+		args = append(args, afterID)
+		order = "notification_id"
+	}
+	if unreadOnly {
+		conditions = append(conditions, "read_at IS NULL")
+	}
+	query := "SELECT notification_id, type, message, data, read_at, created_at FROM notifications WHERE " +
+		strings.Join(conditions, " AND ") + " ORDER BY " + order + " LIMIT ?"
+	rows, err := q.Query(query, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying notifications: %v", err)
+	}
+	defer rows.Close()
+
+	notifications := []Notification{}
+	for rows.Next() {
+		var notification Notification
+		var data sql.NullString
+		if err := rows.Scan(&notification.ID, &notification.Type, &notification.Message, &data,
+			&notification.ReadAt, &notification.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning notification: %v", err)
+		}
+		notification.Data = json.RawMessage("null")
+		if data.Valid {
+			notification.Data = json.RawMessage(data.String)
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, rows.Err()
+}
+
+// LatestNotificationID returns the ID of the user's newest notification, 0 when there is none
+func LatestNotificationID(q Querier, userID interface{}) (int64, error) {
+	var latest int64
+	if err := q.QueryRow("SELECT COALESCE(MAX(notification_id), 0) FROM notifications WHERE user_id = ?", userID).Scan(&latest); err != nil {
+		return 0, fmt.Errorf("error reading latest notification: %v", err)
+	}
+	return latest, nil
+}
+
+// UnreadNotificationCount counts the user's unread notifications
+func UnreadNotificationCount(q Querier, userID interface{}) (int, error) {
+	var count int
+	if err := q.QueryRow("SELECT COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL", userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting unread notifications: %v", err)
+	}
+	return count, nil
+}
+
+// notificationCommitGrace is how long after sending a notification a stream still looks for notifications with
+// lower IDs, created by transactions that had not committed yet
+const notificationCommitGrace = time.Minute
+
+// NotificationCursor is the position of a notification stream. IDs are assigned when a notification is
+// inserted but it is only visible once its transaction commits, so a notification can appear below an ID
+// already sent. The cursor reads after the highest ID sent at least notificationCommitGrace ago and skips
+// the IDs it has sent since.
+type NotificationCursor struct {
+	after int64
+	sent  map[int64]time.Time
+}
+
+// NewNotificationCursor starts a cursor after lastID
+func NewNotificationCursor(lastID int64) *NotificationCursor {
+	return &NotificationCursor{after: lastID, sent: map[int64]time.Time{}}
+}
+
+// After returns the ID to list notifications after, first moving past the IDs sent before the grace period
+func (cursor *NotificationCursor) After(now time.Time) int64 {
+	for id, sentAt := range cursor.sent {
+		if id > cursor.after && now.Sub(sentAt) >= notificationCommitGrace {
+			cursor.after = id
+		}
+	}
+	for id := range cursor.sent {
+		if id <= cursor.after {
+			delete(cursor.sent, id)
+		}
+	}
+	return cursor.after
+}
+
+// Send records that the notification is being sent, reporting false when it already was
+func (cursor *NotificationCursor) Send(id int64, now time.Time) bool {
+	if _, sent := cursor.sent[id]; sent || id <= cursor.after {
+		return false
+	}
+	cursor.sent[id] = now
+	return true
+}
+
+// MarkNotificationsRead marks the user's notifications read, all of them when ids is empty, and returns how
+// many changed
+func MarkNotificationsRead(exec Execer, userID interface{}, ids ...int64) (int64, error) {
+	query := "UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = ? AND read_at IS NULL"
+	args := []interface{}{userID}
+	if len(ids) > 0 {
+		query += " AND notification_id IN (?" + strings.Repeat(", ?", len(ids)-1) + ")"
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	}
+	result, err := exec.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("error marking notifications read: %v", err)
+	}
+	changed, _ := result.RowsAffected()
+	return changed, nil
+}
+
+// NotificationHub wakes the notification streams of a user when a notification is created in this process.
+// Notifications are created inside transactions and other replicas create them too, so streams still read
+// them from the database and poll every NotificationPollInterval; a wake-up only shortens the wait.
+type NotificationHub struct {
+	mu          sync.Mutex
+	subscribers map[int]map[chan struct{}]struct{}
+	closed      bool
+}
+
+// Notifications is the hub of this process
+var Notifications = &NotificationHub{subscribers: map[int]map[chan struct{}]struct{}{}}
+
+// Subscribe registers a stream of the user. The returned channel receives a value when the user may have new
+// notifications and is closed when the hub shuts down; call the returned function to unsubscribe.
+func (hub *NotificationHub) Subscribe(userID int) (<-chan struct{}, func()) {
+	wake := make(chan struct{}, 1)
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	if hub.closed {
+		close(wake)
+		return wake, func() {}
+	}
+	if hub.subscribers[userID] == nil {
+		hub.subscribers[userID] = map[chan struct{}]struct{}{}
+	}
+	hub.subscribers[userID][wake] = struct{}{}
+
+	return wake, func() {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		if _, ok := hub.subscribers[userID][wake]; ok {
+			delete(hub.subscribers[userID], wake)
+			if len(hub.subscribers[userID]) == 0 {
+				delete(hub.subscribers, userID)
+			}
+			close(wake)
+		}
+	}
+}
+
+// Wake signals every stream of the user without blocking
+func (hub *NotificationHub) Wake(userID int) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	for wake := range hub.subscribers[userID] {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Close ends every stream, for server shutdown
+func (hub *NotificationHub) Close() {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	hub.closed = true
+	for userID, subscribers := range hub.subscribers {
+		for wake := range subscribers {
+			close(wake)
+		}
+		delete(hub.subscribers, userID)
+	}
+}
+
+// NotificationPollInterval reads NOTIFICATION_POLL_INTERVAL (default 5s), how often streams check the database
+func NotificationPollInterval() time.Duration {
+	interval := 5 * time.Second
+	if value := os.Getenv("NOTIFICATION_POLL_INTERVAL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			interval = parsed
+		} else {
+			log.Printf("Warning: invalid NOTIFICATION_POLL_INTERVAL %q, using %v", value, interval)
+		}
+	}
+	return interval
+}
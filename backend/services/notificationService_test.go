@@ -0,0 +1,69 @@
+package services
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestNotificationCursor(t *testing.T) {
+	start := time.Date(2026, time.March, 15, 10, 0, 0, 0, time.UTC)
+	cursor := NewNotificationCursor(10)
+
+	// committed holds the IDs visible in the database; poll sends those above the cursor it has not sent yet
+	var committed []int64
+	poll := func(now time.Time) []int64 {
+		after := cursor.After(now)
+		sent := []int64{}
+		for _, id := range committed {
+			if id > after && cursor.Send(id, now) {
+				sent = append(sent, id)
+			}
+		}
+		return sent
+	}
+
+	steps := []struct {
+		name    string
+		at      time.Duration
+		commit  []int64
+		want    []int64
+		wantPos int64
+	}{
+		{name: "nothing new", at: 0, want: []int64{}, wantPos: 10},
+		// 11 and 12 are inserted in that order, but 12 commits first
+		{name: "higher ID commits first", at: time.Second, commit: []int64{12}, want: []int64{12}, wantPos: 10},
+		{name: "sent IDs are skipped", at: 2 * time.Second, want: []int64{}, wantPos: 10},
+		{name: "late lower ID is still sent", at: 5 * time.Second, commit: []int64{11}, want: []int64{11}, wantPos: 10},
+		{name: "new IDs after the late one", at: 6 * time.Second, commit: []int64{14, 13}, want: []int64{13, 14}, wantPos: 10},
+		{name: "moves past IDs sent before the grace period", at: time.Second + notificationCommitGrace, want: []int64{}, wantPos: 12},
+		{name: "then past every ID", at: 6*time.Second + notificationCommitGrace, want: []int64{}, wantPos: 14},
+	}
+
+	for _, step := range steps {
+		committed = append(committed, step.commit...)
+		sort.Slice(committed, func(i, j int) bool { return committed[i] < committed[j] })
+		now := start.Add(step.at)
+		if got := poll(now); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: sent %v, want %v", step.name, got, step.want)
+		}
+		if got := cursor.After(now); got != step.wantPos {
+			t.Errorf("%s: After() = %d, want %d", step.name, got, step.wantPos)
+		}
+	}
+	if len(cursor.sent) != 0 {
+		t.Errorf("cursor still tracks %v, want every sent ID released", cursor.sent)
+	}
+}
+
+func TestNotificationCursorSkipsIDsBelowTheStart(t *testing.T) {
+	cursor := NewNotificationCursor(10)
+	now := time.Now()
+	if cursor.Send(9, now) || cursor.Send(10, now) {
+		t.Error("Send() = true for an ID at or below the starting ID, want false")
+	}
+	if !cursor.Send(11, now) || cursor.Send(11, now) {
+		t.Error("Send() of a new ID twice, want true then false")
+	}
+}
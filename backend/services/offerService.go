@@ -0,0 +1,482 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OfferStatus is the state of an offer
+type OfferStatus string
+
+const (
+	// OfferStatusPending waits for the candidate until expires_at
+	OfferStatusPending   OfferStatus = "pending"
+	OfferStatusAccepted  OfferStatus = "accepted"
+	OfferStatusDeclined  OfferStatus = "declined"
+	OfferStatusRescinded OfferStatus = "rescinded"
+	// OfferStatusExpired is reported for pending offers past expires_at; it is never stored
+	OfferStatusExpired OfferStatus = "expired"
+)
+
+// OfferStatuses lists every OfferStatus
+var OfferStatuses = []OfferStatus{
+	OfferStatusPending, OfferStatusAccepted, OfferStatusDeclined, OfferStatusRescinded, OfferStatusExpired,
+}
+
+// ParseOfferStatus matches value case-insensitively against OfferStatuses
+func ParseOfferStatus(value string) (OfferStatus, bool) {
+	return parseEnum(value, OfferStatuses)
+}
+
+// offerStatusExpr is the reported status of offers o, turning pending offers past their expiry into expired
+const offerStatusExpr = "CASE WHEN o.status = 'pending' AND o.expires_at <= UTC_TIMESTAMP() THEN 'expired' ELSE o.status END"
+
+// OfferStatusCondition filters offers o by their reported status
+func OfferStatusCondition(status OfferStatus) (string, []interface{}) {
+	return "(" + offerStatusExpr + ") = ?", []interface{}{string(status)}
+}
+
+// Limits on offers
+const (
+	MaxOfferValidDays = 60
+	offerDateLayout   = "2006-01-02"
+)
+
+// Offer is an offer with the job and candidate it concerns. Times are RFC 3339 in UTC.
+type Offer struct {
+	ID              int         `json:"offer_id"`
+	ApplicationID   int         `json:"application_id"`
+	JobID           int         `json:"job_id"`
+	JobTitle        string      `json:"job_title"`
+	EmployerID      int         `json:"employer_id"`
+	CandidateUserID int         `json:"candidate_user_id"`
+	Salary          float64     `json:"salary"`
+	StartDate       string      `json:"start_date"`
+	ExpiresAt       string      `json:"expires_at"`
+	Note            *string     `json:"note"`
+	Status          OfferStatus `json:"status"`
+	ResponseNote    *string     `json:"response_note"`
+	RespondedAt     *string     `json:"responded_at"`
+	CreatedAt       string      `json:"created_at"`
+	UpdatedAt       string      `json:"updated_at"`
+}
+
+// OfferInput is an offer as sent by an employer
+type OfferInput struct {
+	Salary    float64 `json:"salary"`
+	StartDate string  `json:"start_date"`
+	ExpiresAt string  `json:"expires_at"`
+	Note      string  `json:"note"`
+}
+
+// Validate normalizes an offer, returning its expiry time and field errors. The salary range of the job is
+// checked by CreateOffer.
+func (input *OfferInput) Validate(now time.Time) (time.Time, FieldErrors) {
+	errs := FieldErrors{}
+	input.StartDate = strings.TrimSpace(input.StartDate)
+	input.Note = strings.TrimSpace(input.Note)
+
+	if input.Salary <= 0 {
+		errs["salary"] = "must be greater than 0"
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, strings.TrimSpace(input.ExpiresAt))
+	expiresAt = expiresAt.UTC().Truncate(time.Second)
+	switch {
+	case err != nil:
+		errs["expires_at"] = "must be an RFC 3339 time such as " + interviewTimeExample
+	case !expiresAt.After(now):
+		errs["expires_at"] = "must be in the future"
+	case expiresAt.After(now.AddDate(0, 0, MaxOfferValidDays)):
+		errs["expires_at"] = fmt.Sprintf("must be within %d days", MaxOfferValidDays)
+	}
+
+	startDate, err := time.Parse(offerDateLayout, input.StartDate)
+	switch {
+	case err != nil:
+		errs["start_date"] = "must be a date in YYYY-MM-DD format"
+	case startDate.Before(now.UTC().Truncate(24 * time.Hour)):
+		errs["start_date"] = "must not be in the past"
+	}
+	return expiresAt, errs
+}
+
+// Errors returned by the offer state changes
+var (
+	ErrOfferNotFound   = errors.New("offer not found")
+	ErrOfferNotPending = errors.New("offer has already been answered or rescinded")
+	ErrOfferExpired    = errors.New("offer has expired")
+	ErrOfferPending    = errors.New("application already has a pending offer")
+	ErrOfferInactive   = errors.New("application is no longer active")
+	ErrOfferJobFilled  = errors.New("job has no open positions left")
+)
+
+// OfferSalaryError reports a salary outside the job's advertised range
+type OfferSalaryError struct {
+	MinSalary, MaxSalary float64
+}
+
+func (err *OfferSalaryError) Error() string {
+	return fmt.Sprintf("salary must be between %.2f and %.2f", err.MinSalary, err.MaxSalary)
+}
+
+// OfferChange is the result of an offer state change, with what is needed to notify the other party
+type OfferChange struct {
+	OfferID         int
+	ApplicationID   int
+	JobID           int
+	JobTitle        string
+	EmployerID      int
+	CandidateUserID int
+	Status          OfferStatus
+	// PositionsRemaining is the job's openings left after an accepted offer
+	PositionsRemaining int
+	// JobFilled is set when accepting the offer filled the job's last position
+	JobFilled bool
+}
+
+// offerActive reports whether an application at status can still receive or accept an offer
+func offerActive(status ApplicationStatus) bool {
+	switch status {
+	case ApplicationStatusRejected, ApplicationStatusWithdrawn, ApplicationStatusHired:
+		return false
+	}
+	return true
+}
+
+// CreateOffer issues an offer for an application inside tx and moves the application to offered. The salary
+// must lie within the job's min_salary and max_salary, the job must have an open position, and an application
+// has at most one pending offer.
+func CreateOffer(tx *sql.Tx, applicationID, employerID interface{}, input OfferInput, expiresAt time.Time) (OfferChange, error) {
+	change := OfferChange{Status: OfferStatusPending}
+	change.EmployerID, _ = employerID.(int)
+	var applicationStatus ApplicationStatus
+	var minSalary, maxSalary float64
+	var positionsLeft int
+	query := "SELECT a.application_id, a.status, a.job_id, j.title, j.min_salary, j.max_salary, j.positions - j.positions_filled, f.user_id " +
+		"FROM applications a " +
+		"INNER JOIN jobs j ON j.job_id = a.job_id " +
+		"INNER JOIN freshgradprofiles f ON f.freshgradprofile_id = a.freshgradprofile_id " +
+		"WHERE a.application_id = ? FOR UPDATE"
+	err := tx.QueryRow(query, applicationID).Scan(&change.ApplicationID, &applicationStatus, &change.JobID,
+		&change.JobTitle, &minSalary, &maxSalary, &positionsLeft, &change.CandidateUserID)
+	if err == sql.ErrNoRows {
+		return change, ErrApplicationNotFound
+	}
+	if err != nil {
+		return change, fmt.Errorf("error loading application: %v", err)
+	}
+	if !offerActive(applicationStatus) {
+		return change, ErrOfferInactive
+	}
+	if positionsLeft <= 0 {
+		return change, ErrOfferJobFilled
+	}
+	if input.Salary < minSalary || input.Salary > maxSalary {
+		return change, &OfferSalaryError{MinSalary: minSalary, MaxSalary: maxSalary}
+	}
+
+	var pending bool
+	pendingCondition, pendingArgs := OfferStatusCondition(OfferStatusPending)
+	pendingQuery := "SELECT EXISTS (SELECT 1 FROM offers o WHERE o.application_id = ? AND " + pendingCondition + ")"
+	if err := tx.QueryRow(pendingQuery, append([]interface{}{change.ApplicationID}, pendingArgs...)...).Scan(&pending); err != nil {
+		return change, fmt.Errorf("error checking pending offers: %v", err)
+	}
+	if pending {
+		return change, ErrOfferPending
+	}
+
+	insertQuery := "INSERT INTO offers (application_id, employer_id, salary, start_date, expires_at, note, status) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	result, err := tx.Exec(insertQuery, change.ApplicationID, employerID, input.Salary, input.StartDate,
+		expiresAt.Format(InterviewTimeLayout), nullString(input.Note), string(OfferStatusPending))
+	if err != nil {
+		return change, fmt.Errorf("error creating offer: %v", err)
+	}
+	offerID, err := result.LastInsertId()
+	if err != nil {
+		return change, fmt.Errorf("error reading offer ID: %v", err)
+	}
+	change.OfferID = int(offerID)
+
+	if applicationStatus != ApplicationStatusOffered {
+		_, err = ChangeApplicationStatus(tx, change.ApplicationID, ApplicationStatusOffered, employerID, "Offer issued")
+	}
+	return change, err
+}
+
+// lockOffer loads a pending offer inside tx for update, with the status of its application
+func lockOffer(tx *sql.Tx, offerID interface{}) (OfferChange, ApplicationStatus, error) {
+	var change OfferChange
+	var applicationStatus ApplicationStatus
+	query := "SELECT o.offer_id, o.application_id, a.job_id, j.title, o.employer_id, f.user_id, " + offerStatusExpr + ", a.status " +
+		"FROM offers o " +
+		"INNER JOIN applications a ON a.application_id = o.application_id " +
+		"INNER JOIN jobs j ON j.job_id = a.job_id " +
+		"INNER JOIN freshgradprofiles f ON f.freshgradprofile_id = a.freshgradprofile_id " +
+		"WHERE o.offer_id = ? FOR UPDATE"
+	err := tx.QueryRow(query, offerID).Scan(&change.OfferID, &change.ApplicationID, &change.JobID, &change.JobTitle,
+		&change.EmployerID, &change.CandidateUserID, &change.Status, &applicationStatus)
+	if err == sql.ErrNoRows {
+		return change, "", ErrOfferNotFound
+	}
+	if err != nil {
+		return change, "", fmt.Errorf("error loading offer: %v", err)
+	}
+	switch change.Status {
+	case OfferStatusPending:
+		return change, applicationStatus, nil
+	case OfferStatusExpired:
+		return change, applicationStatus, ErrOfferExpired
+	}
+	return change, applicationStatus, ErrOfferNotPending
+}
+
+// setOfferStatus records the final status of an offer
+func setOfferStatus(tx *sql.Tx, change *OfferChange, status OfferStatus, note string) error {
+	query := "UPDATE offers SET status = ?, response_note = ?, responded_at = UTC_TIMESTAMP() WHERE offer_id = ?"
+	if _, err := tx.Exec(query, string(status), nullString(note), change.OfferID); err != nil {
+		return fmt.Errorf("error updating offer: %v", err)
+	}
+	change.Status = status
+	return nil
+}
+
+// AcceptOffer accepts a pending offer inside tx: the application becomes hired and the job fills one of its
+// positions, becoming filled when it was open and none remain. The job change is recorded in the audit log
+// with entry, whose action and target are filled in here.
+func AcceptOffer(tx *sql.Tx, offerID interface{}, note string, entry AuditEntry) (OfferChange, error) {
+	change, applicationStatus, err := lockOffer(tx, offerID)
+	if err != nil {
+		return change, err
+	}
+	if !offerActive(applicationStatus) {
+		return change, ErrOfferInactive
+	}
+	var positions, positionsFilled int
+	var jobStatus JobStatus
+	jobQuery := "SELECT positions, positions_filled, job_status FROM jobs WHERE job_id = ? FOR UPDATE"
+	if err := tx.QueryRow(jobQuery, change.JobID).Scan(&positions, &positionsFilled, &jobStatus); err != nil {
+		return change, fmt.Errorf("error loading job: %v", err)
+	}
+	if positionsFilled >= positions {
+		return change, ErrOfferJobFilled
+	}
+
+	if err := setOfferStatus(tx, &change, OfferStatusAccepted, note); err != nil {
+		return change, err
+	}
+	if _, err := ChangeApplicationStatus(tx, change.ApplicationID, ApplicationStatusHired, nil, "Offer accepted"); err != nil {
+		return change, err
+	}
+
+	change.PositionsRemaining = positions - positionsFilled - 1
+	change.JobFilled = change.PositionsRemaining == 0 && jobStatus == JobStatusOpen
+	updateQuery := "UPDATE jobs SET positions_filled = positions_filled + 1, job_status = ? WHERE job_id = ?"
+	if change.JobFilled {
+		jobStatus = JobStatusFilled
+	}
+	entry.Action, entry.TargetType, entry.TargetID = "job.fill", "job", fmt.Sprint(change.JobID)
+	if err := ExecWithAuditTx(tx, entry, JobSnapshotQuery, updateQuery, string(jobStatus), change.JobID); err != nil {
+		return change, fmt.Errorf("error filling job position: %v", err)
+	}
+	return change, nil
+}
+
+// DeclineOffer declines a pending offer inside tx; the application stays offered so the employer may make
+// another offer or reject it
+func DeclineOffer(tx *sql.Tx, offerID interface{}, note string) (OfferChange, error) {
+	change, _, err := lockOffer(tx, offerID)
+	if err != nil {
+		return change, err
+	}
+	return change, setOfferStatus(tx, &change, OfferStatusDeclined, note)
+}
+
+// RescindOffer withdraws a pending offer inside tx
+func RescindOffer(tx *sql.Tx, offerID interface{}, reason string) (OfferChange, error) {
+	change, _, err := lockOffer(tx, offerID)
+	if err != nil {
+		return change, err
+	}
+	return change, setOfferStatus(tx, &change, OfferStatusRescinded, reason)
+}
+
+// offerColumns selects an Offer from offers o joined to applications a, jobs j and freshgradprofiles f
+const offerColumns = "o.offer_id, o.application_id, a.job_id, j.title, o.employer_id, f.user_id, o.salary, o.start_date, " +
+	"o.expires_at, o.note, " + offerStatusExpr + ", o.response_note, o.responded_at, o.created_at, o.updated_at"
+
+// OfferFrom is the FROM clause matching offerColumns, for conditions passed to LoadOffers
+const OfferFrom = "FROM offers o " +
+	"INNER JOIN applications a ON a.application_id = o.application_id " +
+	"INNER JOIN jobs j ON j.job_id = a.job_id " +
+	"INNER JOIN freshgradprofiles f ON f.freshgradprofile_id = a.freshgradprofile_id "
+
+// LoadOffers reads the offers matching condition, newest first
+func LoadOffers(q Querier, condition string, args ...interface{}) ([]Offer, error) {
+	query := "SELECT " + offerColumns + " " + OfferFrom + "WHERE " + condition + " ORDER BY o.created_at DESC, o.offer_id DESC"
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying offers: %v", err)
+	}
+	defer rows.Close()
+
+	offers := []Offer{}
+	for rows.Next() {
+		var offer Offer
+		if err := rows.Scan(&offer.ID, &offer.ApplicationID, &offer.JobID, &offer.JobTitle, &offer.EmployerID,
+			&offer.CandidateUserID, &offer.Salary, &offer.StartDate, &offer.ExpiresAt, &offer.Note, &offer.Status,
+			&offer.ResponseNote, &offer.RespondedAt, &offer.CreatedAt, &offer.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning offer: %v", err)
+		}
+		offer.ExpiresAt = formatInterviewTime(offer.ExpiresAt)
+		if offer.RespondedAt != nil {
+			respondedAt := formatInterviewTime(*offer.RespondedAt)
+			offer.RespondedAt = &respondedAt
+		}
+		offers = append(offers, offer)
+	}
+	return offers, rows.Err()
+}
+
+// HireAnalytics summarizes offers and hires for the admin dashboard
+type HireAnalytics struct {
+	Offers map[OfferStatus]int `json:"offers"`
+	Hires  int                 `json:"hires"`
+	// AcceptanceRate is accepted offers over answered (accepted or declined) offers; rescinded offers, including
+	// those of withdrawn applications, are left out
+	AcceptanceRate *float64 `json:"acceptance_rate"`
+	AverageSalary  *float64 `json:"average_salary"`
+	// AverageDaysToHire runs from the application to the accepted offer
+	AverageDaysToHire *float64          `json:"average_days_to_hire"`
+	ByMonth           []HireMonth       `json:"by_month"`
+	ByCategory        []HireJobCategory `json:"by_category"`
+}
+
+// HireMonth counts the hires of a month (YYYY-MM)
+type HireMonth struct {
+	Month string `json:"month"`
+	Hires int    `json:"hires"`
+}
+
+// HireJobCategory counts the hires of a job category
+type HireJobCategory struct {
+	JobCategory   string  `json:"job_category"`
+	Hires         int     `json:"hires"`
+	AverageSalary float64 `json:"average_salary"`
+}
+
+// LoadHireAnalytics aggregates the offers created, and hires made, between from and to (UTC dates as
+// YYYY-MM-DD, inclusive, either may be empty)
+func LoadHireAnalytics(q Querier, from, to string) (HireAnalytics, error) {
+	analytics := HireAnalytics{Offers: map[OfferStatus]int{}, ByMonth: []HireMonth{}, ByCategory: []HireJobCategory{}}
+	for _, status := range OfferStatuses {
+		analytics.Offers[status] = 0
+	}
+
+	// Offers are counted by creation date and hires by acceptance date, both in UTC
+	offerCondition, offerArgs := hireAnalyticsWindow(utcTimestamp("o.created_at"), from, to)
+	rows, err := q.Query("SELECT "+offerStatusExpr+", COUNT(*) FROM offers o WHERE "+offerCondition+" GROUP BY 1", offerArgs...)
+	if err != nil {
+		return analytics, fmt.Errorf("error counting offers: %v", err)
+	}
+	for rows.Next() {
+		var status OfferStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			rows.Close()
+			return analytics, fmt.Errorf("error scanning offer count: %v", err)
+		}
+		analytics.Offers[status] = count
+	}
+	rows.Close()
+	if answered := analytics.Offers[OfferStatusAccepted] + analytics.Offers[OfferStatusDeclined]; answered > 0 {
+		rate := float64(analytics.Offers[OfferStatusAccepted]) / float64(answered)
+		analytics.AcceptanceRate = &rate
+	}
+
+	hireCondition, hireArgs := hireAnalyticsWindow("o.responded_at", from, to)
+	hireCondition = "o.status = 'accepted' AND " + hireCondition
+	hireFrom := "FROM offers o INNER JOIN applications a ON a.application_id = o.application_id " +
+		"INNER JOIN jobs j ON j.job_id = a.job_id WHERE " + hireCondition
+
+	query := "SELECT COUNT(*), AVG(o.salary), AVG(TIMESTAMPDIFF(HOUR, " + utcTimestamp("a.applied_at") + ", o.responded_at)) / 24 " + hireFrom
+	if err := q.QueryRow(query, hireArgs...).Scan(&analytics.Hires, &analytics.AverageSalary, &analytics.AverageDaysToHire); err != nil {
+		return analytics, fmt.Errorf("error summarizing hires: %v", err)
+	}
+
+	rows, err = q.Query("SELECT DATE_FORMAT(o.responded_at, '%Y-%m'), COUNT(*) "+hireFrom+" GROUP BY 1 ORDER BY 1", hireArgs...)
+	if err != nil {
+		return analytics, fmt.Errorf("error counting hires by month: %v", err)
+	}
+	for rows.Next() {
+		var month HireMonth
+		if err := rows.Scan(&month.Month, &month.Hires); err != nil {
+			rows.Close()
+			return analytics, fmt.Errorf("error scanning hires by month: %v", err)
+		}
+		analytics.ByMonth = append(analytics.ByMonth, month)
+	}
+	rows.Close()
+
+	rows, err = q.Query("SELECT j.job_category, COUNT(*), AVG(o.salary) "+hireFrom+" GROUP BY j.job_category ORDER BY 2 DESC, 1", hireArgs...)
+	if err != nil {
+		return analytics, fmt.Errorf("error counting hires by category: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var category HireJobCategory
+		if err := rows.Scan(&category.JobCategory, &category.Hires, &category.AverageSalary); err != nil {
+			return analytics, fmt.Errorf("error scanning hires by category: %v", err)
+		}
+		analytics.ByCategory = append(analytics.ByCategory, category)
+	}
+	return analytics, rows.Err()
+}
+
+// hireAnalyticsWindow is the condition keeping the UTC times of expr between from and to (YYYY-MM-DD,
+// inclusive, either may be empty)
+func hireAnalyticsWindow(expr, from, to string) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if from != "" {
+		conditions = append(conditions, expr+" >= ?")
+		args = append(args, from)
+	}
+	if to != "" {
+		conditions = append(conditions, expr+" < DATE_ADD(?, INTERVAL 1 DAY)")
+		args = append(args, to)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// utcTimestamp converts a TIMESTAMP column, which reads back in the session time zone, to UTC so that it can
+// be compared with the DATETIME columns written with UTC_TIMESTAMP()
+func utcTimestamp(column string) string {
+	return "CONVERT_TZ(" + column + ", @@session.time_zone, '+00:00')"
+}
+
+// RespondOfferError writes the response for a rejected offer change and reports whether err was one
+func RespondOfferError(c *gin.Context, err error) bool {
+	var salary *OfferSalaryError
+	switch {
+	case errors.As(err, &salary):
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": FieldErrors{"salary": strings.TrimPrefix(err.Error(), "salary ")}})
+	case errors.Is(err, ErrOfferNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Offer not found"})
+	case errors.Is(err, ErrOfferNotPending),
+		errors.Is(err, ErrOfferExpired),
+		errors.Is(err, ErrOfferPending),
+		errors.Is(err, ErrOfferInactive),
+		errors.Is(err, ErrOfferJobFilled):
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
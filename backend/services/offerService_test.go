@@ -0,0 +1,45 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHireAnalyticsWindow(t *testing.T) {
+	tests := []struct {
+		name      string
+		expr      string
+		from, to  string
+		condition string
+		args      []interface{}
+	}{
+		{name: "unbounded", expr: "o.responded_at", condition: "1 = 1"},
+		{name: "from", expr: "o.responded_at", from: "2026-01-01",
+			condition: "1 = 1 AND o.responded_at >= ?", args: []interface{}{"2026-01-01"}},
+		{name: "to includes the whole day", expr: "o.responded_at", to: "2026-01-31",
+			condition: "1 = 1 AND o.responded_at < DATE_ADD(?, INTERVAL 1 DAY)", args: []interface{}{"2026-01-31"}},
+		{name: "both bounds on a converted timestamp", expr: utcTimestamp("o.created_at"), from: "2026-01-01", to: "2026-01-31",
+			condition: "1 = 1 AND CONVERT_TZ(o.created_at, @@session.time_zone, '+00:00') >= ? " +
+				"AND CONVERT_TZ(o.created_at, @@session.time_zone, '+00:00') < DATE_ADD(?, INTERVAL 1 DAY)",
+			args: []interface{}{"2026-01-01", "2026-01-31"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			condition, args := hireAnalyticsWindow(test.expr, test.from, test.to)
+			if condition != test.condition {
+				t.Errorf("hireAnalyticsWindow() condition = %q, want %q", condition, test.condition)
+			}
+			if !reflect.DeepEqual(args, test.args) {
+				t.Errorf("hireAnalyticsWindow() args = %v, want %v", args, test.args)
+			}
+		})
+	}
+}
+
+func TestUTCTimestamp(t *testing.T) {
+	want := "CONVERT_TZ(a.applied_at, @@session.time_zone, '+00:00')"
+	if got := utcTimestamp("a.applied_at"); got != want {
+		t.Errorf("utcTimestamp() = %q, want %q", got, want)
+	}
+}
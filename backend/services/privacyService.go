@@ -0,0 +1,311 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Privacy: fresh grads choose who may find their profile outside their applications, block companies from
+// finding or contacting them, and decide which employers see their contact details beyond the applications
+// that have reached a contact status.
+
+// ProfileVisibility is who can find a fresh grad's profile outside their applications
+type ProfileVisibility string
+
+const (
+	// ProfileVisibilityPrivate shows the profile only through the applications it was submitted with
+	ProfileVisibilityPrivate ProfileVisibility = "private"
+	// ProfileVisibilityApplied also shows it to the teams of the jobs the fresh grad applied to
+	ProfileVisibilityApplied ProfileVisibility = "applied"
+	// ProfileVisibilitySearchable shows it to every approved employer
+	ProfileVisibilitySearchable ProfileVisibility = "searchable"
+)
+
+// ProfileVisibilities lists every ProfileVisibility
+var ProfileVisibilities = []ProfileVisibility{ProfileVisibilityPrivate, ProfileVisibilityApplied, ProfileVisibilitySearchable}
+
+// ParseProfileVisibility matches value case-insensitively against ProfileVisibilities
+func ParseProfileVisibility(value string) (ProfileVisibility, bool) {
+	return parseEnum(value, ProfileVisibilities)
+}
+
+// ProfileVisibilityMessage is the validation error for an unknown visibility
+var ProfileVisibilityMessage = enumMessage(ProfileVisibilities)
+
+// ContactRequestStatus is the state of a contact request
+type ContactRequestStatus string
+
+const (
+	ContactRequestStatusPending  ContactRequestStatus = "pending"
+	ContactRequestStatusAccepted ContactRequestStatus = "accepted"
+	ContactRequestStatusDeclined ContactRequestStatus = "declined"
+)
+
+// ContactRequestStatuses lists every ContactRequestStatus
+var ContactRequestStatuses = []ContactRequestStatus{
+	ContactRequestStatusPending, ContactRequestStatusAccepted, ContactRequestStatusDeclined,
+}
+
+// ParseContactRequestStatus matches value case-insensitively against ContactRequestStatuses
+func ParseContactRequestStatus(value string) (ContactRequestStatus, bool) {
+	return parseEnum(value, ContactRequestStatuses)
+}
+
+// Limits on contact requests
+const (
+	MaxContactMessageLength = 1000
+	// ContactRequestCooldownDays is how long a team waits after a declined request before asking again
+	ContactRequestCooldownDays = 30
+)
+
+// Errors returned by company blocks and contact requests
+var (
+	ErrCompanyNotFound          = errors.New("company not found")
+	ErrContactRequestNotFound   = errors.New("contact request not found")
+	ErrContactRequestNotPending = errors.New("contact request has already been answered")
+	ErrContactRequestPending    = errors.New("a contact request is already waiting for the candidate")
+	ErrContactRequestDeclined   = fmt.Errorf("the candidate declined a contact request in the last %d days", ContactRequestCooldownDays)
+	ErrContactShared            = errors.New("the candidate already shares their contact details with you")
+)
+
+// ContactRequest is a request to see a fresh grad's contact details. Times are RFC 3339 in UTC.
+type ContactRequest struct {
+	ID                 int                  `json:"contact_request_id"`
+	FreshGradProfileID int                  `json:"fresh_grad_profile_id"`
+	EmployerID         int                  `json:"employer_id"`
+	CompanyID          *int                 `json:"company_id"`
+	CompanyName        *string              `json:"company_name"`
+	JobID              *int                 `json:"job_id"`
+	JobTitle           *string              `json:"job_title"`
+	Message            *string              `json:"message"`
+	Status             ContactRequestStatus `json:"status"`
+	CreatedAt          string               `json:"created_at"`
+	RespondedAt        *string              `json:"responded_at"`
+}
+
+// contactRequestColumns are the columns scanned into ContactRequest
+const contactRequestColumns = "cr.contact_request_id, cr.freshgradprofile_id, cr.employer_id, cr.company_id, co.name, " +
+	"cr.job_id, j.title, cr.message, cr.status, cr.created_at, cr.responded_at"
+
+// ContactRequestFrom is the FROM clause matching contactRequestColumns, for conditions passed to LoadContactRequests
+const ContactRequestFrom = "FROM contact_requests cr " +
+	"LEFT JOIN companies co ON co.company_id = cr.company_id " +
+	"LEFT JOIN jobs j ON j.job_id = cr.job_id "
+
+// LoadContactRequests reads the contact requests matching condition, newest first
+func LoadContactRequests(q Querier, condition string, args ...interface{}) ([]ContactRequest, error) {
+	query := "SELECT " + contactRequestColumns + " " + ContactRequestFrom + "WHERE " + condition +
+		" ORDER BY cr.created_at DESC, cr.contact_request_id DESC"
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying contact requests: %v", err)
+	}
+	defer rows.Close()
+
+	requests := []ContactRequest{}
+	for rows.Next() {
+		var request ContactRequest
+		if err := rows.Scan(&request.ID, &request.FreshGradProfileID, &request.EmployerID, &request.CompanyID,
+			&request.CompanyName, &request.JobID, &request.JobTitle, &request.Message, &request.Status,
+			&request.CreatedAt, &request.RespondedAt); err != nil {
+			return nil, fmt.Errorf("error scanning contact request: %v", err)
+		}
+		request.CreatedAt = formatInterviewTime(request.CreatedAt)
+		if request.RespondedAt != nil {
+			respondedAt := formatInterviewTime(*request.RespondedAt)
+			request.RespondedAt = &respondedAt
+		}
+		requests = append(requests, request)
+	}
+	return requests, rows.Err()
+}
+
+// ContactRequestInput is a new contact request; CompanyID is the requester's company, nil when they have none
+type ContactRequestInput struct {
+	ProfileID  int
+	EmployerID int
+	CompanyID  *int
+	JobID      *int
+	Message    string
+}
+
+// CreateContactRequest records a contact request inside tx and notifies the fresh grad. A team has at most one
+// pending request per fresh grad, cannot ask once the fresh grad has accepted, and waits
+// ContactRequestCooldownDays after a decline.
+func CreateContactRequest(tx *sql.Tx, input ContactRequestInput) (int64, error) {
+	scopeCondition, scopeArgs := "company_id IS NULL AND employer_id = ?", []interface{}{input.EmployerID}
+	if input.CompanyID != nil {
+		scopeCondition, scopeArgs = "company_id = ?", []interface{}{*input.CompanyID}
+	}
+	query := "SELECT status, COALESCE(responded_at > UTC_TIMESTAMP() - INTERVAL ? DAY, FALSE) FROM contact_requests " +
+		"WHERE freshgradprofile_id = ? AND " + scopeCondition + " ORDER BY created_at DESC, contact_request_id DESC LIMIT 1 FOR UPDATE"
+	var status ContactRequestStatus
+	var recent bool
+	err := tx.QueryRow(query, append([]interface{}{ContactRequestCooldownDays, input.ProfileID}, scopeArgs...)...).Scan(&status, &recent)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return 0, fmt.Errorf("error checking contact requests: %v", err)
+	case status == ContactRequestStatusPending:
+		return 0, ErrContactRequestPending
+	case status == ContactRequestStatusAccepted:
+		return 0, ErrContactShared
+	case recent:
+		return 0, ErrContactRequestDeclined
+	}
+
+	var message interface{}
+	if input.Message != "" {
+		message = input.Message
+	}
+	result, err := tx.Exec("INSERT INTO contact_requests (freshgradprofile_id, employer_id, company_id, job_id, message, created_at) "+
+		"VALUES (?, ?, ?, ?, ?, UTC_TIMESTAMP())",
+		input.ProfileID, input.EmployerID, input.CompanyID, input.JobID, message)
+	if err != nil {
+		return 0, fmt.Errorf("error saving contact request: %v", err)
+	}
+	requestID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error reading contact request ID: %v", err)
+	}
+
+	var candidateUserID int
+	var companyName sql.NullString
+	query = "SELECT f.user_id, co.name FROM freshgradprofiles f LEFT JOIN companies co ON co.company_id = ? " +
+		"WHERE f.freshgradprofile_id = ?"
+	if err := tx.QueryRow(query, input.CompanyID, input.ProfileID).Scan(&candidateUserID, &companyName); err != nil {
+		return 0, fmt.Errorf("error loading candidate: %v", err)
+	}
+	requester := "An employer"
+	if companyName.Valid {
+		requester = companyName.String
+	}
+	data := map[string]interface{}{"contact_request_id": requestID, "company_id": input.CompanyID, "job_id": input.JobID}
+	if err := CreateNotification(tx, candidateUserID, "contact.requested", requester+" would like to contact you", data); err != nil {
+		return 0, err
+	}
+	return requestID, nil
+}
+
+// ContactRequestChange is the result of answering a contact request, with what is needed to notify the requester
+type ContactRequestChange struct {
+	RequestID  int
+	EmployerID int
+	Status     ContactRequestStatus
+}
+
+// AnswerContactRequest accepts or declines a pending contact request to the profile inside tx
+func AnswerContactRequest(tx *sql.Tx, requestID interface{}, profileID int, accept bool) (ContactRequestChange, error) {
+	var change ContactRequestChange
+	query := "SELECT contact_request_id, employer_id, status FROM contact_requests " +
+		"WHERE contact_request_id = ? AND freshgradprofile_id = ? FOR UPDATE"
+	err := tx.QueryRow(query, requestID, profileID).Scan(&change.RequestID, &change.EmployerID, &change.Status)
+	if err == sql.ErrNoRows {
+		return change, ErrContactRequestNotFound
+	}
+	if err != nil {
+		return change, fmt.Errorf("error loading contact request: %v", err)
+	}
+	if change.Status != ContactRequestStatusPending {
+		return change, ErrContactRequestNotPending
+	}
+
+	change.Status = ContactRequestStatusDeclined
+	if accept {
+		change.Status = ContactRequestStatusAccepted
+	}
+	if _, err := tx.Exec("UPDATE contact_requests SET status = ?, responded_at = UTC_TIMESTAMP() WHERE contact_request_id = ?",
+		string(change.Status), change.RequestID); err != nil {
+		return change, fmt.Errorf("error updating contact request: %v", err)
+	}
+	return change, nil
+}
+
+// BlockedCompany is a company a fresh grad has blocked
+type BlockedCompany struct {
+	CompanyID int    `json:"company_id"`
+	Name      string `json:"name"`
+	BlockedAt string `json:"blocked_at"`
+}
+
+// LoadBlockedCompanies reads the companies the profile has blocked, most recent first
+func LoadBlockedCompanies(q Querier, profileID int) ([]BlockedCompany, error) {
+	query := "SELECT co.company_id, co.name, b.created_at FROM profile_blocked_companies b " +
+		"INNER JOIN companies co ON co.company_id = b.company_id " +
+		"WHERE b.freshgradprofile_id = ? ORDER BY b.created_at DESC, co.name"
+	rows, err := q.Query(query, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying blocked companies: %v", err)
+	}
+	defer rows.Close()
+
+	companies := []BlockedCompany{}
+	for rows.Next() {
+		var company BlockedCompany
+		if err := rows.Scan(&company.CompanyID, &company.Name, &company.BlockedAt); err != nil {
+			return nil, fmt.Errorf("error scanning blocked company: %v", err)
+		}
+		company.BlockedAt = formatInterviewTime(company.BlockedAt)
+		companies = append(companies, company)
+	}
+	return companies, rows.Err()
+}
+
+// BlockCompany blocks a company from finding or contacting the profile inside tx, declining its pending contact
+// requests. Contact details the fresh grad shared before stay hidden from the company while it is blocked.
+func BlockCompany(tx *sql.Tx, profileID int, companyID interface{}) error {
+	var exists bool
+	if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM companies WHERE company_id = ?)", companyID).Scan(&exists); err != nil {
+		return fmt.Errorf("error checking company: %v", err)
+	}
+	if !exists {
+		return ErrCompanyNotFound
+	}
+
+	if _, err := tx.Exec("INSERT IGNORE INTO profile_blocked_companies (freshgradprofile_id, company_id, created_at) VALUES (?, ?, UTC_TIMESTAMP())",
+		profileID, companyID); err != nil {
+		return fmt.Errorf("error blocking company: %v", err)
+	}
+	if _, err := tx.Exec("UPDATE contact_requests SET status = ?, responded_at = UTC_TIMESTAMP() "+
+		"WHERE freshgradprofile_id = ? AND company_id = ? AND status = ?",
+		string(ContactRequestStatusDeclined), profileID, companyID, string(ContactRequestStatusPending)); err != nil {
+		return fmt.Errorf("error declining contact requests: %v", err)
+	}
+	return nil
+}
+
+// UnblockCompany lifts a block, reporting whether the company was blocked
+func UnblockCompany(exec Execer, profileID int, companyID interface{}) (bool, error) {
+	result, err := exec.Exec("DELETE FROM profile_blocked_companies WHERE freshgradprofile_id = ? AND company_id = ?", profileID, companyID)
+	if err != nil {
+		return false, fmt.Errorf("error unblocking company: %v", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error reading unblock result: %v", err)
+	}
+	return affected > 0, nil
+}
+
+// RespondPrivacyError writes the response for the company block and contact request errors, reporting whether
+// err was one of them
+func RespondPrivacyError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, ErrCompanyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Company not found"})
+	case errors.Is(err, ErrContactRequestNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Contact request not found"})
+	case errors.Is(err, ErrContactRequestNotPending),
+		errors.Is(err, ErrContactRequestPending),
+		errors.Is(err, ErrContactRequestDeclined),
+		errors.Is(err, ErrContactShared):
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
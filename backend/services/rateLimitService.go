@@ -0,0 +1,68 @@
+package services
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow counts requests from one client in the current fixed window
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimitMiddleware limits each client IP to limit requests per window, responding 429 when exceeded
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	clients := make(map[string]*rateWindow)
+	lastSweep := time.Now()
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		ip := c.ClientIP()
+
+		mu.Lock()
+		// Drop windows that have expired so the map doesn't grow without bound
+		if now.Sub(lastSweep) > window {
+			for key, w := range clients {
+				if now.Sub(w.start) >= window {
+					delete(clients, key)
+				}
+			}
+			lastSweep = now
+		}
+
+		w, ok := clients[ip]
+		if !ok || now.Sub(w.start) >= window {
+			w = &rateWindow{start: now}
+			clients[ip] = w
+		}
+		w.count++
+		count := w.count
+		reset := w.start.Add(window)
+		mu.Unlock()
+
+		remaining := limit - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+		if count > limit {
+			log.Printf("Rate limit exceeded for %s on %s", ip, c.Request.URL.Path)
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(reset).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"status": "error", "message": "Too many requests"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
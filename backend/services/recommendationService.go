@@ -0,0 +1,281 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Weights of each match component in the total score; they sum to 1
+const (
+	matchWeightSkills     = 0.40
+	matchWeightExperience = 0.20
+	matchWeightLocation   = 0.15
+	matchWeightSalary     = 0.15
+	matchWeightLevel      = 0.10
+)
+
+// neutralMatchScore is used when the profile or job lacks the data to compare
+const neutralMatchScore = 0.5
+
+// MaxSuggestedCandidatePool caps how many profiles are loaded and scored for one job's suggested candidates
+const MaxSuggestedCandidatePool = 500
+
+// CandidateProfile is the part of a fresh grad profile used for matching; nil preferences are unset
+type CandidateProfile struct {
+	ProfileID         int
+	UserID            int
+	SkillIDs          []int
+	ExperienceYears   *int
+	PreferredLocation *string
+	ExpectedSalary    *float64
+	PreferredJobLevel *string
+}
+
+// JobRequirements is the part of a job used for matching
+type JobRequirements struct {
+	JobID         int
+	SkillIDs      []int
+	MinExperience int
+	MaxExperience int
+	Location      string
+	MinSalary     float64
+	MaxSalary     float64
+	JobLevel      string
+}
+
+// MatchComponent is one explained part of a match score, scored from 0 to 1
+type MatchComponent struct {
+	Score  float64 `json:"score"`
+	Weight float64 `json:"weight"`
+	Reason string  `json:"reason"`
+}
+
+// MatchScore is a 0–100 score of how well a candidate fits a job, with the breakdown that produced it
+type MatchScore struct {
+	Total     float64                   `json:"total"`
+	Breakdown map[string]MatchComponent `json:"breakdown"`
+}
+
+// CandidateProfileColumns are the freshgradprofiles columns read by ScanCandidateProfile, for alias f
+const CandidateProfileColumns = "f.freshgradprofile_id, f.user_id, f.experience_years, f.preferred_location, " +
+	"f.expected_salary, f.preferred_job_level"
+
+// JobRequirementsColumns are the jobs columns read by ScanJobRequirements, for alias j
+const JobRequirementsColumns = "j.job_id, j.min_experience, j.max_experience, j.location, j.min_salary, j.max_salary, j.job_level"
+
+// ScanTargets returns pointers to the profile's fields in CandidateProfileColumns order
+func (profile *CandidateProfile) ScanTargets() []interface{} {
+	return []interface{}{
+		&profile.ProfileID, &profile.UserID, &profile.ExperienceYears, &profile.PreferredLocation,
+		&profile.ExpectedSalary, &profile.PreferredJobLevel,
+	}
+}
+
+// ScanTargets returns pointers to the job's fields in JobRequirementsColumns order
+func (job *JobRequirements) ScanTargets() []interface{} {
+	return []interface{}{
+		&job.JobID, &job.MinExperience, &job.MaxExperience, &job.Location, &job.MinSalary, &job.MaxSalary, &job.JobLevel,
+	}
+}
+
+// ScoreMatch scores a candidate against a job
+func ScoreMatch(profile CandidateProfile, job JobRequirements) MatchScore {
+	breakdown := map[string]MatchComponent{
+		"skills":     scoreSkills(profile.SkillIDs, job.SkillIDs),
+		"experience": scoreExperience(profile.ExperienceYears, job.MinExperience, job.MaxExperience),
+		"location":   scoreLocation(profile.PreferredLocation, job.Location),
+		"salary":     scoreSalary(profile.ExpectedSalary, job.MinSalary, job.MaxSalary),
+		"job_level":  scoreJobLevel(profile.PreferredJobLevel, job.JobLevel),
+	}
+
+	var total float64
+	for _, component := range breakdown {
+		total += component.Score * component.Weight
+	}
+	return MatchScore{Total: math.Round(total*1000) / 10, Breakdown: breakdown}
+}
+
+// scoreSkills is the share of the job's skills the candidate has
+func scoreSkills(candidate, required []int) MatchComponent {
+	component := MatchComponent{Weight: matchWeightSkills}
+	if len(required) == 0 {
+		component.Score = neutralMatchScore
+		component.Reason = "job lists no catalogue skills"
+		return component
+	}
+
+	has := make(map[int]bool, len(candidate))
+	for _, id := range candidate {
+		has[id] = true
+	}
+	matched := 0
+	for _, id := range required {
+		if has[id] {
+			matched++
+		}
+	}
+	component.Score = roundScore(float64(matched) / float64(len(required)))
+	component.Reason = fmt.Sprintf("has %d of %d required skills", matched, len(required))
+	return component
+}
+
+// scoreExperience is full inside the job's range, drops a quarter per missing year and slightly when overqualified
+func scoreExperience(years *int, minYears, maxYears int) MatchComponent {
+	component := MatchComponent{Weight: matchWeightExperience}
+	switch {
+	case years == nil:
+		component.Score = neutralMatchScore
+		component.Reason = "experience not set on profile"
+	case *years < minYears:
+		component.Score = roundScore(math.Max(0, 1-0.25*float64(minYears-*years)))
+		component.Reason = fmt.Sprintf("%d years of experience, job asks for at least %d", *years, minYears)
+	case *years > maxYears:
+		component.Score = 0.75
+		component.Reason = fmt.Sprintf("%d years of experience, above the job's %d", *years, maxYears)
+	default:
+		component.Score = 1
+		component.Reason = fmt.Sprintf("%d years of experience is within %d–%d", *years, minYears, maxYears)
+	}
+	return component
+}
+
+// scoreLocation is full for the same or a remote location and partial when one location contains the other
+func scoreLocation(preferred *string, location string) MatchComponent {
+	component := MatchComponent{Weight: matchWeightLocation}
+	jobLocation := strings.ToLower(strings.TrimSpace(location))
+	if preferred == nil || strings.TrimSpace(*preferred) == "" {
+		component.Score = neutralMatchScore
+		component.Reason = "preferred location not set on profile"
+		return component
+	}
+	if jobLocation == "" {
+		component.Score = neutralMatchScore
+		component.Reason = "job has no location"
+		return component
+	}
+
+	wanted := strings.ToLower(strings.TrimSpace(*preferred))
+	switch {
+	case wanted == jobLocation:
+		component.Score = 1
+		component.Reason = "job is in the preferred location"
+	case strings.Contains(jobLocation, "remote"):
+		component.Score = 1
+		component.Reason = "job is remote"
+	case strings.Contains(jobLocation, wanted) || strings.Contains(wanted, jobLocation):
+		component.Score = 0.75
+		component.Reason = "job location overlaps the preferred location"
+	default:
+		component.Score = 0
+		component.Reason = fmt.Sprintf("job is in %s, not %s", location, *preferred)
+	}
+	return component
+}
+
+// scoreSalary is full when the expectation fits under the job's maximum and falls with the shortfall otherwise
+func scoreSalary(expected *float64, minSalary, maxSalary float64) MatchComponent {
+	component := MatchComponent{Weight: matchWeightSalary}
+	switch {
+	case expected == nil:
+		component.Score = neutralMatchScore
+		component.Reason = "salary expectation not set on profile"
+	case *expected <= maxSalary:
+		component.Score = 1
+		component.Reason = fmt.Sprintf("expected salary %.0f fits the range %.0f–%.0f", *expected, minSalary, maxSalary)
+	case maxSalary <= 0:
+		component.Score = 0
+		component.Reason = "job has no salary range"
+	default:
+		component.Score = roundScore(math.Max(0, 1-(*expected-maxSalary)/maxSalary))
+		component.Reason = fmt.Sprintf("expected salary %.0f is above the maximum %.0f", *expected, maxSalary)
+	}
+	return component
+}
+
+// scoreJobLevel is full for the preferred level and half for an adjacent one
+func scoreJobLevel(preferred *string, level string) MatchComponent {
+	component := MatchComponent{Weight: matchWeightLevel}
+	if preferred == nil || *preferred == "" {
+		component.Score = neutralMatchScore
+		component.Reason = "preferred job level not set on profile"
+		return component
+	}
+
+	preferredIndex, levelIndex := jobLevelIndex(*preferred), jobLevelIndex(level)
+	if preferredIndex < 0 || levelIndex < 0 {
+		component.Score = neutralMatchScore
+		component.Reason = "job level not recognised"
+		return component
+	}
+	distance := preferredIndex - levelIndex
+	switch {
+	case distance == 0:
+		component.Score = 1
+		component.Reason = "job is at the preferred level " + level
+	case distance == 1 || distance == -1:
+		component.Score = 0.5
+		component.Reason = fmt.Sprintf("job is %s, one level from the preferred %s", level, *preferred)
+	default:
+		component.Score = 0
+		component.Reason = fmt.Sprintf("job is %s, far from the preferred %s", level, *preferred)
+	}
+	return component
+}
+
+// jobLevelIndex is the position of level in JobLevels, or -1
+func jobLevelIndex(level string) int {
+	for i, candidate := range JobLevels {
+		if strings.EqualFold(level, string(candidate)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// roundScore rounds a component score to two decimals
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
+
+// SkillIDsByOwner loads the skill IDs linked to each owner, e.g. SkillIDsByOwner(q, "job_skills", "job_id", ids)
+func SkillIDsByOwner(q Querier, linkTable, ownerColumn string, ownerIDs []int) (map[int][]int, error) {
+	result := make(map[int][]int)
+	if len(ownerIDs) == 0 {
+		return result, nil
+	}
+
+	args := make([]interface{}, len(ownerIDs))
+	for i, id := range ownerIDs {
+		args[i] = id
+	}
+	query := "SELECT " + ownerColumn + ", skill_id FROM " + linkTable + " WHERE " + ownerColumn +
+		" IN (?" + strings.Repeat(", ?", len(ownerIDs)-1) + ")"
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying %s: %v", linkTable, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ownerID, skillID int
+		if err := rows.Scan(&ownerID, &skillID); err != nil {
+			return nil, fmt.Errorf("error scanning %s: %v", linkTable, err)
+		}
+		result[ownerID] = append(result[ownerID], skillID)
+	}
+	return result, rows.Err()
+}
+
+// SortByScore orders indexes of scores from best to worst match, keeping the original order on ties
+func SortByScore(scores []MatchScore) []int {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]].Total > scores[order[b]].Total
+	})
+	return order
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestScoreMatch(t *testing.T) {
+	intp := func(value int) *int { return &value }
+	floatp := func(value float64) *float64 { return &value }
+	stringp := func(value string) *string { return &value }
+
+	job := JobRequirements{
+		JobID: 1, SkillIDs: []int{1, 2, 3, 4}, MinExperience: 0, MaxExperience: 2,
+		Location: "Bangkok", MinSalary: 25000, MaxSalary: 35000, JobLevel: "Entry",
+	}
+
+	tests := []struct {
+		name    string
+		profile CandidateProfile
+		job     func(job *JobRequirements)
+		// want lists the components to check; total is checked when it is not negative
+		want  map[string]MatchComponent
+		total float64
+	}{
+		{
+			name: "full match",
+			profile: CandidateProfile{SkillIDs: []int{4, 3, 2, 1, 9}, ExperienceYears: intp(1), PreferredLocation: stringp(" bangkok "),
+				ExpectedSalary: floatp(35000), PreferredJobLevel: stringp("entry")},
+			want: map[string]MatchComponent{
+				"skills":     {Score: 1, Weight: matchWeightSkills, Reason: "has 4 of 4 required skills"},
+				"experience": {Score: 1, Weight: matchWeightExperience, Reason: "1 years of experience is within 0–2"},
+				"location":   {Score: 1, Weight: matchWeightLocation, Reason: "job is in the preferred location"},
+				"salary":     {Score: 1, Weight: matchWeightSalary, Reason: "expected salary 35000 fits the range 25000–35000"},
+				"job_level":  {Score: 1, Weight: matchWeightLevel, Reason: "job is at the preferred level Entry"},
+			},
+			total: 100,
+		},
+		{
+			name: "poor match",
+			profile: CandidateProfile{SkillIDs: []int{2}, ExperienceYears: intp(0), PreferredLocation: stringp("Chiang Mai"),
+				ExpectedSalary: floatp(40000), PreferredJobLevel: stringp("Senior")},
+			job: func(job *JobRequirements) { job.MinExperience = 2 },
+			want: map[string]MatchComponent{
+				"skills":     {Score: 0.25, Weight: matchWeightSkills, Reason: "has 1 of 4 required skills"},
+				"experience": {Score: 0.5, Weight: matchWeightExperience, Reason: "0 years of experience, job asks for at least 2"},
+				"location":   {Score: 0, Weight: matchWeightLocation, Reason: "job is in Bangkok, not Chiang Mai"},
+				"salary":     {Score: 0.86, Weight: matchWeightSalary, Reason: "expected salary 40000 is above the maximum 35000"},
+				"job_level":  {Score: 0, Weight: matchWeightLevel, Reason: "job is Entry, far from the preferred Senior"},
+			},
+			total: 32.9,
+		},
+		{
+			name:    "empty profile and job",
+			profile: CandidateProfile{},
+			job:     func(job *JobRequirements) { job.SkillIDs = nil },
+			want: map[string]MatchComponent{
+				"skills":     {Score: neutralMatchScore, Weight: matchWeightSkills, Reason: "job lists no catalogue skills"},
+				"experience": {Score: neutralMatchScore, Weight: matchWeightExperience, Reason: "experience not set on profile"},
+				"location":   {Score: neutralMatchScore, Weight: matchWeightLocation, Reason: "preferred location not set on profile"},
+				"salary":     {Score: neutralMatchScore, Weight: matchWeightSalary, Reason: "salary expectation not set on profile"},
+				"job_level":  {Score: neutralMatchScore, Weight: matchWeightLevel, Reason: "preferred job level not set on profile"},
+			},
+			total: 50,
+		},
+		{
+			name: "partial fits",
+			profile: CandidateProfile{ExperienceYears: intp(5), PreferredLocation: stringp("Bangkok"),
+				ExpectedSalary: floatp(20000), PreferredJobLevel: stringp("Junior")},
+			job: func(job *JobRequirements) { job.SkillIDs, job.Location, job.MaxSalary = nil, "Remote (Thailand)", 0 },
+			want: map[string]MatchComponent{
+				"experience": {Score: 0.75, Weight: matchWeightExperience, Reason: "5 years of experience, above the job's 2"},
+				"location":   {Score: 1, Weight: matchWeightLocation, Reason: "job is remote"},
+				"salary":     {Score: 0, Weight: matchWeightSalary, Reason: "job has no salary range"},
+				"job_level":  {Score: 0.5, Weight: matchWeightLevel, Reason: "job is Entry, one level from the preferred Junior"},
+			},
+			total: 55,
+		},
+		{
+			name:    "overlapping location",
+			profile: CandidateProfile{PreferredLocation: stringp("Bangkok")},
+			job:     func(job *JobRequirements) { job.Location = "Bangkok, Thailand" },
+			want: map[string]MatchComponent{
+				"location": {Score: 0.75, Weight: matchWeightLocation, Reason: "job location overlaps the preferred location"},
+			},
+			total: -1,
+		},
+		{
+			name:    "job without a location",
+			profile: CandidateProfile{PreferredLocation: stringp("Bangkok")},
+			job:     func(job *JobRequirements) { job.Location = "  " },
+			want: map[string]MatchComponent{
+				"location": {Score: neutralMatchScore, Weight: matchWeightLocation, Reason: "job has no location"},
+			},
+			total: -1,
+		},
+		{
+			name:    "far too little experience",
+			profile: CandidateProfile{ExperienceYears: intp(0)},
+			job:     func(job *JobRequirements) { job.MinExperience, job.MaxExperience = 5, 8 },
+			want: map[string]MatchComponent{
+				"experience": {Score: 0, Weight: matchWeightExperience, Reason: "0 years of experience, job asks for at least 5"},
+			},
+			total: -1,
+		},
+		{
+			name:    "salary far above the maximum",
+			profile: CandidateProfile{ExpectedSalary: floatp(80000)},
+			want: map[string]MatchComponent{
+				"salary": {Score: 0, Weight: matchWeightSalary, Reason: "expected salary 80000 is above the maximum 35000"},
+			},
+			total: -1,
+		},
+		{
+			name:    "unknown job level",
+			profile: CandidateProfile{PreferredJobLevel: stringp("Lead")},
+			want: map[string]MatchComponent{
+				"job_level": {Score: neutralMatchScore, Weight: matchWeightLevel, Reason: "job level not recognised"},
+			},
+			total: -1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			job := job
+			job.SkillIDs = append([]int(nil), job.SkillIDs...)
+			if test.job != nil {
+				test.job(&job)
+			}
+			match := ScoreMatch(test.profile, job)
+
+			if len(match.Breakdown) != 5 {
+				t.Errorf("ScoreMatch() breakdown has %d components, want 5: %v", len(match.Breakdown), match.Breakdown)
+			}
+			for key, want := range test.want {
+				if got, ok := match.Breakdown[key]; !ok || got != want {
+					t.Errorf("ScoreMatch() %s = %+v, want %+v", key, got, want)
+				}
+			}
+			if test.total >= 0 && match.Total != test.total {
+				t.Errorf("ScoreMatch() total = %v, want %v", match.Total, test.total)
+			}
+
+			// The total is always the weighted sum of the breakdown, as a percentage
+			var sum float64
+			for _, component := range match.Breakdown {
+				sum += component.Score * component.Weight
+			}
+			if math.Abs(match.Total-sum*100) > 0.05 {
+				t.Errorf("ScoreMatch() total = %v, want the weighted breakdown %v", match.Total, sum*100)
+			}
+		})
+	}
+}
+
+func TestMatchWeightsSumToOne(t *testing.T) {
+	sum := matchWeightSkills + matchWeightExperience + matchWeightLocation + matchWeightSalary + matchWeightLevel
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("match weights sum to %v, want 1", sum)
+	}
+}
+
+func TestSortByScore(t *testing.T) {
+	scores := []MatchScore{{Total: 40}, {Total: 90}, {Total: 40}, {Total: 75.5}, {Total: 90}}
+	want := []int{1, 4, 3, 0, 2}
+	if got := SortByScore(scores); !reflect.DeepEqual(got, want) {
+		t.Errorf("SortByScore() = %v, want %v", got, want)
+	}
+}
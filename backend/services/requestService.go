@@ -0,0 +1,90 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader is the header used to propagate the request ID between clients and the API
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware reuses the caller's X-Request-ID or generates a new one and stores it in the context
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = newRequestID()
+		}
+
+		// Store request_id in context and echo it back to the client
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+// RequestID returns the request ID stored by RequestIDMiddleware
+func RequestID(c *gin.Context) string {
+	if requestID := c.GetString("request_id"); requestID != "" {
+		return requestID
+	}
+	return newRequestID()
+}
+
+// newRequestID generates a random 32 character hex ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// redactedQueryParams are query parameters whose values are kept out of the access log
+var redactedQueryParams = map[string]bool{"token": true}
+
+// RequestLogger is gin's default access logger, except that credentials sent in the query string, such as the
+// JWT that EventSource clients pass as ?token=, are redacted
+func RequestLogger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		var statusColor, methodColor, resetColor string
+		if param.IsOutputColor() {
+			statusColor, methodColor, resetColor = param.StatusCodeColor(), param.MethodColor(), param.ResetColor()
+		}
+		if param.Latency > time.Minute {
+			param.Latency = param.Latency.Truncate(time.Second)
+		}
+		return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+			param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			statusColor, param.StatusCode, resetColor,
+			param.Latency,
+			param.ClientIP,
+			methodColor, param.Method, resetColor,
+			RedactQuery(param.Path),
+			param.ErrorMessage,
+		)
+	})
+}
+
+// RedactQuery replaces the values of redactedQueryParams in a path's query string
+func RedactQuery(path string) string {
+	at := strings.IndexByte(path, '?')
+	if at < 0 {
+		return path
+	}
+	params := strings.Split(path[at+1:], "&")
+	for i, param := range params {
+		name, _, _ := strings.Cut(param, "=")
+		if decoded, err := url.QueryUnescape(name); err == nil && redactedQueryParams[decoded] {
+			params[i] = name + "=REDACTED"
+		}
+	}
+	return path[:at+1] + strings.Join(params, "&")
+}
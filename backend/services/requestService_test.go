@@ -0,0 +1,24 @@
+package services
+
+import "testing"
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/notifications/stream", "/notifications/stream"},
+		{"/notifications/stream?token=eyJhbGciOi.eyJpZCI6.sig", "/notifications/stream?token=REDACTED"},
+		{"/notifications/stream?last_event_id=42&token=abc", "/notifications/stream?last_event_id=42&token=REDACTED"},
+		{"/notifications/stream?to%6Ben=abc&token", "/notifications/stream?to%6Ben=REDACTED&token=REDACTED"},
+		{"/public/jobs?tokens=2&q=token", "/public/jobs?tokens=2&q=token"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			if got := RedactQuery(test.path); got != test.want {
+				t.Errorf("RedactQuery(%q) = %q, want %q", test.path, got, test.want)
+			}
+		})
+	}
+}
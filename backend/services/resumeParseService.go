@@ -0,0 +1,652 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Resume parsing turns the text of a resume into a draft profile with simple English and Thai heuristics:
+// section headings split the text, and dates, GPAs, contact details and catalogue skills are matched within
+// them. The draft is only a suggestion; the candidate reviews it and saves the profile themselves.
+
+// ResumeDraft is what was found in a resume
+type ResumeDraft struct {
+	Profile         ProfileDraft      `json:"profile"`
+	Contact         ResumeContact     `json:"contact"`
+	Education       []EducationEntry  `json:"education"`
+	Experience      []ExperienceEntry `json:"experience"`
+	Skills          []Skill           `json:"skills"`
+	UnmatchedSkills []string          `json:"unmatched_skills"`
+	Text            string            `json:"text"`
+}
+
+// ProfileDraft holds the freshgradprofiles fields found in a resume, in the shape PUT /freshGrad/profile takes;
+// the skills go to PUT /freshGrad/profile/skills
+type ProfileDraft struct {
+	FullName        *string  `json:"full_name,omitempty"`
+	Phone           *string  `json:"phone,omitempty"`
+	University      *string  `json:"university,omitempty"`
+	GraduationYear  *int     `json:"graduation_year,omitempty"`
+	GPA             *float64 `json:"gpa,omitempty"`
+	ExperienceYears *int     `json:"experience_years,omitempty"`
+}
+
+// ResumeContact is the contact information found in a resume
+type ResumeContact struct {
+	Email *string  `json:"email"`
+	Phone *string  `json:"phone"`
+	Links []string `json:"links"`
+}
+
+// EducationEntry is one school or degree of a resume; years are in the Gregorian calendar
+type EducationEntry struct {
+	Institution string   `json:"institution"`
+	Degree      *string  `json:"degree"`
+	StartYear   *int     `json:"start_year"`
+	EndYear     *int     `json:"end_year"`
+	GPA         *float64 `json:"gpa"`
+	Details     []string `json:"details"`
+}
+
+// ExperienceEntry is one job or internship of a resume; Start and End are YYYY-MM
+type ExperienceEntry struct {
+	Title   string   `json:"title"`
+	Start   *string  `json:"start"`
+	End     *string  `json:"end"`
+	Current bool     `json:"current"`
+	Months  int      `json:"months"`
+	Details []string `json:"details"`
+}
+
+// Sections of a resume
+const (
+	resumeSectionHeader     = "header"
+	resumeSectionEducation  = "education"
+	resumeSectionExperience = "experience"
+	resumeSectionSkills     = "skills"
+	resumeSectionOther      = "other"
+)
+
+// resumeHeadings maps lowercase section headings to their section
+var resumeHeadings = map[string]string{
+	"education": resumeSectionEducation, "educational background": resumeSectionEducation,
+	"academic background": resumeSectionEducation, "education background": resumeSectionEducation,
+	"การศึกษา": resumeSectionEducation, "ประวัติการศึกษา": resumeSectionEducation,
+
+	"experience": resumeSectionExperience, "work experience": resumeSectionExperience,
+	"professional experience": resumeSectionExperience, "employment history": resumeSectionExperience,
+	"work history": resumeSectionExperience, "internship": resumeSectionExperience,
+	"internships": resumeSectionExperience, "internship experience": resumeSectionExperience,
+	"ประสบการณ์": resumeSectionExperience, "ประสบการณ์ทำงาน": resumeSectionExperience,
+	"ประวัติการทำงาน": resumeSectionExperience, "การฝึกงาน": resumeSectionExperience,
+	"ประสบการณ์การทำงาน": resumeSectionExperience, "ประสบการณ์ฝึกงาน": resumeSectionExperience,
+
+	"skills": resumeSectionSkills, "technical skills": resumeSectionSkills, "skill": resumeSectionSkills,
+	"core skills": resumeSectionSkills, "skills & tools": resumeSectionSkills, "skills and tools": resumeSectionSkills,
+	"technologies": resumeSectionSkills, "tools": resumeSectionSkills,
+	"ทักษะ": resumeSectionSkills, "ความสามารถ": resumeSectionSkills, "ทักษะและความสามารถ": resumeSectionSkills,
+
+	"projects": resumeSectionOther, "certifications": resumeSectionOther, "certificates": resumeSectionOther,
+	"awards": resumeSectionOther, "languages": resumeSectionOther, "activities": resumeSectionOther,
+	"extracurricular activities": resumeSectionOther, "references": resumeSectionOther,
+	"summary": resumeSectionOther, "profile": resumeSectionOther, "objective": resumeSectionOther,
+	"about me": resumeSectionOther, "contact": resumeSectionOther, "interests": resumeSectionOther,
+	"hobbies": resumeSectionOther, "personal information": resumeSectionOther,
+	"โครงการ": resumeSectionOther, "ผลงาน": resumeSectionOther, "กิจกรรม": resumeSectionOther,
+	"ภาษา": resumeSectionOther, "ข้อมูลส่วนตัว": resumeSectionOther, "เกี่ยวกับฉัน": resumeSectionOther,
+	"รางวัล": resumeSectionOther, "ใบรับรอง": resumeSectionOther, "บุคคลอ้างอิง": resumeSectionOther,
+}
+
+// Words that mark the lines of education entries
+var (
+	institutionWords = []string{"university", "college", "institute", "school", "academy", "มหาวิทยาลัย", "วิทยาลัย", "สถาบัน", "โรงเรียน"}
+	schoolWords      = []string{"school", "academy", "โรงเรียน"}
+	degreeWords      = []string{"bachelor", "master", "doctor", "degree", "b.sc", "b.eng", "b.a.", "b.b.a", "bsc", "beng",
+		"m.sc", "m.eng", "mba", "ph.d", "diploma", "high school", "major", "ปริญญา", "บัณฑิต", "สาขา", "มัธยม", "ปวช", "ปวส"}
+)
+
+// resumeMonths maps English and Thai month names and abbreviations to month numbers
+var resumeMonths = map[string]int{
+	"jan": 1, "january": 1, "feb": 2, "february": 2, "mar": 3, "march": 3, "apr": 4, "april": 4, "may": 5,
+	"jun": 6, "june": 6, "jul": 7, "july": 7, "aug": 8, "august": 8, "sep": 9, "sept": 9, "september": 9,
+	"oct": 10, "october": 10, "nov": 11, "november": 11, "dec": 12, "december": 12,
+	"ม.ค.": 1, "มกราคม": 1, "ก.พ.": 2, "กุมภาพันธ์": 2, "มี.ค.": 3, "มีนาคม": 3, "เม.ย.": 4, "เมษายน": 4,
+	"พ.ค.": 5, "พฤษภาคม": 5, "มิ.ย.": 6, "มิถุนายน": 6, "ก.ค.": 7, "กรกฎาคม": 7, "ส.ค.": 8, "สิงหาคม": 8,
+	"ก.ย.": 9, "กันยายน": 9, "ต.ค.": 10, "ตุลาคม": 10, "พ.ย.": 11, "พฤศจิกายน": 11, "ธ.ค.": 12, "ธันวาคม": 12,
+}
+
+var (
+	resumeEmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	resumePhonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?\(?0?\d{1,2}\)?[\s.-]?\d{3}[\s.-]?\d{3,4}`)
+	resumeLinkPattern  = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s,;]+|\b(?:linkedin\.com|github\.com)/[^\s,;]+`)
+	resumeYearPattern  = regexp.MustCompile(`\b(?:19|20|25)\d{2}\b`)
+	resumeGPAPattern   = regexp.MustCompile(`(?i)(?:gpax?|g\.p\.a\.?|เกรดเฉลี่ย(?:สะสม)?)\s*[:：=]?\s*([0-4](?:\.\d{1,2})?)`)
+	resumeBullet       = regexp.MustCompile(`^[•·▪●○◦\-*–]\s*`)
+	resumeSeparators   = regexp.MustCompile(`\s*(?:[|,•·\t]|\s[-–—]\s)\s*`)
+	resumeSkillSplit   = regexp.MustCompile(`[,;|•·/]`)
+	resumeDatePoint    = `(?:(` + monthAlternatives() + `)\.?\s*|(\d{1,2})\s*/\s*)?((?:19|20|25)\d{2})`
+	resumeDateRange    = regexp.MustCompile(`(?i)` + resumeDatePoint + `\s*(?:-|–|—|to|until|ถึง)\s*(?:` + resumeDatePoint +
+		`|(present|current|now|today|ปัจจุบัน))`)
+)
+
+// monthAlternatives lists the month names for a regular expression, longest first so names win over
+// their abbreviations
+func monthAlternatives() string {
+	names := make([]string, 0, len(resumeMonths))
+	for name := range resumeMonths {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return strings.Join(names, "|")
+}
+
+// ReadResumeUpload reads the PDF or DOCX file of a multipart form's file field, returning its content type
+func ReadResumeUpload(c *gin.Context) ([]byte, string, FieldErrors, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxResumeBytes+(1<<20))
+	header, err := c.FormFile("file")
+	if err == http.ErrMissingFile {
+		return nil, "", FieldErrors{"file": "is required"}, nil
+	}
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if header.Size > MaxResumeBytes {
+		return nil, "", FieldErrors{"file": fmt.Sprintf("must be at most %d MB", MaxResumeBytes>>20)}, nil
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	contentType, ok := resumeContentType(header.Filename, http.DetectContentType(data))
+	if !ok {
+		return nil, "", FieldErrors{"file": ErrResumeFileType.Error()}, nil
+	}
+	return data, contentType, nil, nil
+}
+
+// ReadResumeVersion reads the uploaded file of a version of one of the profile's resumes. Linked versions
+// have no file to read and give ErrResumeFileType.
+func ReadResumeVersion(q Querier, storage Storage, profileID int, resumeID, versionID interface{}) ([]byte, string, error) {
+	var version ResumeVersion
+	query := "SELECT " + resumeVersionColumns + " FROM resume_versions rv INNER JOIN resumes r ON r.resume_id = rv.resume_id " +
+		"WHERE rv.resume_version_id = ? AND r.resume_id = ? AND r.freshgradprofile_id = ?"
+	if err := q.QueryRow(query, versionID, resumeID, profileID).Scan(version.scanTargets()...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, "", ErrResumeNotFound
+		}
+		return nil, "", fmt.Errorf("error loading resume version: %v", err)
+	}
+	if version.storageKey == nil {
+		return nil, "", ErrResumeFileType
+	}
+	file, err := storage.Open(*version.storageKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("error opening resume file: %v", err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, MaxResumeBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading resume file: %v", err)
+	}
+	return data, *version.ContentType, nil
+}
+
+// ParseResume extracts the text of a resume file and drafts a profile from it, matching skills against the
+// catalogue; now dates experience that is still going on
+func ParseResume(q Querier, contentType string, data []byte, now time.Time) (ResumeDraft, error) {
+	text, err := ExtractResumeText(contentType, data)
+	if err != nil {
+		return ResumeDraft{}, err
+	}
+	catalogue, err := ListSkills(q, "")
+	if err != nil {
+		return ResumeDraft{}, err
+	}
+	return DraftResume(text, catalogue, now), nil
+}
+
+// DraftResume drafts a profile from the plain text of a resume
+func DraftResume(text string, catalogue []Skill, now time.Time) ResumeDraft {
+	draft := ResumeDraft{Text: text, Contact: resumeContact(text)}
+	sections := splitResumeSections(strings.Split(text, "\n"))
+
+	draft.Profile.Phone = draft.Contact.Phone
+	draft.Profile.FullName = resumeName(sections[resumeSectionHeader])
+
+	draft.Education = resumeEducation(sections[resumeSectionEducation])
+	if entry := mainEducation(draft.Education, now); entry != nil {
+		university := entry.Institution
+		draft.Profile.University, draft.Profile.GraduationYear, draft.Profile.GPA = &university, entry.EndYear, entry.GPA
+	}
+
+	var months int
+	draft.Experience, months = resumeExperience(sections[resumeSectionExperience], now)
+	if months > 0 {
+		years := months / 12
+		draft.Profile.ExperienceYears = &years
+	}
+
+	var other []string
+	for section, lines := range sections {
+		if section != resumeSectionSkills {
+			other = append(other, lines...)
+		}
+	}
+	draft.Skills, draft.UnmatchedSkills = resumeSkills(sections[resumeSectionSkills], other, catalogue)
+	return draft
+}
+
+// splitResumeSections groups lines under the section of the heading before them; lines before any heading
+// are the header. A heading may share its line with the section's first content, as in "Skills: Go, SQL".
+func splitResumeSections(lines []string) map[string][]string {
+	sections := map[string][]string{}
+	section := resumeSectionHeader
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if heading, rest, ok := resumeHeading(line); ok {
+			section = heading
+			if rest == "" {
+				continue
+			}
+			line = rest
+		}
+		sections[section] = append(sections[section], line)
+	}
+	return sections
+}
+
+// resumeHeading reports whether a line starts a section, returning the section and the rest of the line
+func resumeHeading(line string) (string, string, bool) {
+	head, rest := line, ""
+	if at := strings.IndexAny(line, ":："); at >= 0 {
+		head, rest = line[:at], strings.TrimSpace(strings.TrimLeft(line[at:], ":："))
+	}
+	head = strings.ToLower(strings.Join(strings.Fields(strings.Trim(head, " -–|•*#")), " "))
+	section, ok := resumeHeadings[head]
+	return section, rest, ok
+}
+
+// resumeContact finds the first email address and phone number and the profile links of a resume
+func resumeContact(text string) ResumeContact {
+	contact := ResumeContact{Links: []string{}}
+	if email := resumeEmailPattern.FindString(text); email != "" {
+		contact.Email = &email
+	}
+	for _, match := range resumePhonePattern.FindAllString(text, -1) {
+		digits := strings.Map(func(r rune) rune {
+			if unicode.IsDigit(r) || r == '+' {
+				return r
+			}
+			return -1
+		}, match)
+		if count := len(strings.TrimPrefix(digits, "+")); count >= 9 && count <= 12 {
+			phone := strings.TrimSpace(match)
+			contact.Phone = &phone
+			break
+		}
+	}
+	seen := map[string]bool{}
+	for _, link := range resumeLinkPattern.FindAllString(text, -1) {
+		link = strings.TrimRight(link, ".)")
+		if !seen[strings.ToLower(link)] && !strings.Contains(link, "@") && len(contact.Links) < 5 {
+			seen[strings.ToLower(link)] = true
+			contact.Links = append(contact.Links, link)
+		}
+	}
+	return contact
+}
+
+// resumeName takes the first short header line made of letters only as the candidate's name
+func resumeName(header []string) *string {
+	for i, line := range header {
+		if i >= 5 {
+			break
+		}
+		lower := strings.ToLower(line)
+		if lower == "resume" || lower == "curriculum vitae" || lower == "cv" || lower == "เรซูเม่" || lower == "ประวัติย่อ" {
+			continue
+		}
+		words := strings.Fields(line)
+		if len(words) < 2 || len(words) > 5 || utf8.RuneCountInString(line) > 60 {
+			continue
+		}
+		onlyLetters := true
+		for _, r := range line {
+			if !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r) && r != ' ' && r != '.' && r != '-' && r != '\'' {
+				onlyLetters = false
+				break
+			}
+		}
+		if onlyLetters {
+			name := line
+			return &name
+		}
+	}
+	return nil
+}
+
+// containsAnyWord reports whether the lowercase text contains one of words
+func containsAnyWord(text string, words []string) bool {
+	for _, word := range words {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// resumeSegment returns the part of a line between separators that contains one of words, or ""
+func resumeSegment(line string, words []string) string {
+	for _, segment := range resumeSeparators.Split(line, -1) {
+		if containsAnyWord(strings.ToLower(segment), words) {
+			segment = resumeGPAPattern.ReplaceAllString(segment, "")
+			segment = resumeDateRange.ReplaceAllString(segment, "")
+			segment = resumeYearPattern.ReplaceAllString(segment, "")
+			return strings.Trim(segment, " ()-–—,")
+		}
+	}
+	return ""
+}
+
+// resumeYears returns the Gregorian years on a line; Buddhist Era years (25xx) are converted
+func resumeYears(line string) []int {
+	var years []int
+	for _, match := range resumeYearPattern.FindAllString(line, -1) {
+		year, _ := strconv.Atoi(match)
+		if year >= 2500 {
+			year -= 543
+		}
+		years = append(years, year)
+	}
+	return years
+}
+
+// resumeEducation reads education entries; a line naming an institution starts a new entry
+func resumeEducation(lines []string) []EducationEntry {
+	entries := []EducationEntry{}
+	var current *EducationEntry
+	var years []int
+	finish := func() {
+		if current == nil {
+			return
+		}
+		sort.Ints(years)
+		if len(years) > 0 {
+			end := years[len(years)-1]
+			current.EndYear = &end
+		}
+		if len(years) > 1 {
+			start := years[0]
+			current.StartYear = &start
+		}
+		if current.Institution != "" || current.Degree != nil {
+			entries = append(entries, *current)
+		}
+		current, years = nil, nil
+	}
+
+	for _, line := range lines {
+		line = resumeBullet.ReplaceAllString(line, "")
+		lower := strings.ToLower(line)
+		institution := ""
+		if containsAnyWord(lower, institutionWords) {
+			institution = resumeSegment(line, institutionWords)
+		}
+		if current == nil || (institution != "" && current.Institution != "") {
+			finish()
+			current = &EducationEntry{Details: []string{}}
+		}
+
+		used := false
+		if institution != "" {
+			current.Institution, used = institution, true
+		}
+		if current.Degree == nil && containsAnyWord(lower, degreeWords) {
+			if degree := resumeSegment(line, degreeWords); degree != "" && degree != current.Institution {
+				current.Degree, used = &degree, true
+			}
+		}
+		if match := resumeGPAPattern.FindStringSubmatch(line); match != nil && current.GPA == nil {
+			if gpa, err := strconv.ParseFloat(match[1], 64); err == nil && gpa <= 4 {
+				current.GPA, used = &gpa, true
+			}
+		}
+		if lineYears := resumeYears(line); len(lineYears) > 0 {
+			years, used = append(years, lineYears...), true
+		}
+		if !used {
+			current.Details = append(current.Details, line)
+		}
+	}
+	finish()
+	return entries
+}
+
+// mainEducation picks the latest university-level entry, ignoring graduation years too far in the future
+func mainEducation(entries []EducationEntry, now time.Time) *EducationEntry {
+	var best *EducationEntry
+	for i := range entries {
+		entry := &entries[i]
+		lower := strings.ToLower(entry.Institution)
+		if entry.Institution == "" || containsAnyWord(lower, schoolWords) {
+			continue
+		}
+		if entry.EndYear != nil && *entry.EndYear > now.Year()+6 {
+			entry.EndYear = nil
+		}
+		if best == nil || (entry.EndYear != nil && (best.EndYear == nil || *entry.EndYear > *best.EndYear)) {
+			best = entry
+		}
+	}
+	return best
+}
+
+// resumeMonth returns a month index (year*12 + month-1) from the groups of resumeDatePoint, or -1
+func resumeMonth(monthName, monthNumber, yearText string, defaultMonth int) int {
+	year, err := strconv.Atoi(yearText)
+	if err != nil {
+		return -1
+	}
+	if year >= 2500 {
+		year -= 543
+	}
+	month := defaultMonth
+	if number, err := strconv.Atoi(monthNumber); err == nil && number >= 1 && number <= 12 {
+		month = number
+	} else if number, ok := resumeMonths[strings.ToLower(monthName)]; ok {
+		month = number
+	}
+	return year*12 + month - 1
+}
+
+// formatResumeMonth formats a month index as YYYY-MM
+func formatResumeMonth(index int) *string {
+	text := fmt.Sprintf("%04d-%02d", index/12, index%12+1)
+	return &text
+}
+
+// resumeExperience reads experience entries, each starting at a line with a date range, and returns them with
+// the months they cover together, counting overlapping entries once
+func resumeExperience(lines []string, now time.Time) ([]ExperienceEntry, int) {
+	entries := []ExperienceEntry{}
+	var spans [][2]int
+	var pending []string
+	lastWasBullet := true
+	for _, line := range lines {
+		bullet := resumeBullet.MatchString(line)
+		line = resumeBullet.ReplaceAllString(line, "")
+		match := resumeDateRange.FindStringSubmatchIndex(line)
+		if match == nil {
+			if len(entries) == 0 {
+				pending = append(pending, line)
+			} else {
+				last := &entries[len(entries)-1]
+				last.Details = append(last.Details, line)
+			}
+			lastWasBullet = bullet
+			continue
+		}
+
+		group := func(i int) string {
+			if match[2*i] < 0 {
+				return ""
+			}
+			return line[match[2*i]:match[2*i+1]]
+		}
+		entry := ExperienceEntry{Details: []string{}}
+		entry.Title = strings.Trim(line[:match[0]]+" "+line[match[1]:], " ()|,-–—:")
+		entry.Title = strings.Join(strings.Fields(entry.Title), " ")
+		if entry.Title == "" {
+			// The title is on the line before the dates
+			if len(entries) == 0 && len(pending) > 0 {
+				entry.Title, pending = pending[len(pending)-1], pending[:len(pending)-1]
+			} else if len(entries) > 0 && !lastWasBullet {
+				last := &entries[len(entries)-1]
+				if n := len(last.Details); n > 0 {
+					entry.Title, last.Details = last.Details[n-1], last.Details[:n-1]
+				}
+			}
+		}
+
+		start := resumeMonth(group(1), group(2), group(3), 1)
+		var end int
+		if group(7) != "" {
+			entry.Current = true
+			end = now.Year()*12 + int(now.Month()) - 1
+		} else {
+			end = resumeMonth(group(4), group(5), group(6), 1)
+		}
+		if start >= 0 && end >= start {
+			entry.Start = formatResumeMonth(start)
+			if !entry.Current {
+				entry.End = formatResumeMonth(end)
+			}
+			entry.Months = end - start
+			if group(1) != "" || group(2) != "" {
+				// Both ends are inclusive when months are given
+				entry.Months++
+			}
+			if entry.Months == 0 {
+				entry.Months = 1
+			}
+			spans = append(spans, [2]int{start, start + entry.Months})
+		}
+		entries = append(entries, entry)
+		lastWasBullet = bullet
+	}
+	if len(entries) == 0 && len(pending) > 0 {
+		entries = append(entries, ExperienceEntry{Title: pending[0], Details: append([]string{}, pending[1:]...)})
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	months, coveredUntil := 0, -1
+	for _, span := range spans {
+		if span[0] < coveredUntil {
+			span[0] = coveredUntil
+		}
+		if span[1] > span[0] {
+			months += span[1] - span[0]
+			coveredUntil = span[1]
+		}
+	}
+	return entries, months
+}
+
+// resumeSkills matches catalogue skills, by name, Thai name or alias, in the skills section, and by the longer
+// names also elsewhere in the resume. Entries of the skills section that match nothing are returned as well.
+func resumeSkills(skillLines, otherLines []string, catalogue []Skill) ([]Skill, []string) {
+	terms := map[string]int{}
+	for i, skill := range catalogue {
+		for _, name := range append([]string{skill.Name, skill.NameTH}, skill.Aliases...) {
+			if name = strings.ToLower(NormalizeSkillName(name)); name != "" {
+				terms[name] = i
+			}
+		}
+	}
+
+	skillText := strings.ToLower(strings.Join(skillLines, "\n"))
+	otherText := strings.ToLower(strings.Join(otherLines, "\n"))
+	found := map[int]bool{}
+	for term, i := range terms {
+		if containsResumeTerm(skillText, term) || (utf8.RuneCountInString(term) >= 3 && containsResumeTerm(otherText, term)) {
+			found[i] = true
+		}
+	}
+	skills := []Skill{}
+	for i, skill := range catalogue {
+		if found[i] {
+			skill.Aliases = nil
+			skills = append(skills, skill)
+		}
+	}
+
+	unmatched := []string{}
+	seen := map[string]bool{}
+	for _, line := range skillLines {
+		// Drop a label such as "Programming languages:"
+		if at := strings.IndexAny(line, ":："); at >= 0 && len(strings.Fields(line[:at])) <= 3 {
+			line = strings.TrimLeft(line[at:], ":：")
+		}
+		for _, item := range resumeSkillSplit.Split(line, -1) {
+			item = NormalizeSkillName(strings.Trim(resumeBullet.ReplaceAllString(strings.TrimSpace(item), ""), " ()."))
+			key := strings.ToLower(item)
+			if count := utf8.RuneCountInString(item); count == 0 || count > 40 || seen[key] {
+				continue
+			}
+			seen[key] = true
+			if _, ok := terms[key]; !ok && len(unmatched) < 30 {
+				unmatched = append(unmatched, item)
+			}
+		}
+	}
+	return skills, unmatched
+}
+
+// containsResumeTerm reports whether text contains term as a whole word; Thai terms, written without
+// spaces between words, match anywhere
+func containsResumeTerm(text, term string) bool {
+	for offset := 0; offset < len(text); {
+		at := strings.Index(text[offset:], term)
+		if at < 0 {
+			return false
+		}
+		start, end := offset+at, offset+at+len(term)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		first, _ := utf8.DecodeRuneInString(term)
+		if first > unicode.MaxASCII || ((start == 0 || !isResumeWordRune(before)) && (end == len(text) || !isResumeWordRune(after))) {
+			return true
+		}
+		offset = start + 1
+	}
+	return false
+}
+
+// isResumeWordRune reports whether r continues a word, so that "Java" does not match inside "JavaScript"
+func isResumeWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#'
+}
@@ -0,0 +1,511 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResumeHeading(t *testing.T) {
+	tests := []struct {
+		line    string
+		section string
+		rest    string
+		ok      bool
+	}{
+		{line: "Education", section: resumeSectionEducation, ok: true},
+		{line: "WORK  EXPERIENCE", section: resumeSectionExperience, ok: true},
+		{line: "## Technical Skills", section: resumeSectionSkills, ok: true},
+		{line: "Skills: Go, SQL", section: resumeSectionSkills, rest: "Go, SQL", ok: true},
+		{line: "ประวัติการศึกษา：", section: resumeSectionEducation, ok: true},
+		{line: "ทักษะ: ภาษาอังกฤษ", section: resumeSectionSkills, rest: "ภาษาอังกฤษ", ok: true},
+		{line: "Projects", section: resumeSectionOther, ok: true},
+		{line: "Kasetsart University"},
+		{line: "Experience with Go and SQL"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.line, func(t *testing.T) {
+			section, rest, ok := resumeHeading(test.line)
+			if section != test.section || rest != test.rest || ok != test.ok {
+				t.Errorf("resumeHeading(%q) = %q, %q, %v, want %q, %q, %v", test.line, section, rest, ok, test.section, test.rest, test.ok)
+			}
+		})
+	}
+}
+
+func TestResumeContact(t *testing.T) {
+	email := "somchai.j@example.com"
+	localPhone := "081-234-5678"
+	internationalPhone := "+66 81 234 5678"
+
+	tests := []struct {
+		name string
+		text string
+		want ResumeContact
+	}{
+		{
+			name: "email, phone and link",
+			text: "somchai.j@example.com | 081-234-5678 | linkedin.com/in/somchai",
+			want: ResumeContact{Email: &email, Phone: &localPhone, Links: []string{"linkedin.com/in/somchai"}},
+		},
+		{
+			name: "international phone after a short number",
+			text: "Room 1234\nTel: +66 81 234 5678",
+			want: ResumeContact{Phone: &internationalPhone, Links: []string{}},
+		},
+		{
+			name: "links without trailing punctuation or duplicates",
+			text: "Portfolio: https://somchai.dev. GitHub: github.com/somchai (github.com/Somchai)",
+			want: ResumeContact{Links: []string{"https://somchai.dev", "github.com/somchai"}},
+		},
+		{
+			name: "nothing",
+			text: "Somchai Jaidee\nBangkok",
+			want: ResumeContact{Links: []string{}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := resumeContact(test.text); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("resumeContact(%q) = %+v, want %+v", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestResumeName(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []string
+		want   string
+	}{
+		{name: "after a title", header: []string{"Curriculum Vitae", "Somchai Jaidee", "Bangkok"}, want: "Somchai Jaidee"},
+		{name: "Thai", header: []string{"ประวัติย่อ", "นายสมชาย ใจดี"}, want: "นายสมชาย ใจดี"},
+		{name: "initials", header: []string{"Somchai J. Jaidee"}, want: "Somchai J. Jaidee"},
+		{name: "skips contact lines", header: []string{"somchai@example.com", "Bangkok 10900", "Somchai Jaidee"}, want: "Somchai Jaidee"},
+		{name: "single word", header: []string{"Somchai"}},
+		{name: "too far down", header: []string{"a1", "b2", "c3", "d4", "e5", "Somchai Jaidee"}},
+		{name: "empty", header: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := resumeName(test.header)
+			if (got == nil) != (test.want == "") || (got != nil && *got != test.want) {
+				t.Errorf("resumeName(%q) = %v, want %q", test.header, got, test.want)
+			}
+		})
+	}
+}
+
+func TestResumeYears(t *testing.T) {
+	tests := []struct {
+		line string
+		want []int
+	}{
+		{line: "2019 - 2023", want: []int{2019, 2023}},
+		{line: "พ.ศ. 2562 - 2566", want: []int{2019, 2023}},
+		{line: "Class of 1999", want: []int{1999}},
+		{line: "Zip code 10900, ID 123456"},
+		{line: "No dates here"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.line, func(t *testing.T) {
+			if got := resumeYears(test.line); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("resumeYears(%q) = %v, want %v", test.line, got, test.want)
+			}
+		})
+	}
+}
+
+func TestResumeEducation(t *testing.T) {
+	intp := func(value int) *int { return &value }
+	floatp := func(value float64) *float64 { return &value }
+	stringp := func(value string) *string { return &value }
+
+	tests := []struct {
+		name  string
+		lines []string
+		want  []EducationEntry
+	}{
+		{
+			name: "one line per entry",
+			lines: []string{
+				"Kasetsart University | B.Eng. Computer Engineering | 2019 - 2023",
+				"GPA: 3.45",
+				"• Senior project: job matching platform",
+				"Triam Udom Suksa School 2013 - 2019",
+			},
+			want: []EducationEntry{
+				{Institution: "Kasetsart University", Degree: stringp("B.Eng. Computer Engineering"), StartYear: intp(2019),
+					EndYear: intp(2023), GPA: floatp(3.45), Details: []string{"Senior project: job matching platform"}},
+				{Institution: "Triam Udom Suksa School", StartYear: intp(2013), EndYear: intp(2019), Details: []string{}},
+			},
+		},
+		{
+			name: "Thai with Buddhist Era years",
+			lines: []string{
+				"มหาวิทยาลัยเชียงใหม่",
+				"ปริญญาตรี วิศวกรรมศาสตรบัณฑิต",
+				"พ.ศ. 2560 - 2564",
+				"เกรดเฉลี่ยสะสม 3.20",
+			},
+			want: []EducationEntry{
+				{Institution: "มหาวิทยาลัยเชียงใหม่", Degree: stringp("ปริญญาตรี วิศวกรรมศาสตรบัณฑิต"), StartYear: intp(2017),
+					EndYear: intp(2021), GPA: floatp(3.2), Details: []string{}},
+			},
+		},
+		{
+			name:  "graduation year only",
+			lines: []string{"Chulalongkorn University, Bachelor of Arts, 2024"},
+			want: []EducationEntry{
+				{Institution: "Chulalongkorn University", Degree: stringp("Bachelor of Arts"), EndYear: intp(2024), Details: []string{}},
+			},
+		},
+		{
+			name:  "no institution or degree",
+			lines: []string{"Dean's list 2022"},
+			want:  []EducationEntry{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := resumeEducation(test.lines); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("resumeEducation() = %s, want %s", formatEntries(got), formatEntries(test.want))
+			}
+		})
+	}
+}
+
+func TestMainEducation(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	intp := func(value int) *int { return &value }
+
+	tests := []struct {
+		name        string
+		entries     []EducationEntry
+		institution string
+		endYear     *int
+	}{
+		{
+			name: "latest university",
+			entries: []EducationEntry{
+				{Institution: "Kasetsart University", EndYear: intp(2023)},
+				{Institution: "Mahidol University", EndYear: intp(2025)},
+				{Institution: "Bangkok Christian College", EndYear: intp(2019)},
+			},
+			institution: "Mahidol University",
+			endYear:     intp(2025),
+		},
+		{
+			name: "skips schools",
+			entries: []EducationEntry{
+				{Institution: "Triam Udom Suksa School", EndYear: intp(2026)},
+				{Institution: "Kasetsart University", EndYear: intp(2023)},
+			},
+			institution: "Kasetsart University",
+			endYear:     intp(2023),
+		},
+		{
+			name:        "drops a graduation year far in the future",
+			entries:     []EducationEntry{{Institution: "Kasetsart University", EndYear: intp(2040)}},
+			institution: "Kasetsart University",
+		},
+		{
+			name:    "degree without an institution",
+			entries: []EducationEntry{{Degree: new(string), EndYear: intp(2023)}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := mainEducation(test.entries, now)
+			if test.institution == "" {
+				if got != nil {
+					t.Fatalf("mainEducation() = %+v, want nil", *got)
+				}
+				return
+			}
+			if got == nil || got.Institution != test.institution || !reflect.DeepEqual(got.EndYear, test.endYear) {
+				t.Fatalf("mainEducation() = %s, want %s ending %v", formatEntries(nilOrEntry(got)), test.institution, formatInt(test.endYear))
+			}
+		})
+	}
+}
+
+func TestResumeExperience(t *testing.T) {
+	now := time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC)
+	stringp := func(value string) *string { return &value }
+
+	tests := []struct {
+		name   string
+		lines  []string
+		want   []ExperienceEntry
+		months int
+	}{
+		{
+			name: "titles on and before the dates",
+			lines: []string{
+				"Software Engineer Intern, Agoda | Jun 2022 - Aug 2022",
+				"• Built internal tools in Go",
+				"Junior Developer",
+				"Jan 2023 - Present",
+				"- Maintained APIs",
+			},
+			want: []ExperienceEntry{
+				{Title: "Software Engineer Intern, Agoda", Start: stringp("2022-06"), End: stringp("2022-08"), Months: 3,
+					Details: []string{"Built internal tools in Go"}},
+				{Title: "Junior Developer", Start: stringp("2023-01"), Current: true, Months: 18, Details: []string{"Maintained APIs"}},
+			},
+			months: 21,
+		},
+		{
+			name:  "Thai months and Buddhist Era years",
+			lines: []string{"นักศึกษาฝึกงาน บริษัท เอสซีบี มิ.ย. 2565 - ส.ค. 2565"},
+			want: []ExperienceEntry{
+				{Title: "นักศึกษาฝึกงาน บริษัท เอสซีบี", Start: stringp("2022-06"), End: stringp("2022-08"), Months: 3, Details: []string{}},
+			},
+			months: 3,
+		},
+		{
+			name:  "numeric months",
+			lines: []string{"Teaching assistant 01/2021 to 12/2021"},
+			want: []ExperienceEntry{
+				{Title: "Teaching assistant", Start: stringp("2021-01"), End: stringp("2021-12"), Months: 12, Details: []string{}},
+			},
+			months: 12,
+		},
+		{
+			name: "overlapping entries count once",
+			lines: []string{
+				"Part-time tutor, Jan 2022 - Dec 2022",
+				"Intern, Jun 2022 - Aug 2022",
+			},
+			want: []ExperienceEntry{
+				{Title: "Part-time tutor", Start: stringp("2022-01"), End: stringp("2022-12"), Months: 12, Details: []string{}},
+				{Title: "Intern", Start: stringp("2022-06"), End: stringp("2022-08"), Months: 3, Details: []string{}},
+			},
+			months: 12,
+		},
+		{
+			name:  "no dates",
+			lines: []string{"Freelance web developer", "Built sites for local shops"},
+			want:  []ExperienceEntry{{Title: "Freelance web developer", Details: []string{"Built sites for local shops"}}},
+		},
+		{
+			name: "empty",
+			want: []ExperienceEntry{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, months := resumeExperience(test.lines, now)
+			if !reflect.DeepEqual(got, test.want) || months != test.months {
+				t.Errorf("resumeExperience() = %+v, %d months, want %+v, %d months", got, months, test.want, test.months)
+			}
+		})
+	}
+}
+
+// testSkillCatalogue is a small skills catalogue with Thai names and aliases
+var testSkillCatalogue = []Skill{
+	{ID: 1, Name: "Go", Aliases: []string{"Golang"}},
+	{ID: 2, Name: "Java"},
+	{ID: 3, Name: "JavaScript", Aliases: []string{"JS"}},
+	{ID: 4, Name: "SQL"},
+	{ID: 5, Name: "Machine Learning", NameTH: "การเรียนรู้ของเครื่อง"},
+	{ID: 6, Name: "C++"},
+	{ID: 7, Name: "Docker"},
+}
+
+func TestResumeSkills(t *testing.T) {
+	tests := []struct {
+		name       string
+		skillLines []string
+		otherLines []string
+		skills     []int
+		unmatched  []string
+	}{
+		{
+			name:       "names, aliases and labels",
+			skillLines: []string{"Programming languages: Golang, JavaScript, C++", "Tools: Docker / Figma"},
+			skills:     []int{1, 3, 6, 7},
+			unmatched:  []string{"Figma"},
+		},
+		{
+			name:       "longer names outside the skills section",
+			otherLines: []string{"Built a machine learning pipeline in JavaScript", "Let's go hiking"},
+			skills:     []int{3, 5},
+		},
+		{
+			name:       "Thai names",
+			skillLines: []string{"• การเรียนรู้ของเครื่อง", "• ภาษาอังกฤษ"},
+			skills:     []int{5},
+			unmatched:  []string{"ภาษาอังกฤษ"},
+		},
+		{
+			name:       "Java is not JavaScript",
+			skillLines: []string{"JavaScript (React)"},
+			skills:     []int{3},
+			unmatched:  []string{"JavaScript (React"},
+		},
+		{
+			name: "nothing",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			skills, unmatched := resumeSkills(test.skillLines, test.otherLines, testSkillCatalogue)
+			ids := []int{}
+			for _, skill := range skills {
+				if skill.Aliases != nil {
+					t.Errorf("skill %s has aliases %v, want none", skill.Name, skill.Aliases)
+				}
+				ids = append(ids, skill.ID)
+			}
+			wantSkills, wantUnmatched := test.skills, test.unmatched
+			if wantSkills == nil {
+				wantSkills = []int{}
+			}
+			if wantUnmatched == nil {
+				wantUnmatched = []string{}
+			}
+			if !reflect.DeepEqual(ids, wantSkills) || !reflect.DeepEqual(unmatched, wantUnmatched) {
+				t.Errorf("resumeSkills() = %v, %q, want %v, %q", ids, unmatched, wantSkills, wantUnmatched)
+			}
+		})
+	}
+}
+
+func TestContainsResumeTerm(t *testing.T) {
+	tests := []struct {
+		text string
+		term string
+		want bool
+	}{
+		{"java developer", "java", true},
+		{"javascript developer", "java", false},
+		{"golang, go", "go", true},
+		{"c# and .net", "c#", true},
+		{"c++", "c", false},
+		{"node.js", "js", true},
+		{"ใช้งานไพทอนได้ดี", "ไพทอน", true},
+		{"", "go", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text+"/"+test.term, func(t *testing.T) {
+			if got := containsResumeTerm(test.text, test.term); got != test.want {
+				t.Errorf("containsResumeTerm(%q, %q) = %v, want %v", test.text, test.term, got, test.want)
+			}
+		})
+	}
+}
+
+func TestDraftResume(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	text := strings.Join([]string{
+		"Curriculum Vitae",
+		"Somchai Jaidee",
+		"somchai.j@example.com | 081-234-5678 | linkedin.com/in/somchai",
+		"Education",
+		"Kasetsart University | B.Eng. Computer Engineering | 2019 - 2023",
+		"GPA 3.45",
+		"Work Experience",
+		"Software Engineer Intern, Agoda | Jun 2022 - Aug 2022",
+		"• Built internal tools in Go",
+		"Backend Developer, LINE MAN | Jul 2023 - Present",
+		"Skills: Go, SQL, Figma",
+	}, "\n")
+
+	draft := DraftResume(text, testSkillCatalogue, now)
+
+	profile := draft.Profile
+	checks := []struct {
+		field string
+		ok    bool
+		got   interface{}
+	}{
+		{"full_name", profile.FullName != nil && *profile.FullName == "Somchai Jaidee", profile.FullName},
+		{"phone", profile.Phone != nil && *profile.Phone == "081-234-5678", profile.Phone},
+		{"university", profile.University != nil && *profile.University == "Kasetsart University", profile.University},
+		{"graduation_year", profile.GraduationYear != nil && *profile.GraduationYear == 2023, profile.GraduationYear},
+		{"gpa", profile.GPA != nil && *profile.GPA == 3.45, profile.GPA},
+		// Jun-Aug 2022 and Jul 2023-Oct 2026 are 3 + 40 months
+		{"experience_years", profile.ExperienceYears != nil && *profile.ExperienceYears == 3, profile.ExperienceYears},
+		{"email", draft.Contact.Email != nil && *draft.Contact.Email == "somchai.j@example.com", draft.Contact.Email},
+		{"links", reflect.DeepEqual(draft.Contact.Links, []string{"linkedin.com/in/somchai"}), draft.Contact.Links},
+		{"education", len(draft.Education) == 1, draft.Education},
+		{"experience", len(draft.Experience) == 2 && draft.Experience[1].Current, draft.Experience},
+		{"skills", len(draft.Skills) == 2 && draft.Skills[0].Name == "Go" && draft.Skills[1].Name == "SQL", draft.Skills},
+		{"unmatched_skills", reflect.DeepEqual(draft.UnmatchedSkills, []string{"Figma"}), draft.UnmatchedSkills},
+		{"text", draft.Text == text, draft.Text},
+	}
+	for _, check := range checks {
+		if !check.ok {
+			t.Errorf("DraftResume() %s = %s", check.field, formatValue(check.got))
+		}
+	}
+}
+
+func TestDraftResumeEmpty(t *testing.T) {
+	draft := DraftResume("", testSkillCatalogue, time.Now())
+	// The lists are empty rather than null in JSON
+	if draft.Education == nil || draft.Experience == nil || draft.Skills == nil || draft.UnmatchedSkills == nil || draft.Contact.Links == nil {
+		t.Errorf("DraftResume(\"\") = %+v, want empty lists", draft)
+	}
+	if draft.Profile != (ProfileDraft{}) {
+		t.Errorf("DraftResume(\"\") profile = %+v, want none", draft.Profile)
+	}
+}
+
+// formatEntries formats education entries with their pointer fields dereferenced
+func formatEntries(entries []EducationEntry) string {
+	var parts []string
+	for _, entry := range entries {
+		degree := "<nil>"
+		if entry.Degree != nil {
+			degree = *entry.Degree
+		}
+		gpa := "<nil>"
+		if entry.GPA != nil {
+			gpa = formatValue(*entry.GPA)
+		}
+		parts = append(parts, "{"+entry.Institution+" | "+degree+" | "+formatInt(entry.StartYear)+"-"+formatInt(entry.EndYear)+
+			" | GPA "+gpa+" | "+strings.Join(entry.Details, "; ")+"}")
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func nilOrEntry(entry *EducationEntry) []EducationEntry {
+	if entry == nil {
+		return nil
+	}
+	return []EducationEntry{*entry}
+}
+
+func formatInt(value *int) string {
+	if value == nil {
+		return "<nil>"
+	}
+	return formatValue(*value)
+}
+
+// formatValue formats a value, dereferencing pointers
+func formatValue(value interface{}) string {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "<nil>"
+		}
+		value = v.Elem().Interface()
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,538 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Resumes: a fresh grad keeps several named resumes, each with the versions its file has had. Applications
+// point at the version that was current when they were submitted, so replacing a resume never changes what an
+// employer already received.
+
+// Limits on resumes
+const (
+	MaxResumes          = 10
+	MaxResumeNameLength = 100
+	MaxResumeBytes      = 10 << 20
+	MaxResumeLinkLength = 500
+)
+
+// Content types of uploaded resumes
+const (
+	ResumeContentTypePDF  = "application/pdf"
+	ResumeContentTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// ResumeVersion is one file of a resume: an uploaded file, or an external link for FileLink versions
+type ResumeVersion struct {
+	ID          int64   `json:"resume_version_id"`
+	Version     int     `json:"version"`
+	FileName    *string `json:"file_name"`
+	ContentType *string `json:"content_type"`
+	SizeBytes   *int64  `json:"size_bytes"`
+	FileLink    *string `json:"file_link"`
+	CreatedAt   string  `json:"created_at"`
+	storageKey  *string
+}
+
+// Resume is a named resume with its versions, newest first
+type Resume struct {
+	ID        int64           `json:"resume_id"`
+	Name      string          `json:"name"`
+	Default   bool            `json:"is_default"`
+	CreatedAt string          `json:"created_at"`
+	UpdatedAt string          `json:"updated_at"`
+	Versions  []ResumeVersion `json:"versions"`
+}
+
+// ResumeInput is a resume file to save: an uploaded File or a FileLink, with the resume's Name when creating one
+type ResumeInput struct {
+	Name     string
+	File     *multipart.FileHeader
+	FileLink string
+}
+
+// ResumeUpdate renames a resume or makes it the default; nil fields are left unchanged
+type ResumeUpdate struct {
+	Name    *string `json:"name"`
+	Default *bool   `json:"is_default"`
+}
+
+// ProfileSnapshot is the applicant's profile as it was when an application was submitted
+type ProfileSnapshot struct {
+	FullName          *string  `json:"full_name"`
+	Phone             *string  `json:"phone"`
+	University        *string  `json:"university"`
+	GraduationYear    *int     `json:"graduation_year"`
+	GPA               *float64 `json:"gpa"`
+	Location          *string  `json:"location"`
+	ExperienceYears   *int     `json:"experience_years"`
+	PreferredLocation *string  `json:"preferred_location"`
+	ExpectedSalary    *float64 `json:"expected_salary"`
+	PreferredJobLevel *string  `json:"preferred_job_level"`
+	Skills            []Skill  `json:"skills"`
+}
+
+// Errors returned by the resume functions
+var (
+	ErrResumeNotFound        = errors.New("resume not found")
+	ErrResumeProfileNotFound = errors.New("profile not found")
+	ErrResumeNameTaken       = errors.New("a resume with this name already exists")
+	ErrResumeLimit           = fmt.Errorf("at most %d resumes can be kept", MaxResumes)
+	ErrResumeFileType        = errors.New("must be a PDF or DOCX file")
+)
+
+// ParseResumeInput reads a resume from a multipart form with name and file fields, or from a JSON body
+// {"name": ..., "file_link": ...}. The name is only required, and only read, when withName is set.
+func ParseResumeInput(c *gin.Context, withName bool) (ResumeInput, FieldErrors, error) {
+	var input ResumeInput
+	if c.ContentType() == "multipart/form-data" {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxResumeBytes+(1<<20))
+		form, err := c.MultipartForm()
+		if err != nil {
+			return input, nil, err
+		}
+		input.Name = strings.Join(form.Value["name"], " ")
+		if files := form.File["file"]; len(files) > 0 {
+			input.File = files[0]
+		}
+	} else {
+		var request struct {
+			Name     string `json:"name"`
+			FileLink string `json:"file_link"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			return input, nil, err
+		}
+		input.Name, input.FileLink = request.Name, strings.TrimSpace(request.FileLink)
+	}
+
+	errs := FieldErrors{}
+	if withName {
+		input.Name = strings.TrimSpace(input.Name)
+		if message := resumeNameError(input.Name); message != "" {
+			errs["name"] = message
+		}
+	}
+	switch {
+	case input.File == nil && input.FileLink == "":
+		errs["file"] = "is required, or a file_link"
+	case input.File != nil && input.File.Size > MaxResumeBytes:
+		errs["file"] = fmt.Sprintf("must be at most %d MB", MaxResumeBytes>>20)
+	case input.File == nil:
+		if link, err := url.Parse(input.FileLink); err != nil || (link.Scheme != "https" && link.Scheme != "http") || link.Host == "" {
+			errs["file_link"] = "must be an http or https URL"
+		} else if len(input.FileLink) > MaxResumeLinkLength {
+			errs["file_link"] = fmt.Sprintf("must be at most %d characters", MaxResumeLinkLength)
+		}
+	}
+	return input, errs, nil
+}
+
+// Validate checks the update, trimming the new name
+func (update *ResumeUpdate) Validate() FieldErrors {
+	errs := FieldErrors{}
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		update.Name = &name
+		if message := resumeNameError(name); message != "" {
+			errs["name"] = message
+		}
+	}
+	if update.Default != nil && !*update.Default {
+		errs["is_default"] = "can only be true; make another resume the default instead"
+	}
+	if update.Name == nil && update.Default == nil {
+		errs["name"] = "is required when is_default is not set"
+	}
+	return errs
+}
+
+// resumeNameError describes what is wrong with a trimmed resume name, or returns ""
+func resumeNameError(name string) string {
+	if name == "" {
+		return "must not be empty"
+	}
+	if len([]rune(name)) > MaxResumeNameLength {
+		return fmt.Sprintf("must be at most %d characters", MaxResumeNameLength)
+	}
+	return ""
+}
+
+// resumeContentType returns the content type of an upload named fileName whose first bytes were detected as
+// sniffed, if it is a PDF or DOCX file. DOCX files are ZIP archives, so their extension decides.
+func resumeContentType(fileName, sniffed string) (string, bool) {
+	switch {
+	case sniffed == ResumeContentTypePDF:
+		return ResumeContentTypePDF, true
+	case sniffed == "application/zip" && strings.EqualFold(filepath.Ext(fileName), ".docx"):
+		return ResumeContentTypeDOCX, true
+	}
+	return "", false
+}
+
+// CreateResume saves the input as version 1 of a new named resume of the profile. The profile's first resume
+// becomes its default.
+func CreateResume(db *sql.DB, storage Storage, profileID int, input ResumeInput) (int64, error) {
+	var resumeID int64
+	err := saveResumeVersion(db, storage, input, func(tx *sql.Tx, version ResumeVersion) error {
+		// Locking the profile serializes the limit and name checks of concurrent requests
+		lockQuery := "SELECT freshgradprofile_id FROM freshgradprofiles WHERE freshgradprofile_id = ? FOR UPDATE"
+		if err := tx.QueryRow(lockQuery, profileID).Scan(&profileID); err != nil {
+			if err == sql.ErrNoRows {
+				return ErrResumeProfileNotFound
+			}
+			return fmt.Errorf("error locking profile: %v", err)
+		}
+		var count int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM resumes WHERE freshgradprofile_id = ? AND deleted_at IS NULL", profileID).Scan(&count); err != nil {
+			return fmt.Errorf("error counting resumes: %v", err)
+		}
+		if count >= MaxResumes {
+			return ErrResumeLimit
+		}
+		if err := checkResumeName(tx, profileID, input.Name, 0); err != nil {
+			return err
+		}
+
+		result, err := tx.Exec("INSERT INTO resumes (freshgradprofile_id, name, is_default) VALUES (?, ?, ?)", profileID, input.Name, count == 0)
+		if err != nil {
+			return fmt.Errorf("error saving resume: %v", err)
+		}
+		if resumeID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("error reading resume ID: %v", err)
+		}
+		version.Version = 1
+		return insertResumeVersion(tx, resumeID, &version)
+	})
+	return resumeID, err
+}
+
+// AddResumeVersion saves the input as the next version of one of the profile's resumes
+func AddResumeVersion(db *sql.DB, storage Storage, profileID int, resumeID interface{}, input ResumeInput) (ResumeVersion, error) {
+	var saved ResumeVersion
+	err := saveResumeVersion(db, storage, input, func(tx *sql.Tx, version ResumeVersion) error {
+		if _, err := lockResume(tx, profileID, resumeID); err != nil {
+			return err
+		}
+		if err := tx.QueryRow("SELECT COALESCE(MAX(version), 0) + 1 FROM resume_versions WHERE resume_id = ?", resumeID).Scan(&version.Version); err != nil {
+			return fmt.Errorf("error numbering resume version: %v", err)
+		}
+		if err := insertResumeVersion(tx, resumeID, &version); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE resumes SET updated_at = CURRENT_TIMESTAMP WHERE resume_id = ?", resumeID); err != nil {
+			return fmt.Errorf("error updating resume: %v", err)
+		}
+		saved = version
+		return nil
+	})
+	return saved, err
+}
+
+// saveResumeVersion stores the input's uploaded file, if any, then runs save in a transaction. The stored file is
+// removed again when save fails.
+func saveResumeVersion(db *sql.DB, storage Storage, input ResumeInput, save func(tx *sql.Tx, version ResumeVersion) error) error {
+	var version ResumeVersion
+	if input.File != nil {
+		attachment, err := storeAttachment(storage, input.File)
+		if err != nil {
+			return err
+		}
+		version.storageKey = &attachment.storageKey
+		contentType, ok := resumeContentType(attachment.FileName, attachment.ContentType)
+		if !ok {
+			removeResumeFile(storage, attachment.storageKey)
+			return ErrResumeFileType
+		}
+		version.FileName, version.ContentType, version.SizeBytes = &attachment.FileName, &contentType, &attachment.SizeBytes
+	} else {
+		version.FileLink = &input.FileLink
+	}
+
+	err := func() error {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("error starting transaction: %v", err)
+		}
+		defer tx.Rollback()
+		if err := save(tx, version); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}()
+	if err != nil && version.storageKey != nil {
+		removeResumeFile(storage, *version.storageKey)
+	}
+	return err
+}
+
+// removeResumeFile deletes a stored resume file that could not be saved, logging failures
+func removeResumeFile(storage Storage, key string) {
+	if err := storage.Delete(key); err != nil {
+		log.Printf("Error removing resume file %s: %v", key, err)
+	}
+}
+
+// insertResumeVersion saves version as a version of the resume, filling in its ID
+func insertResumeVersion(tx *sql.Tx, resumeID interface{}, version *ResumeVersion) error {
+	query := "INSERT INTO resume_versions (resume_id, version, file_name, content_type, size_bytes, storage_key, file_link) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?)"
+	result, err := tx.Exec(query, resumeID, version.Version, version.FileName, version.ContentType, version.SizeBytes,
+		version.storageKey, version.FileLink)
+	if err != nil {
+		return fmt.Errorf("error saving resume version: %v", err)
+	}
+	if version.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("error reading resume version ID: %v", err)
+	}
+	return nil
+}
+
+// lockResume locks one of the profile's resumes, returning whether it is the default, or ErrResumeNotFound
+func lockResume(tx *sql.Tx, profileID int, resumeID interface{}) (bool, error) {
+	var isDefault bool
+	query := "SELECT is_default FROM resumes WHERE resume_id = ? AND freshgradprofile_id = ? AND deleted_at IS NULL FOR UPDATE"
+	if err := tx.QueryRow(query, resumeID, profileID).Scan(&isDefault); err != nil {
+		if err == sql.ErrNoRows {
+			return false, ErrResumeNotFound
+		}
+		return false, fmt.Errorf("error loading resume: %v", err)
+	}
+	return isDefault, nil
+}
+
+// checkResumeName returns ErrResumeNameTaken when another of the profile's resumes, other than excludeID, has name
+func checkResumeName(q Querier, profileID int, name string, excludeID interface{}) error {
+	var taken bool
+	query := "SELECT EXISTS (SELECT 1 FROM resumes WHERE freshgradprofile_id = ? AND deleted_at IS NULL AND name = ? AND resume_id <> ?)"
+	if err := q.QueryRow(query, profileID, name, excludeID).Scan(&taken); err != nil {
+		return fmt.Errorf("error checking resume name: %v", err)
+	}
+	if taken {
+		return ErrResumeNameTaken
+	}
+	return nil
+}
+
+// UpdateResume renames one of the profile's resumes or makes it the default
+func UpdateResume(tx *sql.Tx, profileID int, resumeID interface{}, update ResumeUpdate) error {
+	if _, err := lockResume(tx, profileID, resumeID); err != nil {
+		return err
+	}
+	if update.Name != nil {
+		if err := checkResumeName(tx, profileID, *update.Name, resumeID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE resumes SET name = ? WHERE resume_id = ?", *update.Name, resumeID); err != nil {
+			return fmt.Errorf("error renaming resume: %v", err)
+		}
+	}
+	if update.Default != nil && *update.Default {
+		query := "UPDATE resumes SET is_default = (resume_id = ?) WHERE freshgradprofile_id = ? AND deleted_at IS NULL"
+		if _, err := tx.Exec(query, resumeID, profileID); err != nil {
+			return fmt.Errorf("error changing default resume: %v", err)
+		}
+	}
+	return nil
+}
+
+// DeleteResume removes one of the profile's resumes from its list; its files stay for the applications that
+// used them. Deleting the default resume makes the most recently updated remaining one the default.
+func DeleteResume(tx *sql.Tx, profileID int, resumeID interface{}) error {
+	isDefault, err := lockResume(tx, profileID, resumeID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE resumes SET deleted_at = CURRENT_TIMESTAMP, is_default = FALSE WHERE resume_id = ?", resumeID); err != nil {
+		return fmt.Errorf("error deleting resume: %v", err)
+	}
+	if isDefault {
+		query := "UPDATE resumes SET is_default = TRUE WHERE freshgradprofile_id = ? AND deleted_at IS NULL " +
+			"ORDER BY updated_at DESC, resume_id DESC LIMIT 1"
+		if _, err := tx.Exec(query, profileID); err != nil {
+			return fmt.Errorf("error changing default resume: %v", err)
+		}
+	}
+	return nil
+}
+
+// resumeVersionColumns are the ResumeVersion columns of resume_versions rv
+const resumeVersionColumns = "rv.resume_version_id, rv.version, rv.file_name, rv.content_type, rv.size_bytes, rv.file_link, rv.created_at, rv.storage_key"
+
+// scanTargets returns pointers to the version's fields in resumeVersionColumns order
+func (version *ResumeVersion) scanTargets() []interface{} {
+	return []interface{}{
+		&version.ID, &version.Version, &version.FileName, &version.ContentType, &version.SizeBytes, &version.FileLink,
+		&version.CreatedAt, &version.storageKey,
+	}
+}
+
+// LoadResumes reads the profile's resumes, the default first, with their versions
+func LoadResumes(q Querier, profileID int) ([]Resume, error) {
+	rows, err := q.Query("SELECT resume_id, name, is_default, created_at, updated_at FROM resumes "+
+		"WHERE freshgradprofile_id = ? AND deleted_at IS NULL ORDER BY is_default DESC, updated_at DESC, resume_id DESC", profileID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying resumes: %v", err)
+	}
+	defer rows.Close()
+
+	resumes := []Resume{}
+	index := map[int64]int{}
+	for rows.Next() {
+		var resume Resume
+		if err := rows.Scan(&resume.ID, &resume.Name, &resume.Default, &resume.CreatedAt, &resume.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning resume: %v", err)
+		}
+		resume.Versions = []ResumeVersion{}
+		index[resume.ID] = len(resumes)
+		resumes = append(resumes, resume)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	versionRows, err := q.Query("SELECT rv.resume_id, "+resumeVersionColumns+" FROM resume_versions rv "+
+		"INNER JOIN resumes r ON r.resume_id = rv.resume_id "+
+		"WHERE r.freshgradprofile_id = ? AND r.deleted_at IS NULL ORDER BY rv.version DESC", profileID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying resume versions: %v", err)
+	}
+	defer versionRows.Close()
+	for versionRows.Next() {
+		var resumeID int64
+		var version ResumeVersion
+		if err := versionRows.Scan(append([]interface{}{&resumeID}, version.scanTargets()...)...); err != nil {
+			return nil, fmt.Errorf("error scanning resume version: %v", err)
+		}
+		if i, ok := index[resumeID]; ok {
+			resumes[i].Versions = append(resumes[i].Versions, version)
+		}
+	}
+	return resumes, versionRows.Err()
+}
+
+// ApplicationResumeVersion returns the current version of the profile's resume to submit with an application:
+// resumeID's, or the default resume's when resumeID is nil. It returns nil when no resume is chosen and the
+// profile has no default, and ErrResumeNotFound when resumeID is not one of the profile's resumes.
+func ApplicationResumeVersion(q Querier, profileID int, resumeID *int) (interface{}, error) {
+	condition, args := "r.is_default", []interface{}{profileID}
+	if resumeID != nil {
+		condition, args = "r.resume_id = ?", append(args, *resumeID)
+	}
+	var versionID int64
+	query := "SELECT rv.resume_version_id FROM resumes r " +
+		"INNER JOIN resume_versions rv ON rv.resume_id = r.resume_id " +
+		"WHERE r.freshgradprofile_id = ? AND r.deleted_at IS NULL AND " + condition + " ORDER BY rv.version DESC LIMIT 1"
+	if err := q.QueryRow(query, args...).Scan(&versionID); err != nil {
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("error loading resume: %v", err)
+		}
+		if resumeID != nil {
+			return nil, ErrResumeNotFound
+		}
+		return nil, nil
+	}
+	return versionID, nil
+}
+
+// SnapshotProfile returns the profile with its skills as the JSON stored with an application
+func SnapshotProfile(q Querier, profileID int) ([]byte, error) {
+	var snapshot ProfileSnapshot
+	query := "SELECT full_name, phone, university, graduation_year, gpa, location, experience_years, preferred_location, " +
+		"expected_salary, preferred_job_level FROM freshgradprofiles WHERE freshgradprofile_id = ?"
+	if err := q.QueryRow(query, profileID).Scan(&snapshot.FullName, &snapshot.Phone, &snapshot.University,
+		&snapshot.GraduationYear, &snapshot.GPA, &snapshot.Location, &snapshot.ExperienceYears, &snapshot.PreferredLocation,
+		&snapshot.ExpectedSalary, &snapshot.PreferredJobLevel); err != nil {
+		return nil, fmt.Errorf("error loading profile: %v", err)
+	}
+
+	rows, err := q.Query("SELECT s.skill_id, s.name, s.name_th FROM freshgradprofile_skills ps "+
+		"INNER JOIN skills s ON s.skill_id = ps.skill_id WHERE ps.freshgradprofile_id = ? ORDER BY s.name", profileID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying profile skills: %v", err)
+	}
+	defer rows.Close()
+	snapshot.Skills = []Skill{}
+	for rows.Next() {
+		var skill Skill
+		if err := rows.Scan(&skill.ID, &skill.Name, &skill.NameTH); err != nil {
+			return nil, fmt.Errorf("error scanning profile skill: %v", err)
+		}
+		snapshot.Skills = append(snapshot.Skills, skill)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(snapshot)
+}
+
+// WriteResumeVersion streams the resume version matching condition over resume_versions rv and resumes r, or
+// redirects to it for linked versions
+func WriteResumeVersion(c *gin.Context, q Querier, condition string, args ...interface{}) {
+	var version ResumeVersion
+	query := "SELECT " + resumeVersionColumns + " FROM resume_versions rv " +
+		"INNER JOIN resumes r ON r.resume_id = rv.resume_id WHERE " + condition
+	if err := q.QueryRow(query, args...).Scan(version.scanTargets()...); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Resume not found"})
+			return
+		}
+		log.Printf("Error loading resume version: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Database query error"})
+		return
+	}
+	if version.storageKey == nil {
+		c.Redirect(http.StatusFound, *version.FileLink)
+		return
+	}
+
+	storage, err := NewStorage()
+	if err != nil {
+		log.Printf("File storage error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "File storage error"})
+		return
+	}
+	file, err := storage.Open(*version.storageKey)
+	if err != nil {
+		log.Printf("Error opening resume version %d: %v", version.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "File storage error"})
+		return
+	}
+	defer file.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": *version.FileName})
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, *version.SizeBytes, *version.ContentType, file,
+		map[string]string{"Content-Disposition": disposition})
+}
+
+// RespondResumeError writes the response for errors of the resume functions, returning false for other errors
+func RespondResumeError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, ErrResumeFileType):
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Validation failed", "errors": FieldErrors{"file": err.Error()}})
+	case errors.Is(err, ErrResumeUnreadable), errors.Is(err, ErrResumeNoText):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"status": "error", "message": ErrResumeUnreadable.Error(), "details": err.Error()})
+	case errors.Is(err, ErrResumeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Resume not found"})
+	case errors.Is(err, ErrResumeProfileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Profile not found"})
+	case errors.Is(err, ErrResumeNameTaken), errors.Is(err, ErrResumeLimit):
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
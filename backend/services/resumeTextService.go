@@ -0,0 +1,870 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// Text extraction for resume parsing, offline and with the standard library only. DOCX text comes from
+// word/document.xml. PDF text comes from the pages' content streams, decoded through the fonts' ToUnicode maps
+// when they have one; scanned PDFs and PDFs with other stream filters yield no text.
+
+// maxResumeTextBytes caps how much decompressed data is read from one resume, against zip and flate bombs
+const maxResumeTextBytes = 32 << 20
+
+// Errors returned by ExtractResumeText
+var (
+	ErrResumeUnreadable = errors.New("the file could not be read as a PDF or DOCX document")
+	ErrResumeNoText     = errors.New("no text was found in the file; it may be a scanned image")
+)
+
+// ExtractResumeText returns the plain text of a PDF or DOCX resume, one line per paragraph or text line
+func ExtractResumeText(contentType string, data []byte) (string, error) {
+	var text string
+	var err error
+	switch contentType {
+	case ResumeContentTypePDF:
+		text, err = pdfText(data)
+	case ResumeContentTypeDOCX:
+		text, err = docxText(data)
+	default:
+		return "", ErrResumeFileType
+	}
+	if err != nil {
+		return "", err
+	}
+	text = cleanResumeText(text)
+	if text == "" {
+		return "", ErrResumeNoText
+	}
+	return text, nil
+}
+
+// cleanResumeText drops control characters, collapses spaces within lines and removes empty lines
+func cleanResumeText(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Map(func(r rune) rune {
+			switch {
+			case r == '\t' || r == ' ':
+				return ' '
+			case unicode.IsControl(r) || r == unicode.ReplacementChar:
+				return -1
+			}
+			return r
+		}, line)
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// docxText reads the paragraphs of word/document.xml
+func docxText(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", ErrResumeUnreadable
+	}
+	var document *zip.File
+	for _, file := range archive.File {
+		if file.Name == "word/document.xml" {
+			document = file
+			break
+		}
+	}
+	if document == nil {
+		return "", ErrResumeUnreadable
+	}
+	reader, err := document.Open()
+	if err != nil {
+		return "", ErrResumeUnreadable
+	}
+	defer reader.Close()
+
+	var text strings.Builder
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxResumeTextBytes))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrResumeUnreadable, err)
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			switch token.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteByte('\t')
+			case "br", "cr":
+				text.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch token.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteByte('\n')
+			case "tc":
+				text.WriteByte('\t')
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(token)
+			}
+		}
+	}
+	return text.String(), nil
+}
+
+// pdfReader indexes the objects of a PDF file by number, including those inside object streams
+type pdfReader struct {
+	objects  map[int][]byte
+	budget   int
+	cmaps    map[int]*pdfCMap
+	fontMaps map[int]map[string]*pdfCMap
+}
+
+var (
+	pdfObjectPattern = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	pdfRefPattern    = regexp.MustCompile(`^(\d+)\s+\d+\s+R`)
+	pdfRefsPattern   = regexp.MustCompile(`(\d+)\s+\d+\s+R`)
+)
+
+// pdfText reads the text of every page in page order
+func pdfText(data []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\r\n "), []byte("%PDF-")) {
+		return "", ErrResumeUnreadable
+	}
+	reader := &pdfReader{objects: map[int][]byte{}, budget: maxResumeTextBytes, cmaps: map[int]*pdfCMap{}, fontMaps: map[int]map[string]*pdfCMap{}}
+
+	// Later definitions of an object replace earlier ones, as incremental updates do
+	matches := pdfObjectPattern.FindAllSubmatchIndex(data, -1)
+	for i, match := range matches {
+		number, _ := strconv.Atoi(string(data[match[2]:match[3]]))
+		end := len(data)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		body := data[match[1]:end]
+		if at := bytes.LastIndex(body, []byte("endobj")); at >= 0 {
+			body = body[:at]
+		}
+		reader.objects[number] = body
+	}
+	for number := range reader.objects {
+		reader.expandObjectStream(number)
+	}
+
+	pages := reader.pages()
+
+	var text strings.Builder
+	for _, page := range pages {
+		dict := pdfDict(reader.objects[page])
+		fonts := reader.pageFonts(page)
+		for _, ref := range pdfRefs(pdfDictValue(dict, "Contents")) {
+			if content, ok := reader.stream(ref); ok {
+				writePDFContentText(&text, content, fonts)
+			}
+		}
+		text.WriteByte('\n')
+	}
+	return text.String(), nil
+}
+
+// pages returns the page objects in page tree order, or in object number order when there is no readable tree
+func (reader *pdfReader) pages() []int {
+	var pages []int
+	seen := map[int]bool{}
+	var walk func(number, depth int)
+	walk = func(number, depth int) {
+		if seen[number] || depth > 32 {
+			return
+		}
+		seen[number] = true
+		dict := pdfDict(reader.objects[number])
+		switch pdfName(pdfDictValue(dict, "Type")) {
+		case "Page":
+			pages = append(pages, number)
+		case "Pages":
+			for _, kid := range pdfRefs(pdfDictValue(dict, "Kids")) {
+				walk(kid, depth+1)
+			}
+		}
+	}
+	for _, body := range reader.objects {
+		dict := pdfDict(body)
+		if pdfName(pdfDictValue(dict, "Type")) == "Catalog" {
+			if refs := pdfRefs(pdfDictValue(dict, "Pages")); len(refs) > 0 {
+				walk(refs[0], 0)
+			}
+			if len(pages) > 0 {
+				return pages
+			}
+		}
+	}
+
+	for number, body := range reader.objects {
+		if pdfName(pdfDictValue(pdfDict(body), "Type")) == "Page" {
+			pages = append(pages, number)
+		}
+	}
+	sort.Ints(pages)
+	return pages
+}
+
+// expandObjectStream adds the objects of an object stream that are not defined directly
+func (reader *pdfReader) expandObjectStream(number int) {
+	dict := pdfDict(reader.objects[number])
+	if pdfName(pdfDictValue(dict, "Type")) != "ObjStm" {
+		return
+	}
+	content, ok := reader.stream(number)
+	if !ok {
+		return
+	}
+	count, _ := strconv.Atoi(string(pdfDictValue(dict, "N")))
+	first, _ := strconv.Atoi(string(pdfDictValue(dict, "First")))
+	if first <= 0 || first > len(content) {
+		return
+	}
+	header := strings.Fields(string(content[:first]))
+	for i := 0; i < count && 2*i+1 < len(header); i++ {
+		objectNumber, err1 := strconv.Atoi(header[2*i])
+		offset, err2 := strconv.Atoi(header[2*i+1])
+		end := len(content) - first
+		if 2*i+3 < len(header) {
+			end, _ = strconv.Atoi(header[2*i+3])
+		}
+		if err1 != nil || err2 != nil || offset < 0 || offset > end || first+end > len(content) {
+			return
+		}
+		if _, defined := reader.objects[objectNumber]; !defined {
+			reader.objects[objectNumber] = content[first+offset : first+end]
+		}
+	}
+}
+
+// stream returns the decoded stream of an object, if it has one without filters or with FlateDecode
+func (reader *pdfReader) stream(number int) ([]byte, bool) {
+	body, ok := reader.objects[number]
+	if !ok {
+		return nil, false
+	}
+	start := bytes.Index(body, []byte("stream"))
+	if start < 0 {
+		return nil, false
+	}
+	dict := body[:start]
+	raw := body[start+len("stream"):]
+	raw = bytes.TrimPrefix(raw, []byte("\r"))
+	raw = bytes.TrimPrefix(raw, []byte("\n"))
+	if length, err := strconv.Atoi(string(pdfDictValue(pdfDict(dict), "Length"))); err == nil && length >= 0 && length <= len(raw) {
+		raw = raw[:length]
+	} else if end := bytes.LastIndex(raw, []byte("endstream")); end >= 0 {
+		raw = bytes.TrimRight(raw[:end], "\r\n")
+	}
+
+	filter := string(pdfDictValue(pdfDict(dict), "Filter"))
+	filter = strings.Trim(strings.TrimSpace(filter), "[] \r\n\t")
+	switch filter {
+	case "":
+		return raw, true
+	case "/FlateDecode", "/Fl":
+		if reader.budget <= 0 {
+			return nil, false
+		}
+		inflater, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, false
+		}
+		defer inflater.Close()
+		// Truncated streams still give the text before the damage
+		decoded, _ := io.ReadAll(io.LimitReader(inflater, int64(reader.budget)))
+		reader.budget -= len(decoded)
+		return decoded, len(decoded) > 0
+	}
+	return nil, false
+}
+
+// resolve returns the object a reference value points to, or the value itself
+func (reader *pdfReader) resolve(value []byte) []byte {
+	if match := pdfRefPattern.FindSubmatch(bytes.TrimSpace(value)); match != nil {
+		number, _ := strconv.Atoi(string(match[1]))
+		return pdfDict(reader.objects[number])
+	}
+	return value
+}
+
+// pageFonts maps the font resource names of a page, inherited from its parents when it has no resources of
+// its own, to their ToUnicode maps
+func (reader *pdfReader) pageFonts(page int) map[string]*pdfCMap {
+	number := page
+	var resources []byte
+	for depth := 0; depth < 32; depth++ {
+		dict := pdfDict(reader.objects[number])
+		if resources = pdfDictValue(dict, "Resources"); resources != nil {
+			break
+		}
+		refs := pdfRefs(pdfDictValue(dict, "Parent"))
+		if len(refs) == 0 {
+			break
+		}
+		number = refs[0]
+	}
+	if resources == nil {
+		return nil
+	}
+	if fonts, ok := reader.fontMaps[number]; ok {
+		return fonts
+	}
+
+	fonts := map[string]*pdfCMap{}
+	fontDict := reader.resolve(pdfDictValue(reader.resolve(resources), "Font"))
+	for _, entry := range pdfDictEntries(fontDict) {
+		refs := pdfRefs(entry.value)
+		if len(refs) == 0 {
+			continue
+		}
+		font := pdfDict(reader.objects[refs[0]])
+		if toUnicode := pdfRefs(pdfDictValue(font, "ToUnicode")); len(toUnicode) > 0 {
+			fonts[entry.key] = reader.cmap(toUnicode[0])
+		}
+	}
+	reader.fontMaps[number] = fonts
+	return fonts
+}
+
+// cmap parses the ToUnicode map in an object's stream once
+func (reader *pdfReader) cmap(number int) *pdfCMap {
+	if cmap, ok := reader.cmaps[number]; ok {
+		return cmap
+	}
+	var cmap *pdfCMap
+	if content, ok := reader.stream(number); ok {
+		cmap = parsePDFCMap(content)
+	}
+	reader.cmaps[number] = cmap
+	return cmap
+}
+
+// pdfDict returns the dictionary at the start of an object body, or nil
+func pdfDict(body []byte) []byte {
+	start := bytes.Index(body, []byte("<<"))
+	if start < 0 {
+		return nil
+	}
+	end := pdfMatchingEnd(body, start)
+	return body[start:end]
+}
+
+// pdfMatchingEnd returns the end of the dictionary or array opening at start
+func pdfMatchingEnd(data []byte, start int) int {
+	depth := 0
+	for i := start; i < len(data); i++ {
+		switch data[i] {
+		case '(':
+			i = pdfStringEnd(data, i) - 1
+		case '%':
+			for i < len(data) && data[i] != '\n' && data[i] != '\r' {
+				i++
+			}
+		case '<', '[':
+			depth++
+			if data[i] == '<' && i+1 < len(data) && data[i+1] == '<' {
+				i++
+			} else if data[i] == '<' {
+				// A hex string
+				for i < len(data) && data[i] != '>' {
+					i++
+				}
+				depth--
+			}
+		case '>', ']':
+			depth--
+			if data[i] == '>' && i+1 < len(data) && data[i+1] == '>' {
+				i++
+			}
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(data)
+}
+
+// pdfStringEnd returns the end of the literal string opening at start
+func pdfStringEnd(data []byte, start int) int {
+	depth := 0
+	for i := start; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(data)
+}
+
+type pdfDictEntry struct {
+	key   string
+	value []byte
+}
+
+// pdfDictEntries lists the top-level entries of a dictionary
+func pdfDictEntries(dict []byte) []pdfDictEntry {
+	if len(dict) < 4 {
+		return nil
+	}
+	var entries []pdfDictEntry
+	inner := dict[2 : len(dict)-2]
+	for i := 0; i < len(inner); {
+		if inner[i] != '/' {
+			i++
+			continue
+		}
+		keyEnd := i + 1
+		for keyEnd < len(inner) && !pdfDelimiter(inner[keyEnd]) {
+			keyEnd++
+		}
+		key := string(inner[i+1 : keyEnd])
+		valueStart := keyEnd
+		for valueStart < len(inner) && isPDFSpace(inner[valueStart]) {
+			valueStart++
+		}
+		valueEnd := pdfValueEnd(inner, valueStart)
+		entries = append(entries, pdfDictEntry{key: key, value: inner[valueStart:valueEnd]})
+		i = valueEnd
+	}
+	return entries
+}
+
+// pdfDictValue returns the raw value of a top-level dictionary key, or nil
+func pdfDictValue(dict []byte, key string) []byte {
+	for _, entry := range pdfDictEntries(dict) {
+		if entry.key == key {
+			return entry.value
+		}
+	}
+	return nil
+}
+
+// pdfValueEnd returns the end of the value starting at start: a dictionary, array, string, reference or token
+func pdfValueEnd(data []byte, start int) int {
+	if start >= len(data) {
+		return start
+	}
+	switch data[start] {
+	case '<', '[':
+		return pdfMatchingEnd(data, start)
+	case '(':
+		return pdfStringEnd(data, start)
+	}
+	if match := pdfRefPattern.FindIndex(data[start:]); match != nil {
+		return start + match[1]
+	}
+	end := start + 1
+	for end < len(data) && !pdfDelimiter(data[end]) && !isPDFSpace(data[end]) {
+		end++
+	}
+	return end
+}
+
+// pdfName returns a name value without its slash
+func pdfName(value []byte) string {
+	return strings.TrimPrefix(strings.TrimSpace(string(value)), "/")
+}
+
+// pdfRefs returns the object numbers of the references in a value
+func pdfRefs(value []byte) []int {
+	var numbers []int
+	for _, match := range pdfRefsPattern.FindAllSubmatch(value, -1) {
+		number, _ := strconv.Atoi(string(match[1]))
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+func isPDFSpace(b byte) bool {
+	return b == ' ' || b == '\n' || b == '\r' || b == '\t' || b == '\f' || b == 0
+}
+
+func pdfDelimiter(b byte) bool {
+	return strings.IndexByte("()<>[]{}/%", b) >= 0 || isPDFSpace(b)
+}
+
+// pdfCMap maps character codes of a font to text
+type pdfCMap struct {
+	codeBytes int
+	codes     map[string]string
+}
+
+// parsePDFCMap reads the bfchar and bfrange mappings of a ToUnicode CMap
+func parsePDFCMap(content []byte) *pdfCMap {
+	cmap := &pdfCMap{codeBytes: 1, codes: map[string]string{}}
+	lexer := pdfLexer{data: content}
+	var operands []pdfToken
+	for {
+		token, ok := lexer.next()
+		if !ok {
+			break
+		}
+		if token.kind != pdfTokenOperator {
+			operands = append(operands, token)
+			continue
+		}
+		switch token.text {
+		case "endcodespacerange":
+			if len(operands) > 0 && len(operands[0].bytes) > 1 {
+				cmap.codeBytes = len(operands[0].bytes)
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				cmap.codes[string(operands[i].bytes)] = decodeUTF16(operands[i+1].bytes)
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				cmap.addRange(operands[i].bytes, operands[i+1].bytes, operands[i+2])
+			}
+		}
+		operands = operands[:0]
+	}
+	return cmap
+}
+
+// addRange maps the codes from low to high to consecutive text starting at target, or to the strings of a
+// target array
+func (cmap *pdfCMap) addRange(low, high []byte, target pdfToken) {
+	if len(low) != len(high) || len(low) == 0 || len(low) > 4 {
+		return
+	}
+	from, to := bytesToInt(low), bytesToInt(high)
+	if to < from || to-from > 0xffff {
+		return
+	}
+	for code := from; code <= to; code++ {
+		key := intToBytes(code, len(low))
+		if target.kind == pdfTokenArray {
+			if i := code - from; i < len(target.items) {
+				cmap.codes[string(key)] = decodeUTF16(target.items[i].bytes)
+			}
+			continue
+		}
+		units := append([]byte(nil), target.bytes...)
+		if len(units) >= 2 {
+			last := int(units[len(units)-2])<<8 | int(units[len(units)-1])
+			last += code - from
+			units[len(units)-2], units[len(units)-1] = byte(last>>8), byte(last)
+		}
+		cmap.codes[string(key)] = decodeUTF16(units)
+	}
+}
+
+// decode maps the codes of a string to text
+func (cmap *pdfCMap) decode(data []byte) string {
+	var text strings.Builder
+	for i := 0; i+cmap.codeBytes <= len(data); i += cmap.codeBytes {
+		text.WriteString(cmap.codes[string(data[i:i+cmap.codeBytes])])
+	}
+	return text.String()
+}
+
+func bytesToInt(data []byte) int {
+	value := 0
+	for _, b := range data {
+		value = value<<8 | int(b)
+	}
+	return value
+}
+
+func intToBytes(value, size int) []byte {
+	data := make([]byte, size)
+	for i := size - 1; i >= 0; i-- {
+		data[i] = byte(value)
+		value >>= 8
+	}
+	return data
+}
+
+// decodeUTF16 decodes big-endian UTF-16 text
+func decodeUTF16(data []byte) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// pdfWinAnsi maps the WinAnsiEncoding bytes that differ from Latin-1 and can appear in resumes
+var pdfWinAnsi = map[byte]rune{
+	0x91: '\'', 0x92: '\'', 0x93: '"', 0x94: '"', 0x95: '•', 0x96: '–', 0x97: '—', 0x80: '€',
+}
+
+// decodePDFString decodes a string drawn without a ToUnicode map: UTF-16 with a byte order mark, or a
+// single-byte encoding read as WinAnsi
+func decodePDFString(data []byte) string {
+	if bytes.HasPrefix(data, []byte{0xfe, 0xff}) {
+		return decodeUTF16(data[2:])
+	}
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		if r, ok := pdfWinAnsi[b]; ok {
+			runes[i] = r
+		} else {
+			runes[i] = rune(b)
+		}
+	}
+	return string(runes)
+}
+
+// writePDFContentText writes the text drawn by a content stream, starting a new line whenever the text
+// moves to another line
+func writePDFContentText(text *strings.Builder, content []byte, fonts map[string]*pdfCMap) {
+	lexer := pdfLexer{data: content}
+	var operands []pdfToken
+	var font *pdfCMap
+	// The baseline set by the last Tm, to tell words placed one by one from new lines
+	var lineY float64
+	hasLineY := false
+	show := func(token pdfToken) {
+		if font != nil {
+			text.WriteString(font.decode(token.bytes))
+		} else {
+			text.WriteString(decodePDFString(token.bytes))
+		}
+	}
+	for {
+		token, ok := lexer.next()
+		if !ok {
+			return
+		}
+		if token.kind != pdfTokenOperator {
+			operands = append(operands, token)
+			continue
+		}
+		switch token.text {
+		case "Tf":
+			font = nil
+			if len(operands) >= 2 && operands[len(operands)-2].kind == pdfTokenName {
+				font = fonts[operands[len(operands)-2].text]
+			}
+		case "Tj":
+			if len(operands) > 0 {
+				show(operands[len(operands)-1])
+			}
+		case "'", "\"":
+			text.WriteByte('\n')
+			if len(operands) > 0 {
+				show(operands[len(operands)-1])
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				for _, item := range operands[len(operands)-1].items {
+					if item.kind == pdfTokenString {
+						show(item)
+					} else if item.kind == pdfTokenNumber && item.number < -200 {
+						// A wide negative adjustment is a gap between words
+						text.WriteByte(' ')
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 && operands[len(operands)-1].number != 0 {
+				text.WriteByte('\n')
+				hasLineY = false
+			} else {
+				text.WriteByte(' ')
+			}
+		case "T*":
+			text.WriteByte('\n')
+			hasLineY = false
+		case "Tm":
+			if len(operands) >= 6 {
+				y := operands[len(operands)-1].number
+				if hasLineY && y == lineY {
+					text.WriteByte(' ')
+				} else {
+					text.WriteByte('\n')
+				}
+				lineY, hasLineY = y, true
+			}
+		case "ET":
+			text.WriteByte(' ')
+		case "ID":
+			// Skip the binary data of an inline image
+			if end := bytes.Index(content[lexer.pos:], []byte("EI")); end >= 0 {
+				lexer.pos += end + 2
+			} else {
+				return
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+// Kinds of pdfToken
+const (
+	pdfTokenOperator = iota
+	pdfTokenString
+	pdfTokenName
+	pdfTokenNumber
+	pdfTokenArray
+	pdfTokenDict
+)
+
+// pdfToken is a token of a content stream or CMap; arrays hold their items
+type pdfToken struct {
+	kind   int
+	text   string
+	bytes  []byte
+	number float64
+	items  []pdfToken
+}
+
+// pdfLexer splits PDF content into tokens
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+// next returns the next token, reading arrays whole
+func (lexer *pdfLexer) next() (pdfToken, bool) {
+	data := lexer.data
+	for lexer.pos < len(data) {
+		c := data[lexer.pos]
+		switch {
+		case isPDFSpace(c):
+			lexer.pos++
+		case c == '%':
+			for lexer.pos < len(data) && data[lexer.pos] != '\n' && data[lexer.pos] != '\r' {
+				lexer.pos++
+			}
+		case c == '(':
+			end := pdfStringEnd(data, lexer.pos)
+			token := pdfToken{kind: pdfTokenString, bytes: unescapePDFString(data[lexer.pos+1 : max(end-1, lexer.pos+1)])}
+			lexer.pos = end
+			return token, true
+		case c == '<' && lexer.pos+1 < len(data) && data[lexer.pos+1] == '<':
+			end := pdfMatchingEnd(data, lexer.pos)
+			lexer.pos = end
+			return pdfToken{kind: pdfTokenDict}, true
+		case c == '<':
+			end := bytes.IndexByte(data[lexer.pos:], '>')
+			if end < 0 {
+				end = len(data) - lexer.pos
+			}
+			digits := strings.Map(func(r rune) rune {
+				if unicode.Is(unicode.ASCII_Hex_Digit, r) {
+					return r
+				}
+				return -1
+			}, string(data[lexer.pos+1:lexer.pos+end]))
+			if len(digits)%2 == 1 {
+				digits += "0"
+			}
+			decoded, _ := hex.DecodeString(digits)
+			lexer.pos += end + 1
+			return pdfToken{kind: pdfTokenString, bytes: decoded}, true
+		case c == '[':
+			lexer.pos++
+			array := pdfToken{kind: pdfTokenArray}
+			for {
+				item, ok := lexer.next()
+				if !ok || (item.kind == pdfTokenOperator && item.text == "]") {
+					break
+				}
+				array.items = append(array.items, item)
+			}
+			return array, true
+		case c == ']':
+			lexer.pos++
+			return pdfToken{kind: pdfTokenOperator, text: "]"}, true
+		case c == '/':
+			start := lexer.pos + 1
+			lexer.pos = start
+			for lexer.pos < len(data) && !pdfDelimiter(data[lexer.pos]) {
+				lexer.pos++
+			}
+			return pdfToken{kind: pdfTokenName, text: string(data[start:lexer.pos])}, true
+		default:
+			start := lexer.pos
+			lexer.pos++
+			for lexer.pos < len(data) && !pdfDelimiter(data[lexer.pos]) {
+				lexer.pos++
+			}
+			word := string(data[start:lexer.pos])
+			if number, err := strconv.ParseFloat(word, 64); err == nil {
+				return pdfToken{kind: pdfTokenNumber, text: word, number: number}, true
+			}
+			return pdfToken{kind: pdfTokenOperator, text: word}, true
+		}
+	}
+	return pdfToken{}, false
+}
+
+// unescapePDFString resolves the escapes of a literal string's content
+func unescapePDFString(data []byte) []byte {
+	if bytes.IndexByte(data, '\\') < 0 {
+		return data
+	}
+	var out []byte
+	for i := 0; i < len(data); i++ {
+		if data[i] != '\\' || i+1 >= len(data) {
+			out = append(out, data[i])
+			continue
+		}
+		i++
+		switch c := data[i]; c {
+		case 'n':
+			out = append(out, '\n')
+		case 'r':
+			out = append(out, '\r')
+		case 't':
+			out = append(out, '\t')
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case '\r':
+			// A line continuation
+			if i+1 < len(data) && data[i+1] == '\n' {
+				i++
+			}
+		case '\n':
+		default:
+			if c >= '0' && c <= '7' {
+				value := 0
+				for n := 0; n < 3 && i < len(data) && data[i] >= '0' && data[i] <= '7'; n++ {
+					value = value*8 + int(data[i]-'0')
+					i++
+				}
+				i--
+				out = append(out, byte(value))
+			} else {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
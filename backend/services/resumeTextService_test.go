@@ -0,0 +1,256 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// pdfObject is one indirect object of a test PDF; a non-nil stream is written after the dictionary
+type pdfObject struct {
+	dict   string
+	stream []byte
+	flate  bool
+}
+
+// buildTestPDF writes a PDF with the objects numbered from 1, the first being the catalog, and a valid xref
+func buildTestPDF(t *testing.T, objects []pdfObject) []byte {
+	t.Helper()
+	var data bytes.Buffer
+	data.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = data.Len()
+		fmt.Fprintf(&data, "%d 0 obj\n", i+1)
+		if object.stream == nil {
+			fmt.Fprintf(&data, "%s\nendobj\n", object.dict)
+			continue
+		}
+		stream := object.stream
+		filter := ""
+		if object.flate {
+			var compressed bytes.Buffer
+			writer := zlib.NewWriter(&compressed)
+			if _, err := writer.Write(stream); err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+			stream, filter = compressed.Bytes(), " /Filter /FlateDecode"
+		}
+		fmt.Fprintf(&data, "<< %s /Length %d%s >>\nstream\n", object.dict, len(stream), filter)
+		data.Write(stream)
+		data.WriteString("\nendstream\nendobj\n")
+	}
+	xref := data.Len()
+	fmt.Fprintf(&data, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&data, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&data, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return data.Bytes()
+}
+
+// buildTestDOCX writes a DOCX archive holding the given word/document.xml
+func buildTestDOCX(t *testing.T, document []byte) []byte {
+	t.Helper()
+	var data bytes.Buffer
+	archive := zip.NewWriter(&data)
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", []byte(`<?xml version="1.0"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`)},
+		{"word/document.xml", document},
+	}
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write(file.content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return data.Bytes()
+}
+
+// docxDocument wraps body XML in a word/document.xml
+func docxDocument(body string) []byte {
+	return []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		body + `</w:body></w:document>`)
+}
+
+func TestExtractResumeTextPDF(t *testing.T) {
+	// Page 1 draws WinAnsi text, page 2 draws two-byte codes through a ToUnicode map
+	cmap := []byte("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n" +
+		"2 beginbfchar\n<0001> <0E01>\n<0002> <0E32>\nendbfchar\n" +
+		"1 beginbfrange\n<0010> <0012> <0041>\nendbfrange\n" +
+		"endcmap\nend\nend\n")
+	pdf := buildTestPDF(t, []pdfObject{
+		{dict: "<< /Type /Catalog /Pages 2 0 R >>"},
+		{dict: "<< /Type /Pages /Kids [3 0 R 6 0 R] /Count 2 >>"},
+		{dict: "<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>"},
+		{dict: "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"},
+		{stream: []byte("BT /F1 12 Tf 72 720 Td (Somchai Jaidee) Tj 0 -14 Td [(Software) -300 (Engineer)] TJ ET\n" +
+			"BT 72 690 Td (Email: somchai@example.com) Tj ET"), flate: true},
+		{dict: "<< /Type /Page /Parent 2 0 R /Resources << /Font << /F2 7 0 R >> >> /Contents [8 0 R 9 0 R] >>"},
+		{dict: "<< /Type /Font /Subtype /Type0 /BaseFont /Sarabun /ToUnicode 10 0 R >>"},
+		{stream: []byte("BT /F2 12 Tf 72 720 Td <00010002> Tj ET"), flate: true},
+		{stream: []byte("BT /F2 12 Tf 72 700 Td <001000110012> Tj ET")},
+		{stream: cmap, flate: true},
+	})
+
+	text, err := ExtractResumeText(ResumeContentTypePDF, pdf)
+	if err != nil {
+		t.Fatalf("ExtractResumeText() error = %v", err)
+	}
+	want := "Somchai Jaidee\nSoftware Engineer\nEmail: somchai@example.com\nกา\nABC"
+	if text != want {
+		t.Errorf("ExtractResumeText() = %q, want %q", text, want)
+	}
+}
+
+func TestExtractResumeTextDOCX(t *testing.T) {
+	docx := buildTestDOCX(t, docxDocument(
+		`<w:p><w:r><w:t>Somchai</w:t></w:r><w:r><w:t xml:space="preserve"> Jaidee</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>Skills:</w:t><w:tab/><w:t>Go, SQL &amp; Docker</w:t></w:r></w:p>`+
+			`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>2019</w:t></w:r></w:p></w:tc>`+
+			`<w:tc><w:p><w:r><w:t>มหาวิทยาลัยเกษตรศาสตร์</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`+
+			`<w:p><w:r><w:t>Line one</w:t><w:br/><w:t>Line two</w:t></w:r></w:p>`))
+
+	text, err := ExtractResumeText(ResumeContentTypeDOCX, docx)
+	if err != nil {
+		t.Fatalf("ExtractResumeText() error = %v", err)
+	}
+	want := "Somchai Jaidee\nSkills: Go, SQL & Docker\n2019\nมหาวิทยาลัยเกษตรศาสตร์\nLine one\nLine two"
+	if text != want {
+		t.Errorf("ExtractResumeText() = %q, want %q", text, want)
+	}
+}
+
+func TestExtractResumeTextErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+		want        error
+	}{
+		{"unknown type", "text/plain", []byte("Somchai"), ErrResumeFileType},
+		{"not a PDF", ResumeContentTypePDF, []byte("Somchai"), ErrResumeUnreadable},
+		{"not a zip", ResumeContentTypeDOCX, []byte("Somchai"), ErrResumeUnreadable},
+		{"zip without a document", ResumeContentTypeDOCX, func() []byte {
+			var data bytes.Buffer
+			archive := zip.NewWriter(&data)
+			archive.Create("word/styles.xml")
+			archive.Close()
+			return data.Bytes()
+		}(), ErrResumeUnreadable},
+		{"broken XML", ResumeContentTypeDOCX, buildTestDOCX(t, []byte("<w:document><w:body>")), ErrResumeUnreadable},
+		{"empty DOCX", ResumeContentTypeDOCX, buildTestDOCX(t, docxDocument("<w:p/>")), ErrResumeNoText},
+		{"scanned PDF", ResumeContentTypePDF, buildTestPDF(t, []pdfObject{
+			{dict: "<< /Type /Catalog /Pages 2 0 R >>"},
+			{dict: "<< /Type /Pages /Kids [3 0 R] /Count 1 >>"},
+			{dict: "<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>"},
+			{stream: []byte("q 595 0 0 842 0 0 cm /Im1 Do Q"), flate: true},
+		}), ErrResumeNoText},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			text, err := ExtractResumeText(test.contentType, test.data)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("ExtractResumeText() = %q, %v, want %v", text, err, test.want)
+			}
+		})
+	}
+}
+
+func TestExtractResumeTextStopsAtLimit(t *testing.T) {
+	// Both bombs inflate to more than maxResumeTextBytes from well under a megabyte
+	padding := bytes.Repeat([]byte(" "), maxResumeTextBytes+1<<20)
+
+	t.Run("DOCX", func(t *testing.T) {
+		docx := buildTestDOCX(t, docxDocument(`<w:p><w:r><w:t>Somchai</w:t></w:r></w:p>`+string(padding)))
+		if len(docx) > 1<<20 {
+			t.Fatalf("the DOCX is %d bytes, want a small zip bomb", len(docx))
+		}
+		// The document is cut off before its closing tags, so it does not parse
+		if text, err := ExtractResumeText(ResumeContentTypeDOCX, docx); !errors.Is(err, ErrResumeUnreadable) {
+			t.Fatalf("ExtractResumeText() = %d bytes, %v, want %v", len(text), err, ErrResumeUnreadable)
+		}
+	})
+
+	t.Run("PDF", func(t *testing.T) {
+		bomb := append([]byte("BT (Somchai) Tj ET\n"), padding...)
+		pdf := buildTestPDF(t, []pdfObject{
+			{dict: "<< /Type /Catalog /Pages 2 0 R >>"},
+			{dict: "<< /Type /Pages /Kids [3 0 R] /Count 1 >>"},
+			{dict: "<< /Type /Page /Parent 2 0 R /Contents [4 0 R 5 0 R] >>"},
+			{stream: bomb, flate: true},
+			{stream: []byte("BT (Jaidee) Tj ET"), flate: true},
+		})
+		if len(pdf) > 1<<20 {
+			t.Fatalf("the PDF is %d bytes, want a small flate bomb", len(pdf))
+		}
+		// The text before the limit is kept and the streams after it are not inflated
+		text, err := ExtractResumeText(ResumeContentTypePDF, pdf)
+		if err != nil {
+			t.Fatalf("ExtractResumeText() error = %v", err)
+		}
+		if text != "Somchai" {
+			t.Errorf("ExtractResumeText() = %q, want %q", text, "Somchai")
+		}
+	})
+}
+
+func TestCleanResumeText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"collapses spaces", "  Somchai \t  Jaidee  ", "Somchai Jaidee"},
+		{"drops empty lines", "\n\nGo\n \n\nSQL\n", "Go\nSQL"},
+		{"drops control characters", "Go\x00\x07 SQL�", "Go SQL"},
+		{"keeps Thai marks", "ภาษาไทย  ดี", "ภาษาไทย ดี"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := cleanResumeText(test.text); got != test.want {
+				t.Errorf("cleanResumeText(%q) = %q, want %q", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestDecodePDFStrings(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"escapes", `Line\nTab\t\(x\) \\ \101`, "Line\nTab\t(x) \\ A"},
+		{"line continuation", "Soft\\\nware", "Software"},
+		{"UTF-16 with a byte order mark", "\xfe\xff\x0e\x01\x0e\x32", "กา"},
+		{"WinAnsi bullet", "\x95 Go", "• Go"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := decodePDFString(unescapePDFString([]byte(test.data))); got != test.want {
+				t.Errorf("decodePDFString(%q) = %q, want %q", test.data, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// QuestionType is the kind of answer a screening question expects
+type QuestionType string
+
+const (
+	QuestionTypeText           QuestionType = "text"
+	QuestionTypeSingleChoice   QuestionType = "single_choice"
+	QuestionTypeMultipleChoice QuestionType = "multiple_choice"
+	QuestionTypeYesNo          QuestionType = "yes_no"
+	QuestionTypeNumeric        QuestionType = "numeric"
+)
+
+// QuestionTypes lists every valid QuestionType
+var QuestionTypes = []QuestionType{
+	QuestionTypeText, QuestionTypeSingleChoice, QuestionTypeMultipleChoice, QuestionTypeYesNo, QuestionTypeNumeric,
+}
+
+// yesNoOptions are the fixed options of a yes/no question
+var yesNoOptions = []string{"yes", "no"}
+
+// ScreeningQuestion is a question an applicant answers when applying. Choosing any of KnockoutOptions, or a
+// numeric answer outside MinValue–MaxValue, rejects the application automatically.
+type ScreeningQuestion struct {
+	ID              int          `json:"question_id"`
+	Question        string       `json:"question"`
+	Type            QuestionType `json:"type"`
+	Options         []string     `json:"options,omitempty"`
+	Required        bool         `json:"required"`
+	KnockoutOptions []string     `json:"knockout_options,omitempty"`
+	MinValue        *float64     `json:"min_value,omitempty"`
+	MaxValue        *float64     `json:"max_value,omitempty"`
+}
+
+// ScreeningAnswerInput is one answer sent with an application: a string, a number or a list of strings
+type ScreeningAnswerInput struct {
+	QuestionID int             `json:"question_id"`
+	Answer     json.RawMessage `json:"answer"`
+}
+
+// EvaluatedAnswer is an accepted answer in the form it is stored
+type EvaluatedAnswer struct {
+	Question ScreeningQuestion
+	Answer   string
+	Knockout bool
+}
+
+// NormalizeQuestions validates questions as written by an employer and fills in defaults, keyed by
+// "questions[i].field" in the returned errors
+func NormalizeQuestions(questions []ScreeningQuestion) ([]ScreeningQuestion, FieldErrors) {
+	errs := FieldErrors{}
+	normalized := make([]ScreeningQuestion, len(questions))
+	for i, question := range questions {
+		field := func(name string) string { return fmt.Sprintf("questions[%d].%s", i, name) }
+
+		question.Question = strings.TrimSpace(question.Question)
+		if question.Question == "" {
+			errs[field("question")] = "must not be empty"
+		}
+
+		questionType, ok := parseEnum(string(question.Type), QuestionTypes)
+		if !ok {
+			errs[field("type")] = enumMessage(QuestionTypes)
+		}
+		question.Type = questionType
+
+		switch questionType {
+		case QuestionTypeSingleChoice, QuestionTypeMultipleChoice:
+			question.Options = trimOptions(question.Options)
+			if len(question.Options) < 2 {
+				errs[field("options")] = "must list at least two options"
+			}
+		case QuestionTypeYesNo:
+			question.Options = yesNoOptions
+		default:
+			question.Options = nil
+		}
+
+		question.KnockoutOptions = trimOptions(question.KnockoutOptions)
+		for _, option := range question.KnockoutOptions {
+			if _, found := matchOption(option, question.Options); !found {
+				errs[field("knockout_options")] = "must be among the question's options"
+			}
+		}
+		if questionType != QuestionTypeNumeric && (question.MinValue != nil || question.MaxValue != nil) {
+			errs[field("min_value")] = "only applies to numeric questions"
+		}
+		if question.MinValue != nil && question.MaxValue != nil && *question.MinValue > *question.MaxValue {
+			errs[field("max_value")] = "must be greater than or equal to min_value"
+		}
+
+		question.ID = 0
+		normalized[i] = question
+	}
+	return normalized, errs
+}
+
+// EvaluateAnswers checks the answers against the job's questions, returning the answers to store and field
+// errors keyed by "answers.<question_id>". Unanswered optional questions are skipped.
+func EvaluateAnswers(questions []ScreeningQuestion, inputs []ScreeningAnswerInput) ([]EvaluatedAnswer, FieldErrors) {
+	errs := FieldErrors{}
+	byQuestion := make(map[int]json.RawMessage, len(inputs))
+	for _, input := range inputs {
+		byQuestion[input.QuestionID] = input.Answer
+	}
+
+	var evaluated []EvaluatedAnswer
+	for _, question := range questions {
+		field := fmt.Sprintf("answers.%d", question.ID)
+		raw, present := byQuestion[question.ID]
+		delete(byQuestion, question.ID)
+		if !present || len(raw) == 0 || string(raw) == "null" {
+			if question.Required {
+				errs[field] = "is required"
+			}
+			continue
+		}
+
+		answer, knockout, message := evaluateAnswer(question, raw)
+		if message != "" {
+			errs[field] = message
+			continue
+		}
+		if answer == "" {
+			if question.Required {
+				errs[field] = "is required"
+			}
+			continue
+		}
+		evaluated = append(evaluated, EvaluatedAnswer{Question: question, Answer: answer, Knockout: knockout})
+	}
+
+	for questionID := range byQuestion {
+		errs[fmt.Sprintf("answers.%d", questionID)] = "is not a question of this job"
+	}
+	return evaluated, errs
+}
+
+// evaluateAnswer decodes one answer, returning its stored form, whether it knocks the applicant out, and a
+// validation message when it is invalid
+func evaluateAnswer(question ScreeningQuestion, raw json.RawMessage) (string, bool, string) {
+	switch question.Type {
+	case QuestionTypeNumeric:
+		var number float64
+		if err := json.Unmarshal(raw, &number); err != nil {
+			return "", false, "must be a number"
+		}
+		knockout := (question.MinValue != nil && number < *question.MinValue) ||
+			(question.MaxValue != nil && number > *question.MaxValue)
+		return strconv.FormatFloat(number, 'f', -1, 64), knockout, ""
+
+	case QuestionTypeMultipleChoice:
+		var choices []string
+		if err := json.Unmarshal(raw, &choices); err != nil {
+			return "", false, "must be a list of options"
+		}
+		var selected []string
+		knockout := false
+		for _, choice := range trimOptions(choices) {
+			option, found := matchOption(choice, question.Options)
+			if !found {
+				return "", false, "must only contain the question's options"
+			}
+			if _, ko := matchOption(option, question.KnockoutOptions); ko {
+				knockout = true
+			}
+			selected = append(selected, option)
+		}
+		return strings.Join(selected, ", "), knockout, ""
+
+	default:
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return "", false, "must be a string"
+		}
+		text = strings.TrimSpace(text)
+		if question.Type == QuestionTypeText || text == "" {
+			return text, false, ""
+		}
+		option, found := matchOption(text, question.Options)
+		if !found {
+			return "", false, "must be one of: " + strings.Join(question.Options, ", ")
+		}
+		_, knockout := matchOption(option, question.KnockoutOptions)
+		return option, knockout, ""
+	}
+}
+
+// matchOption finds value among options case-insensitively, returning the option as written
+func matchOption(value string, options []string) (string, bool) {
+	for _, option := range options {
+		if strings.EqualFold(strings.TrimSpace(value), option) {
+			return option, true
+		}
+	}
+	return "", false
+}
+
+// trimOptions trims options and drops empty and duplicate ones
+func trimOptions(options []string) []string {
+	var trimmed []string
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if _, duplicate := matchOption(option, trimmed); option != "" && !duplicate {
+			trimmed = append(trimmed, option)
+		}
+	}
+	return trimmed
+}
+
+// LoadScreeningQuestions reads the questions of a job in order
+func LoadScreeningQuestions(q Querier, jobID interface{}) ([]ScreeningQuestion, error) {
+	query := "SELECT question_id, question, type, options, required, knockout_options, min_value, max_value " +
+		"FROM screening_questions WHERE job_id = ? ORDER BY position"
+	rows, err := q.Query(query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying screening questions: %v", err)
+	}
+	defer rows.Close()
+
+	questions := []ScreeningQuestion{}
+	for rows.Next() {
+		var question ScreeningQuestion
+		var options, knockoutOptions sql.NullString
+		if err := rows.Scan(&question.ID, &question.Question, &question.Type, &options, &question.Required,
+			&knockoutOptions, &question.MinValue, &question.MaxValue); err != nil {
+			return nil, fmt.Errorf("error scanning screening question: %v", err)
+		}
+		if options.Valid {
+			if err := json.Unmarshal([]byte(options.String), &question.Options); err != nil {
+				return nil, fmt.Errorf("error decoding options of screening question %d: %v", question.ID, err)
+			}
+		}
+		if knockoutOptions.Valid {
+			if err := json.Unmarshal([]byte(knockoutOptions.String), &question.KnockoutOptions); err != nil {
+				return nil, fmt.Errorf("error decoding knockout options of screening question %d: %v", question.ID, err)
+			}
+		}
+		questions = append(questions, question)
+	}
+	return questions, rows.Err()
+}
+
+// ReplaceScreeningQuestions swaps the questions of a job for questions, which must be normalized
+func ReplaceScreeningQuestions(exec Execer, jobID interface{}, questions []ScreeningQuestion) error {
+	if _, err := exec.Exec("DELETE FROM screening_questions WHERE job_id = ?", jobID); err != nil {
+		return fmt.Errorf("error clearing screening questions: %v", err)
+	}
+
+	query := "INSERT INTO screening_questions (job_id, position, question, type, options, required, knockout_options, " +
+		"min_value, max_value) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	for i, question := range questions {
+		if _, err := exec.Exec(query, jobID, i+1, question.Question, string(question.Type), jsonList(question.Options),
+			question.Required, jsonList(question.KnockoutOptions), question.MinValue, question.MaxValue); err != nil {
+			return fmt.Errorf("error inserting screening question: %v", err)
+		}
+	}
+	return nil
+}
+
+// jsonList encodes a list for a JSON column, or NULL when empty
+func jsonList(values []string) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeQuestions(t *testing.T) {
+	floatp := func(value float64) *float64 { return &value }
+
+	tests := []struct {
+		name     string
+		question ScreeningQuestion
+		want     ScreeningQuestion
+		errs     FieldErrors
+	}{
+		{
+			name: "single choice trims options",
+			question: ScreeningQuestion{ID: 7, Question: " Where do you live? ", Type: "Single_Choice",
+				Options: []string{" Bangkok ", "bangkok", "", "Chiang Mai"}, KnockoutOptions: []string{" chiang mai", ""}},
+			want: ScreeningQuestion{Question: "Where do you live?", Type: QuestionTypeSingleChoice,
+				Options: []string{"Bangkok", "Chiang Mai"}, KnockoutOptions: []string{"chiang mai"}},
+		},
+		{
+			name:     "yes/no has fixed options",
+			question: ScreeningQuestion{Question: "Can you relocate?", Type: "yes_no", Options: []string{"maybe"}, KnockoutOptions: []string{"No"}},
+			want:     ScreeningQuestion{Question: "Can you relocate?", Type: QuestionTypeYesNo, Options: yesNoOptions, KnockoutOptions: []string{"No"}},
+		},
+		{
+			name:     "text drops options",
+			question: ScreeningQuestion{Question: "Why us?", Type: "text", Options: []string{"a", "b"}, Required: true},
+			want:     ScreeningQuestion{Question: "Why us?", Type: QuestionTypeText, Required: true},
+		},
+		{
+			name:     "numeric bounds",
+			question: ScreeningQuestion{Question: "Years of Go?", Type: "numeric", MinValue: floatp(1), MaxValue: floatp(1)},
+			want:     ScreeningQuestion{Question: "Years of Go?", Type: QuestionTypeNumeric, MinValue: floatp(1), MaxValue: floatp(1)},
+		},
+		{
+			name:     "numeric minimum above maximum",
+			question: ScreeningQuestion{Question: "Years of Go?", Type: "numeric", MinValue: floatp(3), MaxValue: floatp(2)},
+			errs:     FieldErrors{"questions[0].max_value": "must be greater than or equal to min_value"},
+		},
+		{
+			name:     "bounds on a text question",
+			question: ScreeningQuestion{Question: "Why us?", Type: "text", MaxValue: floatp(2)},
+			errs:     FieldErrors{"questions[0].min_value": "only applies to numeric questions"},
+		},
+		{
+			name:     "choice with one option",
+			question: ScreeningQuestion{Question: "Pick one", Type: "multiple_choice", Options: []string{"Go", " go "}},
+			errs:     FieldErrors{"questions[0].options": "must list at least two options"},
+		},
+		{
+			name:     "knockout outside the options",
+			question: ScreeningQuestion{Question: "Pick one", Type: "single_choice", Options: []string{"Go", "SQL"}, KnockoutOptions: []string{"PHP"}},
+			errs:     FieldErrors{"questions[0].knockout_options": "must be among the question's options"},
+		},
+		{
+			name:     "empty question of an unknown type",
+			question: ScreeningQuestion{Question: "  ", Type: "essay"},
+			errs:     FieldErrors{"questions[0].question": "must not be empty", "questions[0].type": enumMessage(QuestionTypes)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			normalized, errs := NormalizeQuestions([]ScreeningQuestion{test.question})
+
+			wantErrs := test.errs
+			if wantErrs == nil {
+				wantErrs = FieldErrors{}
+			}
+			if !reflect.DeepEqual(errs, wantErrs) {
+				t.Fatalf("NormalizeQuestions() errors = %v, want %v", errs, wantErrs)
+			}
+			if len(wantErrs) > 0 {
+				return
+			}
+			if !reflect.DeepEqual(normalized, []ScreeningQuestion{test.want}) {
+				t.Errorf("NormalizeQuestions() = %+v, want %+v", normalized, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateAnswer(t *testing.T) {
+	floatp := func(value float64) *float64 { return &value }
+
+	city := ScreeningQuestion{Type: QuestionTypeSingleChoice, Options: []string{"Bangkok", "Chiang Mai"}, KnockoutOptions: []string{"chiang mai"}}
+	skills := ScreeningQuestion{Type: QuestionTypeMultipleChoice, Options: []string{"Go", "SQL", "PHP"}, KnockoutOptions: []string{"PHP"}}
+	relocate := ScreeningQuestion{Type: QuestionTypeYesNo, Options: yesNoOptions, KnockoutOptions: []string{"no"}}
+	years := ScreeningQuestion{Type: QuestionTypeNumeric, MinValue: floatp(1), MaxValue: floatp(5)}
+	text := ScreeningQuestion{Type: QuestionTypeText}
+
+	tests := []struct {
+		name     string
+		question ScreeningQuestion
+		raw      string
+		answer   string
+		knockout bool
+		message  string
+	}{
+		{"text is trimmed", text, `"  Hello  "`, "Hello", false, ""},
+		{"text must be a string", text, `5`, "", false, "must be a string"},
+		{"choice as written", city, `" bangkok "`, "Bangkok", false, ""},
+		{"choice knockout", city, `"CHIANG MAI"`, "Chiang Mai", true, ""},
+		{"choice outside the options", city, `"Phuket"`, "", false, "must be one of: Bangkok, Chiang Mai"},
+		{"blank choice", city, `" "`, "", false, ""},
+		{"yes", relocate, `"Yes"`, "yes", false, ""},
+		{"no knocks out", relocate, `"no"`, "no", true, ""},
+		{"multiple choices", skills, `["sql", " Go", "go", ""]`, "SQL, Go", false, ""},
+		{"multiple choices with a knockout", skills, `["Go", "php"]`, "Go, PHP", true, ""},
+		{"multiple choices outside the options", skills, `["Go", "Rust"]`, "", false, "must only contain the question's options"},
+		{"multiple choices as a string", skills, `"Go"`, "", false, "must be a list of options"},
+		{"number within the bounds", years, `2.5`, "2.5", false, ""},
+		{"number at the minimum", years, `1`, "1", false, ""},
+		{"number at the maximum", years, `5`, "5", false, ""},
+		{"number below the minimum", years, `0`, "0", true, ""},
+		{"number above the maximum", years, `5.5`, "5.5", true, ""},
+		{"number without bounds", ScreeningQuestion{Type: QuestionTypeNumeric}, `-3`, "-3", false, ""},
+		{"number as a string", years, `"3"`, "", false, "must be a number"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			answer, knockout, message := evaluateAnswer(test.question, json.RawMessage(test.raw))
+			if answer != test.answer || knockout != test.knockout || message != test.message {
+				t.Errorf("evaluateAnswer(%s) = %q, %v, %q, want %q, %v, %q",
+					test.raw, answer, knockout, message, test.answer, test.knockout, test.message)
+			}
+		})
+	}
+}
+
+func TestEvaluateAnswers(t *testing.T) {
+	questions := []ScreeningQuestion{
+		{ID: 1, Question: "Why us?", Type: QuestionTypeText, Required: true},
+		{ID: 2, Question: "Can you relocate?", Type: QuestionTypeYesNo, Options: yesNoOptions, KnockoutOptions: []string{"no"}},
+		{ID: 3, Question: "Anything else?", Type: QuestionTypeText},
+	}
+	answer := func(questionID int, raw string) ScreeningAnswerInput {
+		return ScreeningAnswerInput{QuestionID: questionID, Answer: json.RawMessage(raw)}
+	}
+
+	tests := []struct {
+		name   string
+		inputs []ScreeningAnswerInput
+		want   []EvaluatedAnswer
+		errs   FieldErrors
+	}{
+		{
+			name:   "every question answered",
+			inputs: []ScreeningAnswerInput{answer(3, `"No"`), answer(2, `"NO"`), answer(1, `"Growth"`)},
+			want: []EvaluatedAnswer{
+				{Question: questions[0], Answer: "Growth"},
+				{Question: questions[1], Answer: "no", Knockout: true},
+				{Question: questions[2], Answer: "No"},
+			},
+		},
+		{
+			name:   "optional questions skipped",
+			inputs: []ScreeningAnswerInput{answer(1, `"Growth"`), answer(2, `null`), answer(3, `"  "`)},
+			want:   []EvaluatedAnswer{{Question: questions[0], Answer: "Growth"}},
+		},
+		{
+			name:   "required question missing",
+			inputs: []ScreeningAnswerInput{answer(2, `"yes"`)},
+			want:   []EvaluatedAnswer{{Question: questions[1], Answer: "yes"}},
+			errs:   FieldErrors{"answers.1": "is required"},
+		},
+		{
+			name:   "required question null",
+			inputs: []ScreeningAnswerInput{answer(1, `null`)},
+			errs:   FieldErrors{"answers.1": "is required"},
+		},
+		{
+			name:   "required question blank",
+			inputs: []ScreeningAnswerInput{answer(1, `" "`)},
+			errs:   FieldErrors{"answers.1": "is required"},
+		},
+		{
+			name:   "invalid answer",
+			inputs: []ScreeningAnswerInput{answer(1, `"Growth"`), answer(2, `"maybe"`)},
+			want:   []EvaluatedAnswer{{Question: questions[0], Answer: "Growth"}},
+			errs:   FieldErrors{"answers.2": "must be one of: yes, no"},
+		},
+		{
+			name:   "unknown question",
+			inputs: []ScreeningAnswerInput{answer(1, `"Growth"`), answer(99, `"yes"`)},
+			want:   []EvaluatedAnswer{{Question: questions[0], Answer: "Growth"}},
+			errs:   FieldErrors{"answers.99": "is not a question of this job"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			evaluated, errs := EvaluateAnswers(questions, test.inputs)
+
+			wantErrs := test.errs
+			if wantErrs == nil {
+				wantErrs = FieldErrors{}
+			}
+			if !reflect.DeepEqual(errs, wantErrs) {
+				t.Errorf("EvaluateAnswers() errors = %v, want %v", errs, wantErrs)
+			}
+			if !reflect.DeepEqual(evaluated, test.want) {
+				t.Errorf("EvaluateAnswers() = %+v, want %+v", evaluated, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Querier is implemented by both *sql.DB and *sql.Tx
+type Querier interface {
+	Execer
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Skill is an entry of the skills catalogue
+type Skill struct {
+	ID     int    `json:"skill_id"`
+	Name   string `json:"name"`
+	NameTH string `json:"name_th"`
+
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// SkillSnapshotQuery reads a skill with its aliases for audit snapshots
+const SkillSnapshotQuery = "SELECT s.skill_id, s.name, s.name_th, " +
+	"(SELECT GROUP_CONCAT(a.alias ORDER BY a.alias SEPARATOR ', ') FROM skill_aliases a WHERE a.skill_id = s.skill_id) AS aliases " +
+	"FROM skills s WHERE s.skill_id = ?"
+
+// NormalizeSkillName trims a skill name and collapses inner whitespace
+func NormalizeSkillName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// ResolveSkills maps names, Thai names or aliases to catalogue skills (case-insensitively), returning
+// the matched skills without duplicates and the names that matched nothing
+func ResolveSkills(q Querier, names []string) ([]Skill, []string, error) {
+	query := "SELECT s.skill_id, s.name, s.name_th FROM skills s WHERE s.name = ? OR s.name_th = ? " +
+		"UNION SELECT s.skill_id, s.name, s.name_th FROM skill_aliases a " +
+		"INNER JOIN skills s ON s.skill_id = a.skill_id WHERE a.alias = ? LIMIT 1"
+
+	var skills []Skill
+	var unknown []string
+	seen := make(map[int]bool)
+	for _, name := range names {
+		name = NormalizeSkillName(name)
+		if name == "" {
+			continue
+		}
+
+		var skill Skill
+		err := q.QueryRow(query, name, name, name).Scan(&skill.ID, &skill.Name, &skill.NameTH)
+		if err == sql.ErrNoRows {
+			unknown = append(unknown, name)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("error resolving skill %q: %v", name, err)
+		}
+
+		if !seen[skill.ID] {
+			seen[skill.ID] = true
+			skills = append(skills, skill)
+		}
+	}
+	return skills, unknown, nil
+}
+
+// SkillIDs returns the IDs of skills
+func SkillIDs(skills []Skill) []int {
+	ids := make([]int, len(skills))
+	for i, skill := range skills {
+		ids[i] = skill.ID
+	}
+	return ids
+}
+
+// SkillNames returns the canonical names of skills joined for display, e.g. in jobs.skills_required
+func SkillNames(skills []Skill) string {
+	names := make([]string, len(skills))
+	for i, skill := range skills {
+		names[i] = skill.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// SetJobSkills replaces the skills linked to a job
+func SetJobSkills(exec Execer, jobID interface{}, skillIDs []int) error {
+	return replaceSkillLinks(exec, "job_skills", "job_id", jobID, skillIDs)
+}
+
+// SetProfileSkills replaces the skills linked to a fresh grad profile
+func SetProfileSkills(exec Execer, profileID interface{}, skillIDs []int) error {
+	return replaceSkillLinks(exec, "freshgradprofile_skills", "freshgradprofile_id", profileID, skillIDs)
+}
+
+// replaceSkillLinks deletes and re-inserts the skill links of one owner row
+func replaceSkillLinks(exec Execer, table, ownerColumn string, ownerID interface{}, skillIDs []int) error {
+	if _, err := exec.Exec("DELETE FROM "+table+" WHERE "+ownerColumn+" = ?", ownerID); err != nil {
+		return fmt.Errorf("error clearing %s: %v", table, err)
+	}
+	if len(skillIDs) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("(?, ?), ", len(skillIDs)), ", ")
+	args := make([]interface{}, 0, 2*len(skillIDs))
+	for _, skillID := range skillIDs {
+		args = append(args, ownerID, skillID)
+	}
+	if _, err := exec.Exec("INSERT INTO "+table+" ("+ownerColumn+", skill_id) VALUES "+placeholders, args...); err != nil {
+		return fmt.Errorf("error inserting %s: %v", table, err)
+	}
+	return nil
+}
+
+// SkillFilterCondition returns a condition requiring the owner row to have any (or, with matchAll, every)
+// one of the skills. linkTable/ownerColumn name the link table and ownerRef the owner ID column to match.
+func SkillFilterCondition(linkTable, ownerColumn, ownerRef string, skillIDs []int, matchAll bool) (string, []interface{}) {
+	placeholders := "?" + strings.Repeat(", ?", len(skillIDs)-1)
+	args := make([]interface{}, 0, len(skillIDs)+1)
+	for _, skillID := range skillIDs {
+		args = append(args, skillID)
+	}
+
+	subquery := "SELECT COUNT(DISTINCT sf.skill_id) FROM " + linkTable + " sf WHERE sf." + ownerColumn + " = " + ownerRef +
+		" AND sf.skill_id IN (" + placeholders + ")"
+	if matchAll {
+		args = append(args, len(skillIDs))
+		return "(" + subquery + ") = ?", args
+	}
+	return "(" + subquery + ") > 0", args
+}
+
+// SplitSkillsParam splits a comma-separated skills query parameter
+func SplitSkillsParam(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = NormalizeSkillName(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// SplitSkillsText splits free-text skills, as in jobs.skills_required, on commas, semicolons, slashes, pipes
+// and line breaks. Migration 022 splits the same way when linking older jobs.
+func SplitSkillsText(text string) []string {
+	var names []string
+	for _, name := range strings.FieldsFunc(text, func(r rune) bool { return strings.ContainsRune(",;/|\r\n", r) }) {
+		if name = NormalizeSkillName(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// JobSkills loads the catalogue skills of the given jobs, keyed by job ID
+func JobSkills(q Querier, jobIDs []string) (map[string][]Skill, error) {
+	result := make(map[string][]Skill)
+	if len(jobIDs) == 0 {
+		return result, nil
+	}
+
+	args := make([]interface{}, len(jobIDs))
+	for i, id := range jobIDs {
+		args[i] = id
+	}
+	query := "SELECT js.job_id, s.skill_id, s.name, s.name_th FROM job_skills js " +
+		"INNER JOIN skills s ON s.skill_id = js.skill_id WHERE js.job_id IN (?" + strings.Repeat(", ?", len(jobIDs)-1) + ") " +
+		"ORDER BY s.name"
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying job skills: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jobID string
+		var skill Skill
+		if err := rows.Scan(&jobID, &skill.ID, &skill.Name, &skill.NameTH); err != nil {
+			return nil, fmt.Errorf("error scanning job skill: %v", err)
+		}
+		result[jobID] = append(result[jobID], skill)
+	}
+	return result, rows.Err()
+}
+
+// ListSkills returns the catalogue with aliases, optionally filtered by a search term matching any name or alias
+func ListSkills(q Querier, search string) ([]Skill, error) {
+	query := "SELECT s.skill_id, s.name, s.name_th, " +
+		"COALESCE((SELECT GROUP_CONCAT(a.alias ORDER BY a.alias SEPARATOR '\\n') FROM skill_aliases a WHERE a.skill_id = s.skill_id), '') " +
+		"FROM skills s"
+	var args []interface{}
+	if search = NormalizeSkillName(search); search != "" {
+		pattern := "%" + search + "%"
+		query += " WHERE s.name LIKE ? OR s.name_th LIKE ? " +
+			"OR EXISTS (SELECT 1 FROM skill_aliases a WHERE a.skill_id = s.skill_id AND a.alias LIKE ?)"
+		args = append(args, pattern, pattern, pattern)
+	}
+	query += " ORDER BY s.name"
+
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying skills: %v", err)
+	}
+	defer rows.Close()
+
+	var skills []Skill
+	for rows.Next() {
+		var skill Skill
+		var aliases string
+		if err := rows.Scan(&skill.ID, &skill.Name, &skill.NameTH, &aliases); err != nil {
+			return nil, fmt.Errorf("error scanning skill: %v", err)
+		}
+		if aliases != "" {
+			skill.Aliases = strings.Split(aliases, "\n")
+		}
+		skills = append(skills, skill)
+	}
+	return skills, rows.Err()
+}
+
+// SkillNameTaken reports whether name is already used as a skill name or alias, ignoring the skill excludeID
+func SkillNameTaken(q Querier, name string, excludeID interface{}) (bool, error) {
+	var taken bool
+	query := "SELECT EXISTS (SELECT 1 FROM skills WHERE name = ? AND skill_id <> ?) " +
+		"OR EXISTS (SELECT 1 FROM skill_aliases WHERE alias = ?)"
+	if err := q.QueryRow(query, name, excludeID, name).Scan(&taken); err != nil {
+		return false, fmt.Errorf("error checking skill name: %v", err)
+	}
+	return taken, nil
+}
+
+// SkillFilter turns a comma-separated skills query parameter into a SkillFilterCondition. match is "all" to
+// require every skill, anything else to require any one. It returns an empty condition when param is empty
+// and an always-false one when no listed skill (or, for "all", some listed skill) is in the catalogue.
+func SkillFilter(q Querier, param, match, linkTable, ownerColumn, ownerRef string) (string, []interface{}, error) {
+	skillIDs, matchAll, condition, err := resolveSkillFilter(q, param, match)
+	if err != nil || condition != "" {
+		return condition, nil, err
+	}
+	if skillIDs == nil {
+		return "", nil, nil
+	}
+
+	condition, args := SkillFilterCondition(linkTable, ownerColumn, ownerRef, skillIDs, matchAll)
+	return condition, args, nil
+}
+
+// resolveSkillFilter resolves the skills query parameter of SkillFilter. It returns nil IDs when param is empty
+// and the always-false condition "FALSE" when nothing can match.
+func resolveSkillFilter(q Querier, param, match string) ([]int, bool, string, error) {
+	names := SplitSkillsParam(param)
+	if len(names) == 0 {
+		return nil, false, "", nil
+	}
+
+	skills, unknown, err := ResolveSkills(q, names)
+	if err != nil {
+		return nil, false, "", err
+	}
+	matchAll := strings.EqualFold(match, "all")
+	if len(skills) == 0 || (matchAll && len(unknown) > 0) {
+		return nil, matchAll, "FALSE", nil
+	}
+	return SkillIDs(skills), matchAll, "", nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Storage keeps uploaded files under opaque keys
+type Storage interface {
+	// Save stores the content of r and returns its key and size in bytes
+	Save(r io.Reader) (string, int64, error)
+	// Open reads a stored file; the caller closes it
+	Open(key string) (io.ReadCloser, error)
+	// Delete removes a stored file; deleting a missing file is not an error
+	Delete(key string) error
+}
+
+// ErrStorageKeyInvalid is returned for keys that Save could not have produced
+var ErrStorageKeyInvalid = errors.New("invalid storage key")
+
+// storageKeyPattern matches the keys produced by LocalStorage.Save
+var storageKeyPattern = regexp.MustCompile(`^[0-9a-f]{2}/[0-9a-f]{32}$`)
+
+// LocalStorage stores files on the local disk below Dir, sharded by the first byte of the key
+type LocalStorage struct {
+	Dir string
+}
+
+// NewStorage returns the file storage configured by STORAGE_DIR, "storage" by default
+func NewStorage() (Storage, error) {
+	dir := os.Getenv("STORAGE_DIR")
+	if dir == "" {
+		dir = "storage"
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("error creating storage directory: %v", err)
+	}
+	return LocalStorage{Dir: dir}, nil
+}
+
+// Save writes r to a new file through a temporary file, so a failed upload never leaves a partial file
+func (storage LocalStorage) Save(r io.Reader) (string, int64, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", 0, fmt.Errorf("error generating storage key: %v", err)
+	}
+	name := hex.EncodeToString(id)
+	key := name[:2] + "/" + name
+
+	path := storage.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", 0, fmt.Errorf("error creating storage directory: %v", err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), name+".*.tmp")
+	if err != nil {
+		return "", 0, fmt.Errorf("error creating file: %v", err)
+	}
+	defer os.Remove(file.Name())
+
+	size, err := io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("error writing file: %v", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return "", 0, fmt.Errorf("error storing file: %v", err)
+	}
+	return key, size, nil
+}
+
+// Open opens a stored file for reading
+func (storage LocalStorage) Open(key string) (io.ReadCloser, error) {
+	if !storageKeyPattern.MatchString(key) {
+		return nil, ErrStorageKeyInvalid
+	}
+	return os.Open(storage.path(key))
+}
+
+// Delete removes a stored file
+func (storage LocalStorage) Delete(key string) error {
+	if !storageKeyPattern.MatchString(key) {
+		return ErrStorageKeyInvalid
+	}
+	if err := os.Remove(storage.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error deleting file: %v", err)
+	}
+	return nil
+}
+
+// path is where the file of key lives on disk
+func (storage LocalStorage) path(key string) string {
+	return filepath.Join(storage.Dir, filepath.FromSlash(key))
+}
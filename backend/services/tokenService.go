@@ -1,7 +1,9 @@
 package services
 
 import (
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"os"
@@ -120,3 +122,12 @@ func ValidateJWT(tokenString string) (*JWTClaims, error) {
 	}
 	return nil, fmt.Errorf("invalid token")
 }
+
+// GenerateRandomToken returns a random hex token of n bytes, e.g. for invitation links
+func GenerateRandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating random token: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
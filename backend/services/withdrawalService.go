@@ -0,0 +1,141 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Withdrawal: fresh grads withdraw their own applications, which closes what is still in progress for them,
+// and may apply again to the same job, or to the same employer, only after the reapply cooldown.
+
+// ReapplyScope is what a withdrawal blocks reapplying to during the cooldown
+type ReapplyScope string
+
+const (
+	// ReapplyScopeJob blocks reapplying to the withdrawn job only
+	ReapplyScopeJob ReapplyScope = "job"
+	// ReapplyScopeEmployer blocks applying to any job of the same company, or of the same employer
+	// for jobs without a company
+	ReapplyScopeEmployer ReapplyScope = "employer"
+)
+
+// ReapplyScopes lists every ReapplyScope
+var ReapplyScopes = []ReapplyScope{ReapplyScopeJob, ReapplyScopeEmployer}
+
+// MaxWithdrawReasonLength caps the reason given when withdrawing
+const MaxWithdrawReasonLength = 1000
+
+// ReapplyPolicyConfig is the cooldown after a withdrawal; zero days disables it
+type ReapplyPolicyConfig struct {
+	CooldownDays int
+	Scope        ReapplyScope
+}
+
+// LoadReapplyPolicyConfig reads REAPPLY_COOLDOWN_DAYS (default 30) and REAPPLY_COOLDOWN_SCOPE (job or
+// employer, default job)
+func LoadReapplyPolicyConfig() ReapplyPolicyConfig {
+	config := ReapplyPolicyConfig{CooldownDays: 30, Scope: ReapplyScopeJob}
+
+	if value := os.Getenv("REAPPLY_COOLDOWN_DAYS"); value != "" {
+		if days, err := strconv.Atoi(value); err == nil && days >= 0 {
+			config.CooldownDays = days
+		} else {
+			log.Printf("Warning: invalid REAPPLY_COOLDOWN_DAYS %q, using %d", value, config.CooldownDays)
+		}
+	}
+
+	if value := os.Getenv("REAPPLY_COOLDOWN_SCOPE"); value != "" {
+		if scope, ok := parseEnum(value, ReapplyScopes); ok {
+			config.Scope = scope
+		} else {
+			log.Printf("Warning: invalid REAPPLY_COOLDOWN_SCOPE %q, using %s", value, config.Scope)
+		}
+	}
+
+	return config
+}
+
+// ReapplyPolicy is the reapply cooldown in effect, read once at startup
+var ReapplyPolicy = LoadReapplyPolicyConfig()
+
+// ReapplyAvailableAt returns when the profile may apply to the job again under config, as an RFC 3339 time,
+// or "" when no recent withdrawal blocks it
+func ReapplyAvailableAt(q Querier, config ReapplyPolicyConfig, profileID int, jobID interface{}) (string, error) {
+	if config.CooldownDays == 0 {
+		return "", nil
+	}
+
+	scopeCondition := "a.job_id = t.job_id"
+	if config.Scope == ReapplyScopeEmployer {
+		scopeCondition = "(j.company_id = t.company_id OR (t.company_id IS NULL AND j.employer_id = t.employer_id))"
+	}
+	query := "SELECT MAX(a.withdrawn_at) + INTERVAL ? DAY FROM applications a " +
+		"INNER JOIN jobs j ON j.job_id = a.job_id " +
+		"INNER JOIN jobs t ON t.job_id = ? " +
+		"WHERE a.freshgradprofile_id = ? AND a.status = ? AND " + scopeCondition +
+		" AND a.withdrawn_at > UTC_TIMESTAMP() - INTERVAL ? DAY"
+	var availableAt sql.NullString
+	err := q.QueryRow(query, config.CooldownDays, jobID, profileID, string(ApplicationStatusWithdrawn), config.CooldownDays).Scan(&availableAt)
+	if err != nil {
+		return "", fmt.Errorf("error checking reapply cooldown: %v", err)
+	}
+	if !availableAt.Valid {
+		return "", nil
+	}
+	return formatInterviewTime(availableAt.String), nil
+}
+
+// ApplicationWithdrawal is the result of WithdrawApplication
+type ApplicationWithdrawal struct {
+	From       ApplicationStatus
+	JobID      int
+	JobTitle   string
+	EmployerID int
+}
+
+// WithdrawApplication withdraws an application inside tx on behalf of its applicant, recording the reason
+// and the change in application_status_history. Interviews still being arranged or scheduled are declined, as
+// when the candidate closes one themselves, and pending offers rescinded, so they do not count as declined in
+// the acceptance rate. Rejected, hired and already withdrawn applications cannot be withdrawn.
+func WithdrawApplication(tx *sql.Tx, applicationID, applicantUserID interface{}, reason string) (ApplicationWithdrawal, error) {
+	var withdrawal ApplicationWithdrawal
+	query := "SELECT a.status, a.job_id, j.title, j.employer_id FROM applications a " +
+		"INNER JOIN jobs j ON j.job_id = a.job_id WHERE a.application_id = ? FOR UPDATE"
+	err := tx.QueryRow(query, applicationID).Scan(&withdrawal.From, &withdrawal.JobID, &withdrawal.JobTitle, &withdrawal.EmployerID)
+	if err == sql.ErrNoRows {
+		return withdrawal, ErrApplicationNotFound
+	}
+	if err != nil {
+		return withdrawal, fmt.Errorf("error loading application: %v", err)
+	}
+	switch withdrawal.From {
+	case ApplicationStatusWithdrawn:
+		return withdrawal, ErrApplicationStatusSame
+	case ApplicationStatusHired, ApplicationStatusRejected:
+		return withdrawal, ErrApplicationStatusLocked
+	}
+
+	reason = strings.TrimSpace(reason)
+	updateQuery := "UPDATE applications SET status = ?, withdrawn_at = UTC_TIMESTAMP(), withdraw_reason = ? WHERE application_id = ?"
+	if _, err := tx.Exec(updateQuery, string(ApplicationStatusWithdrawn), nullString(reason), applicationID); err != nil {
+		return withdrawal, fmt.Errorf("error withdrawing application: %v", err)
+	}
+	if err := RecordStatusHistory(tx, applicationID, withdrawal.From, ApplicationStatusWithdrawn, applicantUserID, reason); err != nil {
+		return withdrawal, err
+	}
+
+	interviewQuery := "UPDATE interviews SET status = ?, close_reason = ? WHERE application_id = ? AND status IN (?, ?, ?)"
+	if _, err := tx.Exec(interviewQuery, string(InterviewStatusDeclined), "Application withdrawn", applicationID,
+		string(InterviewStatusProposed), string(InterviewStatusCounterProposed), string(InterviewStatusScheduled)); err != nil {
+		return withdrawal, fmt.Errorf("error closing interviews: %v", err)
+	}
+	offerQuery := "UPDATE offers SET status = ?, response_note = ?, responded_at = UTC_TIMESTAMP() WHERE application_id = ? AND status = ?"
+	if _, err := tx.Exec(offerQuery, string(OfferStatusRescinded), "Application withdrawn", applicationID, string(OfferStatusPending)); err != nil {
+		return withdrawal, fmt.Errorf("error rescinding offers: %v", err)
+	}
+	return withdrawal, nil
+}